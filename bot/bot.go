@@ -0,0 +1,198 @@
+// Package bot implements a computer connect 4 player on top of the engine package.
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/geofpwhite/connect4-grpc/engine"
+)
+
+const (
+	MinLevel     = 1
+	MaxLevel     = 5
+	DefaultLevel = 3
+
+	winScore = 1 << 30
+	// maxNodes and moveTime bound the work done for a single move so large boards stay responsive.
+	maxNodes = 2_000_000
+	moveTime = 2 * time.Second
+)
+
+var ErrInvalidLevel = errors.New("invalid bot level")
+
+// levels maps a difficulty to a search depth and the odds of playing a random move instead of the best one.
+var levels = [MaxLevel + 1]struct {
+	depth      int
+	randomness float64
+}{
+	1: {1, 0.5},
+	2: {2, 0.25},
+	3: {4, 0.1},
+	4: {6, 0},
+	5: {12, 0},
+}
+
+type Player struct {
+	level int
+	rng   *rand.Rand
+}
+
+// New returns a player of the given level, from MinLevel (mostly random) to MaxLevel (deep search).
+func New(level int) (*Player, error) {
+	if level < MinLevel || level > MaxLevel {
+		return nil, fmt.Errorf("%w: must be between %d and %d, got %d", ErrInvalidLevel, MinLevel, MaxLevel, level)
+	}
+	return &Player{level: level, rng: rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))}, nil //nolint:gosec // game play
+}
+
+func (p *Player) Level() int { return p.level }
+
+// Move picks a 0 based column for the side to move, -1 if the game is over. The board is left untouched.
+func (p *Player) Move(b *engine.Board) int {
+	legal := b.LegalMoves()
+	if len(legal) == 0 {
+		return -1
+	}
+	if p.rng.Float64() < levels[p.level].randomness {
+		return legal[p.rng.IntN(len(legal))]
+	}
+	s := newSearch(b, time.Now().Add(moveTime))
+	best := s.order()[0]
+	for depth := 1; depth <= levels[p.level].depth; depth++ {
+		move, score, complete := s.root(depth)
+		if !complete {
+			break
+		}
+		best = move
+		if score >= winScore-depth || score <= -winScore+depth {
+			break // forced result, searching deeper won't change it
+		}
+	}
+	return best
+}
+
+type search struct {
+	board    *engine.Board
+	columns  []int // from the center outwards, central columns being part of more lines
+	deadline time.Time
+	nodes    int
+	aborted  bool
+}
+
+func newSearch(b *engine.Board, deadline time.Time) *search {
+	columns := make([]int, b.Cols())
+	for i := range columns {
+		columns[i] = i
+	}
+	distance := func(c int) int { return abs(2*c - (b.Cols() - 1)) }
+	slices.SortStableFunc(columns, func(a, b int) int { return distance(a) - distance(b) })
+	return &search{board: b.Clone(), columns: columns, deadline: deadline}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// root returns the best move at depth and its score, complete is false if the node budget ran out.
+func (s *search) root(depth int) (int, int, bool) {
+	bestMove, alpha := -1, -winScore-1
+	for _, c := range s.order() {
+		_, _ = s.board.Drop(c)
+		score := -s.negamax(depth-1, -winScore-1, -alpha, 1)
+		_ = s.board.Undo()
+		if s.aborted {
+			return -1, 0, false
+		}
+		if score > alpha {
+			bestMove, alpha = c, score
+		}
+	}
+	return bestMove, alpha, true
+}
+
+func (s *search) negamax(depth, alpha, beta, ply int) int {
+	s.nodes++
+	if s.nodes > maxNodes || (s.nodes%1024 == 0 && time.Now().After(s.deadline)) {
+		s.aborted = true
+		return 0
+	}
+	b := s.board
+	if b.Winner() != engine.Empty {
+		return -(winScore - ply) // the previous move won, quicker wins score higher
+	}
+	if b.IsFull() {
+		return 0
+	}
+	if depth == 0 {
+		return evaluate(b)
+	}
+	for _, c := range s.order() {
+		_, _ = b.Drop(c)
+		score := -s.negamax(depth-1, -beta, -alpha, ply+1)
+		_ = b.Undo()
+		if s.aborted {
+			return 0
+		}
+		alpha = max(alpha, score)
+		if alpha >= beta {
+			break
+		}
+	}
+	return alpha
+}
+
+// order returns the legal moves, most promising first.
+func (s *search) order() []int {
+	moves := make([]int, 0, len(s.columns))
+	for _, c := range s.columns {
+		if s.board.CanDrop(c) {
+			moves = append(moves, c)
+		}
+	}
+	return moves
+}
+
+var directions = [...]engine.Coord{{Row: 0, Col: 1}, {Row: 1, Col: 0}, {Row: 1, Col: 1}, {Row: 1, Col: -1}}
+
+// evaluate scores every window of connect cells from the point of view of the side to move:
+// windows holding discs of a single color count more the fuller they are. The result stays well
+// below winScore so a heuristic never outweighs a forced win.
+func evaluate(b *engine.Board) int {
+	me := b.Turn()
+	n := b.ConnectN()
+	score := 0
+	for r := range b.Rows() {
+		for c := range b.Cols() {
+			for _, d := range directions {
+				endRow, endCol := r+(n-1)*d.Row, c+(n-1)*d.Col
+				if endRow >= b.Rows() || endCol < 0 || endCol >= b.Cols() {
+					continue
+				}
+				mine, theirs := 0, 0
+				for i := range n {
+					switch b.At(r+i*d.Row, c+i*d.Col) {
+					case me:
+						mine++
+					case me.Opponent():
+						theirs++
+					case engine.Empty:
+					}
+				}
+				switch {
+				case mine > 0 && theirs == 0:
+					score += 1 << min(3*mine, 18)
+				case theirs > 0 && mine == 0:
+					score -= 1 << min(3*theirs, 18)
+				}
+			}
+		}
+	}
+	return max(-winScore/2, min(score, winScore/2))
+}
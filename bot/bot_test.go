@@ -0,0 +1,83 @@
+package bot
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/geofpwhite/connect4-grpc/engine"
+)
+
+func board(t *testing.T, v engine.Variant, columns ...int) *engine.Board {
+	t.Helper()
+	b, err := engine.New(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range columns {
+		if _, err := b.Drop(c); err != nil {
+			t.Fatalf("drop in column %d: %v", c, err)
+		}
+	}
+	return b
+}
+
+func TestLevels(t *testing.T) {
+	for _, level := range []int{MinLevel - 1, MaxLevel + 1} {
+		if _, err := New(level); !errors.Is(err, ErrInvalidLevel) {
+			t.Errorf("expected level %d to be rejected, got %v", level, err)
+		}
+	}
+}
+
+func TestTakesWin(t *testing.T) {
+	for level := MinLevel; level <= MaxLevel; level++ {
+		if levels[level].randomness > 0 {
+			continue
+		}
+		p, err := New(level)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// red has three in a row on the bottom row and is to move
+		b := board(t, engine.Classic, 0, 0, 1, 1, 2, 2)
+		if got := p.Move(b); got != 3 {
+			t.Errorf("level %d: expected the winning column 3, got %d", level, got)
+		}
+		if len(b.Moves()) != 6 {
+			t.Fatalf("level %d: Move modified the board", level)
+		}
+	}
+}
+
+func TestBlocksLoss(t *testing.T) {
+	for level := MinLevel; level <= MaxLevel; level++ {
+		if levels[level].randomness > 0 {
+			continue
+		}
+		p, err := New(level)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// yellow to move, red threatens to complete the bottom row on column 3
+		b := board(t, engine.Classic, 0, 6, 1, 6, 2)
+		if got := p.Move(b); got != 3 {
+			t.Errorf("level %d: expected to block in column 3, got %d", level, got)
+		}
+	}
+}
+
+func TestLargeBoardStaysFast(t *testing.T) {
+	p, err := New(MaxLevel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := board(t, engine.Variant{Rows: engine.MaxSize, Cols: engine.MaxSize, Connect: 5})
+	start := time.Now()
+	if got := p.Move(b); !b.CanDrop(got) {
+		t.Fatalf("illegal move %d", got)
+	}
+	if elapsed := time.Since(start); elapsed > 2*moveTime {
+		t.Fatalf("move took %v", elapsed)
+	}
+}
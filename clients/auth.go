@@ -0,0 +1,41 @@
+package clients
+
+import (
+	"context"
+	"os"
+
+	"github.com/geofpwhite/connect4-grpc/pb"
+)
+
+// passwordEnv holds the password of -user, kept out of the command line.
+const passwordEnv = "CONNECT4_PASSWORD"
+
+// sessionCreds adds the session token to every call once logged in.
+type sessionCreds struct {
+	token string
+}
+
+func (c *sessionCreds) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	if c.token == "" {
+		return nil, nil
+	}
+	return map[string]string{"authorization": "Bearer " + c.token}, nil
+}
+
+func (c *sessionCreds) RequireTransportSecurity() bool {
+	return false
+}
+
+// login opens a session for username, creating the account first if register is set, or a guest
+// session without a username.
+func login(client pb.Connect4Client, username string, register bool) (*pb.Session, error) {
+	if username == "" {
+		return client.Guest(context.Background(), &pb.Empty{})
+	}
+	password := os.Getenv(passwordEnv)
+	creds := &pb.Credentials{Username: &username, Password: &password}
+	if register {
+		return client.Register(context.Background(), creds)
+	}
+	return client.Login(context.Background(), creds)
+}
@@ -0,0 +1,122 @@
+package clients
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"fortio.org/terminal/ansipixels"
+	"github.com/geofpwhite/connect4-grpc/pb"
+)
+
+const (
+	// chatLines is how many messages the chat pane under the board shows.
+	chatLines = 3
+	// maxChatLength is the longest message the server accepts.
+	maxChatLength = 200
+)
+
+var emotes = map[pb.Emote]string{
+	pb.Emote_hello:       "waves hello",
+	pb.Emote_good_game:   "says good game",
+	pb.Emote_well_played: "says well played",
+	pb.Emote_oops:        "says oops",
+	pb.Emote_thinking:    "is thinking",
+	pb.Emote_thanks:      "says thanks",
+}
+
+// emoteKeys send an emote when pressed outside of the chat prompt.
+var emoteKeys = map[byte]pb.Emote{
+	'1': pb.Emote_hello,
+	'2': pb.Emote_good_game,
+	'3': pb.Emote_well_played,
+	'4': pb.Emote_oops,
+	'5': pb.Emote_thinking,
+	'6': pb.Emote_thanks,
+}
+
+// chatBox is the chat pane, with the message being typed and whether the opponent is muted.
+type chatBox struct {
+	lines  []string // last messages, oldest first
+	typing bool
+	draft  []byte
+	muted  bool
+}
+
+func (c *chatBox) add(line *pb.ChatLine) {
+	msg := playerName(line.GetName(), line.GetFrom().String())
+	if emote, ok := emotes[line.GetEmote()]; ok {
+		msg += " " + emote
+	}
+	if text := line.GetText(); text != "" {
+		msg += ": " + text
+	}
+	c.lines = append(c.lines, msg)
+	if len(c.lines) > chatLines {
+		c.lines = c.lines[len(c.lines)-chatLines:]
+	}
+}
+
+// key handles the keys typed in the prompt, or the ones opening it, sending an emote or muting the
+// opponent outside of it. It returns the input to send, if any, and whether the keys were for the chat.
+func (c *chatBox) key(data []byte) (*pb.Input, bool) {
+	if !c.typing {
+		if len(data) != 1 {
+			return nil, false
+		}
+		switch data[0] {
+		case 't', '\r':
+			c.typing = true
+			return nil, true
+		case 'm':
+			c.muted = !c.muted
+			return &pb.Input{Action: &pb.Input_MuteChat{MuteChat: c.muted}}, true
+		}
+		if emote, ok := emoteKeys[data[0]]; ok {
+			return &pb.Input{Action: &pb.Input_Chat{Chat: &pb.ChatMessage{Emote: emote.Enum()}}}, true
+		}
+		return nil, false
+	}
+	switch {
+	case len(data) == 1 && data[0] == 27: // escape
+		c.typing, c.draft = false, nil
+		return nil, true
+	case data[0] == 27: // arrows and other escape sequences
+		return nil, true
+	}
+	for _, b := range data {
+		switch {
+		case b == '\r' || b == '\n':
+			text := strings.TrimSpace(string(c.draft))
+			c.typing, c.draft = false, nil
+			if text == "" {
+				return nil, true
+			}
+			return &pb.Input{Action: &pb.Input_Chat{Chat: &pb.ChatMessage{Text: &text}}}, true
+		case b == 127 || b == 8: // backspace
+			_, size := utf8.DecodeLastRune(c.draft)
+			c.draft = c.draft[:len(c.draft)-size]
+		case b < ' ':
+		case utf8.RuneCount(c.draft) < maxChatLength:
+			c.draft = append(c.draft, b)
+		}
+	}
+	return nil, true
+}
+
+// draw writes the last messages on the chatLines rows above the bottom one.
+func (c *chatBox) draw(ap *ansipixels.AnsiPixels) {
+	top := ap.H - 1 - chatLines
+	if c.muted {
+		ap.WriteRight(top, "opponent muted, m to unmute")
+	}
+	for i, line := range c.lines {
+		ap.WriteAtStr(1, top+i, fmt.Sprintf("%.*s", max(0, ap.W-2), line))
+	}
+}
+
+// prompt is the bottom row while a message is being typed, its end when wider than width.
+func (c *chatBox) prompt(width int) string {
+	p := []rune("say (esc to cancel): " + string(c.draft) + "_")
+	return string(p[max(0, len(p)-width):])
+}
@@ -0,0 +1,86 @@
+package clients
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"fortio.org/terminal/ansipixels"
+	"github.com/geofpwhite/connect4-grpc/pb"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// parseTimeControl reads "5m+3s" or "5m" for Fischer clocks, "30s/move" for a fixed time per move
+// and "3d" for correspondence days, empty meaning untimed.
+func parseTimeControl(s string) (*pb.TimeControl, error) {
+	switch {
+	case s == "":
+		return nil, nil //nolint:nilnil // untimed
+	case strings.HasSuffix(s, "/move"):
+		d, err := time.ParseDuration(strings.TrimSuffix(s, "/move"))
+		if err != nil {
+			return nil, err
+		}
+		return &pb.TimeControl{Kind: &pb.TimeControl_PerMove{PerMove: durationpb.New(d)}}, nil
+	case strings.HasSuffix(s, "d"):
+		days, err := strconv.ParseInt(strings.TrimSuffix(s, "d"), 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.TimeControl{Kind: &pb.TimeControl_CorrespondenceDays{CorrespondenceDays: int32(days)}}, nil
+	}
+	initial, increment, _ := strings.Cut(s, "+")
+	fischer := &pb.Fischer{}
+	d, err := time.ParseDuration(initial)
+	if err != nil {
+		return nil, err
+	}
+	fischer.Initial = durationpb.New(d)
+	if increment != "" {
+		if d, err = time.ParseDuration(increment); err != nil {
+			return nil, err
+		}
+		fischer.Increment = durationpb.New(d)
+	}
+	return &pb.TimeControl{Kind: &pb.TimeControl_Fischer{Fischer: fischer}}, nil
+}
+
+// drawClocks shows the time left to each player under the scoreboard, the clock of the side to
+// move counting down since the state was received.
+func drawClocks(ap *ansipixels.AnsiPixels, l layout, clocks *pb.Clocks, received time.Time, turn pb.Team) {
+	if clocks == nil || l.sidebarX+sidebarW > ap.W {
+		return
+	}
+	for i, side := range []struct {
+		team pb.Team
+		left time.Duration
+	}{{pb.Team_red, clocks.GetRed().AsDuration()}, {pb.Team_yellow, clocks.GetYellow().AsDuration()}} {
+		left, marker := side.left, " "
+		if clocks.GetRunning() && side.team == turn {
+			left, marker = max(left-time.Since(received), 0), ">"
+		}
+		ap.WriteAtStr(l.sidebarX, 10+i, fmt.Sprintf("%s %-10s %s", marker, side.team, formatClock(left)))
+	}
+}
+
+// drawShutdown counts down to the shutdown of the server, if it is shutting down.
+func drawShutdown(ap *ansipixels.AnsiPixels, l layout, at time.Time) {
+	if at.IsZero() || l.sidebarX+sidebarW > ap.W {
+		return
+	}
+	ap.WriteAtStr(l.sidebarX, 13, fmt.Sprintf("Server stops in %-8s", formatClock(max(time.Until(at), 0))))
+}
+
+func formatClock(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd%02dh", int(d.Hours())/24, int(d.Hours())%24)
+	case d >= time.Hour:
+		return fmt.Sprintf("%d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+	case d >= 10*time.Second:
+		return fmt.Sprintf("%d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+	}
+	// tenths of a second when it gets close
+	return fmt.Sprintf("0:%04.1f", d.Seconds())
+}
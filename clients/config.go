@@ -0,0 +1,101 @@
+package clients
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	defaultServer = "64.227.12.170:50051"
+	// serverEnv overrides the server of the config file, -server overriding both.
+	serverEnv = "CONNECT4_SERVER"
+)
+
+// settings are the defaults of the connection flags and of -name, read from a JSON file like
+// {"server": "localhost:50051", "tls": true, "name": "ann"}.
+type settings struct {
+	Server string `json:"server,omitempty"`
+	TLS    bool   `json:"tls,omitempty"`
+	CA     string `json:"ca,omitempty"` // PEM file of the CA of the server, the system ones if empty
+	Name   string `json:"name,omitempty"`
+}
+
+// settingsFile is where the settings are read from without -config, empty if the system has no
+// config directory.
+func settingsFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "connect4", "client.json")
+}
+
+// loadSettings reads the settings of path, a missing file being fine unless required.
+func loadSettings(path string, required bool) (settings, error) {
+	var s settings
+	if path == "" {
+		return s, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !required {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// override sets the fields of s given by the environment or, winning over it, on the command line.
+func (s *settings) override(flags settings) {
+	if server := os.Getenv(serverEnv); server != "" {
+		s.Server = server
+	}
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "server":
+			s.Server = flags.Server
+		case "tls":
+			s.TLS = flags.TLS
+		case "ca":
+			s.CA = flags.CA
+		case "name":
+			s.Name = flags.Name
+		}
+	})
+	if s.Server == "" {
+		s.Server = defaultServer
+	}
+}
+
+// transport returns the credentials of the connection to the server, TLS being implied by a CA.
+func (s settings) transport() (credentials.TransportCredentials, error) {
+	if !s.TLS && s.CA == "" {
+		return insecure.NewCredentials(), nil
+	}
+	conf := &tls.Config{MinVersion: tls.VersionTLS12}
+	if s.CA != "" {
+		pem, err := os.ReadFile(s.CA)
+		if err != nil {
+			return nil, fmt.Errorf("reading the CA: %w", err)
+		}
+		conf.RootCAs = x509.NewCertPool()
+		if !conf.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in %s", s.CA)
+		}
+	}
+	return credentials.NewTLS(conf), nil
+}
@@ -1,6 +1,7 @@
 package clients
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"flag"
@@ -10,20 +11,38 @@ import (
 	"image/draw"
 	"io"
 	"math"
+	"os"
 	"strconv"
+	"time"
 
 	"fortio.org/log"
 	"fortio.org/terminal/ansipixels"
 	"fortio.org/terminal/ansipixels/tcolor"
+	"github.com/geofpwhite/connect4-grpc/engine"
 	"github.com/geofpwhite/connect4-grpc/pb"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type game struct {
-	id    int32
-	team  pb.Team
-	state [8][8]pb.Team
+	id          int32
+	team        pb.Team
+	board       *engine.Board
+	result      pb.Result
+	winningLine map[engine.Coord]bool
+	notice      string // why the last move was refused
+	score       *pb.Score
+	players     *pb.Players
+	turn        pb.Team
+	endReason   pb.EndReason
+	nextFirst   pb.Team // who opens the next board, once this one is over
+	offer       *pb.Offer
+	ratings     *pb.RatingChanges // of the board that just ended, in a rated game
+	shutdownAt  time.Time         // when the server stops, zero unless it is shutting down
+	clocks      *pb.Clocks
+	clocksAt    time.Time // when clocks were received
+	chat        chatBox
 }
 
 func Main() { //nolint: funlen,gocognit,gocyclo,maintidx //this is the main function it's gonna get a bit big
@@ -33,79 +52,235 @@ func Main() { //nolint: funlen,gocognit,gocyclo,maintidx //this is the main func
 		panic("")
 	}
 	newGame := flag.Bool("new", false, "Create a new game to play with a friend")
-	joinID := flag.Int("join-id", -1, "id of game to join")
+	joinID := flag.Int("join-id", -1, "id of game to join, without any of -new, -bot and -watch the lobby lets you pick one")
+	rows := flag.Int("rows", engine.DefaultRows, "number of rows of a new game")
+	cols := flag.Int("cols", engine.DefaultCols, "number of columns of a new game")
+	connect := flag.Int("connect", engine.DefaultConnect, "number of aligned discs needed to win a new game")
+	firstTo := flag.Int("first-to", 0, "end a new match once a player won that many games, 0 plays forever")
+	timeControl := flag.String("time", "",
+		"clock of a new game, or of the games listed: 5m+3s (5 minutes, 3 seconds added per move), 30s/move or 3d (days per move)")
+	firstPlayer := flag.String("first-player", pb.FirstPlayer_alternate.String(),
+		"who opens the boards after the first one of a new game: alternate, loser_starts or random_first")
+	botLevel := flag.Int("bot", 0, "play a new game against the computer at that level, from 1 (easy) to 5 (hard)")
+	watchID := flag.Int("watch", -1, "id of a game to watch without playing")
+	name := flag.String("name", "", "name shown to your opponent and spectators")
+	match := flag.Bool("match", false, "wait for the server to pair you with another player of the same board")
+	user := flag.String("user", "", "account to log in with, its password being read from $"+passwordEnv+", a guest session otherwise")
+	register := flag.Bool("register", false, "create the -user account before logging in")
+	replayID := flag.Int("replay", -1, "id of a game, live or finished, to step through move by move")
+	exportID := flag.Int("export", -1, "print the board in play of a game, live or finished, in the text notation and exit")
+	rated := flag.Bool("rated", false, "make a new game, or the one found with -match, count for the ratings of both accounts")
+	profile := flag.String("profile", "", "print the rating and results of that account and exit")
+	leaderboard := flag.Int("leaderboard", 0, "print that many of the best rated players and exit")
+	load := flag.String("load", "",
+		"file of a game in the text notation to start a new game from, with the -bot, -first-to and -name settings")
+	var flags settings
+	flag.StringVar(&flags.Server, "server", defaultServer, "address of the server, $"+serverEnv+" by default")
+	flag.BoolVar(&flags.TLS, "tls", false, "connect to the server over TLS")
+	flag.StringVar(&flags.CA, "ca", "", "PEM file of the CA the certificate of the server is checked against, implies -tls")
+	config := flag.String("config", settingsFile(), "JSON file of defaults for -server, -tls, -ca and -name")
 	flag.Parse()
+	explicit := false
+	flag.Visit(func(f *flag.Flag) { explicit = explicit || f.Name == "config" })
+	cfg, err := loadSettings(*config, explicit)
+	if err != nil {
+		fail(ap, "can't load the settings: %s", err)
+	}
+	flags.Name = *name
+	cfg.override(flags)
+	*name = cfg.Name
+	clock, err := parseTimeControl(*timeControl)
+	if err != nil {
+		fail(ap, "invalid -time %q: %s", *timeControl, err)
+	}
+	policy, ok := pb.FirstPlayer_value[*firstPlayer]
+	if !ok {
+		fail(ap, "invalid -first-player %q", *firstPlayer)
+	}
 
-	conn, err := grpc.NewClient("64.227.12.170:50051", grpc.WithTransportCredentials(insecure.NewCredentials()))
-	// conn, err := grpc.NewClient("localhost:50051", grpc.WithTransportCredentials(insecure.NewCredentials()))
-	// connected := false
+	transport, err := cfg.transport()
+	if err != nil {
+		fail(ap, "can't set up TLS: %s", err)
+	}
+	creds := &sessionCreds{}
+	conn, err := grpc.NewClient(cfg.Server, grpc.WithTransportCredentials(transport), grpc.WithPerRPCCredentials(creds))
 	if err != nil {
-		panic(err)
+		fail(ap, "can't connect to %s: %s", cfg.Server, err)
 	}
 	defer conn.Close()
 	client := pb.NewConnect4Client(conn)
+	session, err := login(client, *user, *register)
+	switch {
+	case status.Code(err) == codes.Unavailable:
+		fail(ap, "can't reach the server at %s: %s", cfg.Server, status.Convert(err).Message())
+	case err != nil:
+		fail(ap, "can't log in: %s", status.Convert(err).Message())
+	}
+	creds.token = session.GetToken()
+	if *exportID >= 0 {
+		ap.ShowCursor()
+		ap.Restore()
+		id := int32(*exportID) //nolint:gosec //panic is fine if they give number that overflows
+		exported, exportErr := client.ExportGame(context.Background(), &pb.ExportGameRequest{Id: &id})
+		if exportErr != nil {
+			panic(fmt.Sprintf("can't export game %d: %s", id, status.Convert(exportErr).Message()))
+		}
+		fmt.Print(exported.GetNotation())
+		return
+	}
+	if *profile != "" || *leaderboard > 0 {
+		ap.ShowCursor()
+		ap.Restore()
+		if rankErr := printRatings(client, *profile, *leaderboard); rankErr != nil {
+			panic(fmt.Sprintf("can't get the ratings: %s", status.Convert(rankErr).Message()))
+		}
+		return
+	}
+	if *replayID >= 0 {
+		id := int32(*replayID) //nolint:gosec //panic is fine if they give number that overflows
+		replayErr := viewReplay(ap, client, id)
+		ap.ShowCursor()
+		ap.Restore()
+		if replayErr != nil {
+			panic(fmt.Sprintf("can't replay game %d: %s", id, status.Convert(replayErr).Message()))
+		}
+		return
+	}
+	if *watchID < 0 && *joinID < 0 && !*newGame && *botLevel == 0 && !*match && *load == "" {
+		// the board and clock flags narrow down the games listed
+		filter := &pb.ListGamesRequest{Variant: &pb.Variant{}}
+		r, c, n := int32(*rows), int32(*cols), int32(*connect) //nolint:gosec // server validates the variant
+		flag.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "rows":
+				filter.Variant.Rows = &r
+			case "cols":
+				filter.Variant.Columns = &c
+			case "connect":
+				filter.Variant.Connect = &n
+			case "time":
+				filter.TimeControl = cmp.Or(clock, &pb.TimeControl{}) // untimed games for an empty -time
+			}
+		})
+		id, action, lobbyErr := pickGame(ap, client, filter)
+		if lobbyErr != nil {
+			panic(fmt.Sprintf("can't show the lobby: %s", status.Convert(lobbyErr).Message()))
+		}
+		switch action {
+		case lobbyQuit:
+			ap.ShowCursor()
+			ap.Restore()
+			return
+		case lobbyNew:
+			*newGame = true
+		case lobbyMatch:
+			*match = true
+		case lobbyJoin:
+			*joinID = int(id)
+		}
+	}
 	g := &game{}
-	if *newGame {
-		id, initErr := client.NewGame(context.Background(), &pb.Empty{}, grpc.EmptyCallOption{})
-		g.id = id.GetId()
-		g.team = id.GetTeam()
+	var variant *pb.Variant
+	var seat *pb.GameIDAndTeam
+	switch {
+	case *watchID >= 0:
+		g.id = int32(*watchID) //nolint:gosec //panic is fine if they give number that overflows
+	case *match:
+		r, c, n := int32(*rows), int32(*cols), int32(*connect) //nolint:gosec // server validates the variant
+		req := &pb.FindMatchRequest{Variant: &pb.Variant{Rows: &r, Columns: &c, Connect: &n}, PlayerName: name, TimeControl: clock,
+			Rated: rated}
+		var matchErr error
+		seat, matchErr = findMatch(ap, client, req)
+		if matchErr != nil {
+			panic(fmt.Sprintf("can't find a match: %s", status.Convert(matchErr).Message()))
+		}
+		if seat == nil {
+			ap.ShowCursor()
+			ap.Restore()
+			return
+		}
+	case *newGame || *botLevel > 0 || *load != "":
+		r, c, n := int32(*rows), int32(*cols), int32(*connect) //nolint:gosec // server validates the variant
+		f := int32(*firstTo)                                   //nolint:gosec // server validates it
+		req := &pb.NewGameRequest{Variant: &pb.Variant{Rows: &r, Columns: &c, Connect: &n}, FirstTo: &f, PlayerName: name,
+			TimeControl: clock, FirstPlayer: pb.FirstPlayer(policy).Enum(), Rated: rated}
+		if *botLevel > 0 {
+			level := int32(*botLevel) //nolint:gosec // server validates the level
+			req.Opponent, req.BotLevel = pb.Opponent_bot.Enum(), &level
+		}
+		var initErr error
+		if *load != "" {
+			position, readErr := os.ReadFile(*load)
+			if readErr != nil {
+				panic(fmt.Sprintf("can't read the position: %s", readErr))
+			}
+			text := string(position)
+			seat, initErr = client.ImportPosition(context.Background(), &pb.ImportPositionRequest{Notation: &text, Game: req})
+		} else {
+			seat, initErr = client.NewGame(context.Background(), req, grpc.EmptyCallOption{})
+		}
 		if initErr != nil {
-			panic(fmt.Sprintf("issue starting game: %s", initErr))
+			panic(fmt.Sprintf("issue starting game: %s", status.Convert(initErr).Message()))
 		}
-	} else {
+	default:
 		id := int32(*joinID) //nolint:gosec //panic is fine if they give number that overflows
-		idAndTeam, joinErr := client.JoinGame(context.Background(), &pb.GameID{Id: &id})
+		var joinErr error
+		seat, joinErr = client.JoinGame(context.Background(), &pb.JoinGameRequest{Id: &id, PlayerName: name})
 		if joinErr != nil {
-			panic("no game with that id")
+			panic(fmt.Sprintf("can't join game %d: %s", id, status.Convert(joinErr).Message()))
 		}
-		g.id = id
-		g.team = idAndTeam.GetTeam()
 	}
-	stream, err := client.CommunicateState(context.Background())
-	if err != nil {
-		panic("Error starting stream")
+	if seat != nil {
+		g.id, g.team, variant = seat.GetId(), seat.GetTeam(), seat.GetVariant()
 	}
-	stateChan := make(chan ([8][8]pb.Team))
-	inputChan := make(chan int)
-	startColumn := int32(-1)
-	inputObj := &pb.Input{GameId: &g.id, InputTeam: &g.team, Column: &startColumn}
-	err = stream.Send(inputObj)
+	g.board, err = engine.New(variant.Engine())
 	if err != nil {
-		log.Infof("error sending initial connection message")
+		panic(fmt.Sprintf("unsupported board: %s", err))
 	}
-	defer func() {
-		if _, leaveErr := client.LeaveGame(context.Background(), &pb.GameIDAndTeam{Id: &g.id, Team: &g.team}); leaveErr != nil {
-			log.FErrf("error leaving")
+	stateChan := make(chan *pb.State)
+	inputChan := make(chan *pb.Input)
+	var recv func() (*pb.State, error)
+	if *watchID >= 0 {
+		spectate, spectateErr := client.Spectate(context.Background(), &pb.GameID{Id: &g.id})
+		if spectateErr != nil {
+			panic(fmt.Sprintf("can't watch game %d: %s", g.id, status.Convert(spectateErr).Message()))
 		}
-	}()
+		recv = spectate.Recv
+	} else {
+		// the stream reconnects on its own if the connection drops
+		stream, streamErr := newSeatStream(client, seat)
+		if streamErr != nil {
+			panic(fmt.Sprintf("Error starting stream: %s", status.Convert(streamErr).Message()))
+		}
+		recv = stream.Recv
+		defer func() {
+			if _, leaveErr := client.LeaveGame(context.Background(), seat); leaveErr != nil {
+				log.FErrf("error leaving")
+			}
+		}()
+		go func() {
+			for input := range inputChan {
+				sendErr := stream.Send(input)
+				if sendErr != nil {
+					// lost while reconnecting, the player clicks again
+					continue
+				}
+			}
+		}()
+	}
 
+	var lost error // why the stream ended, read once stateChan is closed
 	go func() {
-		stateReceived := [8][8]pb.Team{}
 		for {
-			in, streamErr := stream.Recv()
-			if errors.Is(streamErr, io.EOF) {
+			in, streamErr := recv()
+			if streamErr != nil {
+				if !errors.Is(streamErr, io.EOF) {
+					lost = streamErr
+				}
 				close(stateChan)
 				return
 			}
-			if streamErr != nil {
-				continue
-			}
-			for i, row := range in.GetField().GetRows() {
-				copy(stateReceived[i][:], row.GetValues())
-			}
-			stateChan <- stateReceived
-		}
-	}()
-	go func() {
-		for input := range inputChan {
-			i32 := int32(input) //nolint:gosec //input will never be greater than 8
-			inputObj.Column = &i32
-			sendErr := stream.Send(inputObj)
-			if sendErr != nil {
-				// panic(err)
-				//
-				continue
-			}
+			stateChan <- in
 		}
 	}()
 
@@ -125,6 +300,7 @@ func Main() { //nolint: funlen,gocognit,gocyclo,maintidx //this is the main func
 	draw.Draw(img, img.Rect, image.NewUniform(color.Black), image.Point{}, draw.Over)
 	frame := 0
 	highlightedColumn := -1
+	resigning := false // r was pressed once, pressing it again resigns
 	ap.OnResize = func() error {
 		img = image.NewRGBA(image.Rect(0, 0, ap.W, ap.H*2))
 		return nil
@@ -132,13 +308,49 @@ func Main() { //nolint: funlen,gocognit,gocyclo,maintidx //this is the main func
 	err = ap.FPSTicks(context.Background(), func(context.Context) bool {
 		frame = (frame + 1) % 60
 		select {
-		case state := <-stateChan:
-			//
+		case state, ok := <-stateChan:
+			if !ok {
+				stateChan = nil
+				g.notice = "The game ended"
+				if lost != nil {
+					g.notice = "Disconnected: " + status.Convert(lost).Message()
+				}
+				break
+			}
+			board, boardErr := state.GetField().Board(int(state.GetVariant().GetConnect()))
+			if boardErr != nil {
+				log.Errf("invalid board received: %v", boardErr)
+				break
+			}
 			ap.ClearScreen()
 			img = image.NewRGBA(image.Rect(0, 0, ap.W, ap.H*2))
 			draw.Draw(img, img.Rect, image.NewUniform(color.Black), image.Point{}, draw.Over)
 
-			g.state = state
+			g.board = board
+			g.result = state.GetResult()
+			g.score = state.GetScore()
+			g.players = state.GetPlayers()
+			g.turn = state.GetTurn()
+			g.endReason = state.GetEndReason()
+			g.nextFirst = state.GetNextFirst()
+			g.clocks, g.clocksAt = state.GetClocks(), time.Now()
+			g.offer = state.GetOffer()
+			g.ratings = state.GetRatingChanges()
+			g.shutdownAt = time.Time{}
+			if at := state.GetShutdownAt(); at != nil {
+				g.shutdownAt = at.AsTime()
+			}
+			if line := state.GetChat(); line != nil {
+				g.chat.add(line)
+			}
+			g.notice = ""
+			if r := state.GetRejection(); r != nil {
+				g.notice = rejectionMessage(r)
+			}
+			g.winningLine = make(map[engine.Coord]bool)
+			for _, cell := range state.GetWinningLine() {
+				g.winningLine[engine.Coord{Row: int(cell.GetRow()), Col: int(cell.GetColumn())}] = true
+			}
 			// for i, row := range g.state {
 			// 	for j, value := range row {
 			// 		clr := color.RGBA{}
@@ -159,61 +371,76 @@ func Main() { //nolint: funlen,gocognit,gocyclo,maintidx //this is the main func
 			// }
 		default:
 		}
-		for i := range g.state {
-			x := (ap.W / 10) * (1 + i)
-			xBound := x + (ap.W / 10)
+		l := newLayout(ap, g.board, chatLines)
+		for i := range g.board.Cols() {
+			x, xBound := l.columnBounds(i)
 			clr := color.RGBA{0, 0, 0, 50}
 			if ap.Mx >= x && ap.Mx < xBound && highlightedColumn != i {
 				clr = color.RGBA{255, 255, 255, 50}
 				highlightedColumn = i
 			}
-			draw.Draw(img, image.Rect(x, ap.H/5, xBound, ap.H*9/5), &image.Uniform{clr}, image.Point{}, draw.Over)
+			draw.Draw(img, image.Rect(x, 2*l.cellH, xBound, 2*l.cellH*(g.board.Rows()+1)),
+				&image.Uniform{clr}, image.Point{}, draw.Over)
 		}
 		ap.Draw216ColorImage(0, 0, img)
-		for i, row := range g.state {
-			for j, value := range row {
-				x := (ap.W / 10) * (1 + j)
-				clr := tcolor.RGBColor{}
-				y := ((ap.H) - ((ap.H / 10) * (1 + i)))
-				xBound := x + (ap.W / 10)
-				yBound := y - ap.H/10
-				switch value {
-				case 1:
-					clr = tcolor.RGBColor{255, 0, 0}
-				case 2:
-					clr = tcolor.RGBColor{255, 255, 0}
-				case 0:
-					continue
-				}
-
-				radius := min((xBound-x)/2, (y - yBound))
-				ap.DiscSRGB((x+xBound)/2, yBound, radius, clr, clr, .1)
+		drawDiscs(ap, l, g.board, g.winningLine, frame < 30)
+		keys := ap.Data
+		if len(keys) > 0 && g.team != pb.Team_empty {
+			in, forChat := g.chat.key(keys)
+			if in != nil {
+				inputChan <- in
+			}
+			if forChat {
+				keys = nil
 			}
 		}
-		if len(ap.Data) > 0 && ap.Data[0] == 'q' {
+		if len(keys) > 0 && keys[0] == 'q' {
 			return false
 		}
-		if ap.LeftClick() || ap.LeftDrag() {
-			column := ap.Mx / (ap.W / 10)
-			ap.WriteAtStr(1, ap.H-1, strconv.Itoa(column))
-			if column > 0 && column < 9 {
-				inputChan <- column
+		if len(keys) > 0 && g.team != pb.Team_empty {
+			if in := actionOf(keys[0], &resigning); in != nil {
+				inputChan <- in
 			}
 		}
-		// ap.WriteAtStr(1, 1, fmt.Sprintf("%d", frame))
-		for i := range 8 {
-			ap.DrawRoundBox(ap.W*(1+i)/10, ap.H/10, ap.W/10, ap.H*8/10)
-			// for j := range 8 {
-			// 	x := (ap.W / 10) * (1 + j)
-			// 	y := ((ap.H / 10) * (1 + i))
-			// 	xBound := x + (ap.W / 10)
-			// 	yBound := y + (ap.H / 10)
-
-			// 	// draw.Draw(img, image.Rect(x, y, xBound, yBound), &image.Uniform{clr}, image.Point{}, draw.Over)
-			// 	// ap.DrawSquareBox(x+1, y+1, xBound-x-1, yBound-y-1)
-			// }
+		if (ap.LeftClick() || ap.LeftDrag()) && g.team != pb.Team_empty {
+			column := l.column(ap.Mx)
+			ap.WriteAtStr(1, ap.H-1, strconv.Itoa(column))
+			switch {
+			case g.result != pb.Result_in_progress:
+				// once the game is over a click asks for the next one
+				inputChan <- &pb.Input{Action: &pb.Input_Rematch{Rematch: &pb.Empty{}}}
+			case g.board.CanDrop(column - 1):
+				inputChan <- &pb.Input{Action: &pb.Input_Column{Column: int32(column)}} //nolint:gosec // at most the number of columns
+			}
 		}
+		drawColumns(ap, l, g.board)
 		ap.WriteAtStr(1, 1, strconv.Itoa(int(g.id)))
+		drawScoreboard(ap, l, g.score, g.players)
+		drawClocks(ap, l, g.clocks, g.clocksAt, g.turn)
+		drawShutdown(ap, l, g.shutdownAt)
+		g.chat.draw(ap)
+		if g.chat.typing {
+			ap.WriteAtStr(1, ap.H-1, g.chat.prompt(ap.W-2))
+		} else if winner := g.score.GetMatchWinner(); winner != pb.Team_empty {
+			ap.WriteCentered(ap.H-1, "Match over, %s wins %d-%d", winner, g.score.GetRedWins(), g.score.GetYellowWins())
+		} else if msg := resultMessage(g.result, g.endReason, g.team) +
+			ratingMessage(g.ratings, g.team); msg != "" && g.team == pb.Team_empty {
+			ap.WriteCentered(ap.H-1, "%s", msg)
+		} else if msg != "" && g.offer.GetKind() == pb.OfferKind_rematch {
+			ap.WriteCentered(ap.H-1, "%s - %s", msg, offerMessage(g.offer, g.team))
+		} else if msg != "" {
+			ap.WriteCentered(ap.H-1, "%s - click for a rematch, %s starts", msg, g.nextFirst)
+		} else if g.notice != "" {
+			ap.WriteCentered(ap.H-1, "%s", g.notice)
+		} else if resigning {
+			ap.WriteCentered(ap.H-1, "Press r again to resign")
+		} else if msg := offerMessage(g.offer, g.team); msg != "" {
+			ap.WriteCentered(ap.H-1, "%s", msg)
+		}
+		help := "r resign  d draw  u take back  a/x accept/decline  t chat  1-6 emotes  m mute  q quit"
+		if g.team != pb.Team_empty && ap.W > len(help)+10 {
+			ap.WriteRight(0, "%s", help)
+		}
 		return true
 	})
 	if err != nil {
@@ -221,6 +448,165 @@ func Main() { //nolint: funlen,gocognit,gocyclo,maintidx //this is the main func
 	}
 }
 
+// drawDiscs draws the discs of board, the ones of the winning line in white when blink is set.
+func drawDiscs(ap *ansipixels.AnsiPixels, l layout, board *engine.Board, winningLine map[engine.Coord]bool, blink bool) {
+	for i, row := range board.Grid() {
+		for j, value := range row {
+			clr := tcolor.RGBColor{}
+			switch value {
+			case engine.Red:
+				clr = tcolor.RGBColor{R: 255, G: 0, B: 0}
+			case engine.Yellow:
+				clr = tcolor.RGBColor{R: 255, G: 255, B: 0}
+			case engine.Empty:
+				continue
+			}
+			if winningLine[engine.Coord{Row: i, Col: j}] && blink {
+				clr = tcolor.RGBColor{R: 255, G: 255, B: 255}
+			}
+			x, y, radius := l.disc(i, j)
+			ap.DiscSRGB(x, y, radius, clr, clr, .1)
+		}
+	}
+}
+
+func drawColumns(ap *ansipixels.AnsiPixels, l layout, board *engine.Board) {
+	for i := range board.Cols() {
+		x, _ := l.columnBounds(i)
+		ap.DrawRoundBox(x, l.cellH, l.cellW, l.cellH*board.Rows())
+	}
+}
+
+func drawScoreboard(ap *ansipixels.AnsiPixels, l layout, score *pb.Score, players *pb.Players) {
+	if score == nil || l.sidebarX+sidebarW > ap.W {
+		return
+	}
+	lines := []string{
+		"Score",
+		fmt.Sprintf("%-12.12s %3d", playerName(players.GetRed(), "Red"), score.GetRedWins()),
+		fmt.Sprintf("%-12.12s %3d", playerName(players.GetYellow(), "Yellow"), score.GetYellowWins()),
+		fmt.Sprintf("%-12s %3d", "Draws", score.GetDraws()),
+		fmt.Sprintf("%-12s %3d", "Played", score.GetGamesPlayed()),
+	}
+	if score.GetFirstTo() > 0 {
+		lines = append(lines, fmt.Sprintf("First to %d", score.GetFirstTo()))
+	}
+	if n := players.GetSpectators(); n > 0 {
+		lines = append(lines, fmt.Sprintf("%d watching", n))
+	}
+	for i, line := range lines {
+		ap.WriteAtStr(l.sidebarX, 2+i, line)
+	}
+}
+
+// fail restores the terminal, prints the message of format and exits.
+func fail(ap *ansipixels.AnsiPixels, format string, args ...any) {
+	ap.ShowCursor()
+	ap.Restore()
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
+
+func playerName(name, fallback string) string {
+	if name == "" {
+		return fallback
+	}
+	return name
+}
+
+// resultMessage describes the result from the point of view of team, spectators having the empty team.
+func resultMessage(result pb.Result, reason pb.EndReason, team pb.Team) string {
+	onTime := ""
+	switch reason { //nolint:exhaustive // the other reasons need no explanation
+	case pb.EndReason_timeout:
+		onTime = " on time"
+	case pb.EndReason_resigned:
+		onTime = " by resignation"
+	}
+	switch result {
+	case pb.Result_red_won, pb.Result_yellow_won:
+		if team == pb.Team_empty {
+			return fmt.Sprintf("%s won%s", map[pb.Result]string{pb.Result_red_won: "Red", pb.Result_yellow_won: "Yellow"}[result], onTime)
+		}
+		if (result == pb.Result_red_won) == (team == pb.Team_red) {
+			return "You won" + onTime + "!"
+		}
+		return "You lost" + onTime
+	case pb.Result_draw:
+		if reason == pb.EndReason_agreed_draw {
+			return "Draw agreed"
+		}
+		return "Draw, the board is full"
+	case pb.Result_in_progress:
+	}
+	return ""
+}
+
+func rejectionMessage(r *pb.Rejection) string {
+	switch r.GetReason() {
+	case pb.RejectionReason_not_your_turn:
+		return "Wait for your opponent to play"
+	case pb.RejectionReason_column_full:
+		return fmt.Sprintf("Column %d is full", r.GetColumn())
+	case pb.RejectionReason_column_out_of_range:
+		return fmt.Sprintf("There is no column %d", r.GetColumn())
+	case pb.RejectionReason_board_over:
+		return "The board is already over"
+	case pb.RejectionReason_no_offer:
+		return "There is no offer to answer"
+	case pb.RejectionReason_offer_pending:
+		return "An offer already waits for an answer"
+	case pb.RejectionReason_nothing_to_take_back:
+		return "You have no move to take back"
+	case pb.RejectionReason_board_in_play:
+		return "Finish the board before asking for a rematch"
+	case pb.RejectionReason_invalid_chat:
+		return "Message not sent: " + r.GetMessage()
+	case pb.RejectionReason_shutting_down:
+		return "No rematch, the server is shutting down"
+	case pb.RejectionReason_wrong_game, pb.RejectionReason_unknown_action, pb.RejectionReason_not_rejected:
+	}
+	return "Move refused: " + r.GetMessage()
+}
+
+// actionOf is the input for the key pressed by a player, nil for other keys. Resigning takes two
+// presses of r, resigning tracking the first one.
+func actionOf(key byte, resigning *bool) *pb.Input {
+	confirmed := *resigning && key == 'r'
+	*resigning = key == 'r' && !confirmed
+	switch key {
+	case 'r':
+		if confirmed {
+			return &pb.Input{Action: &pb.Input_Resign{Resign: &pb.Empty{}}}
+		}
+	case 'd':
+		return &pb.Input{Action: &pb.Input_OfferDraw{OfferDraw: &pb.Empty{}}}
+	case 'u':
+		return &pb.Input{Action: &pb.Input_RequestTakeback{RequestTakeback: &pb.Empty{}}}
+	case 'a':
+		return &pb.Input{Action: &pb.Input_Accept{Accept: &pb.Empty{}}}
+	case 'x':
+		return &pb.Input{Action: &pb.Input_Decline{Decline: &pb.Empty{}}}
+	}
+	return nil
+}
+
+// offerMessage describes the pending offer to team.
+func offerMessage(o *pb.Offer, team pb.Team) string {
+	if o == nil {
+		return ""
+	}
+	what := map[pb.OfferKind]string{
+		pb.OfferKind_draw_offer: "a draw",
+		pb.OfferKind_takeback:   "a takeback",
+		pb.OfferKind_rematch:    "a rematch",
+	}[o.GetKind()]
+	if o.GetFrom() == team {
+		return fmt.Sprintf("You offered %s, waiting for the answer", what)
+	}
+	return fmt.Sprintf("%s offers %s - a to accept, x to decline", o.GetFrom(), what)
+}
+
 type coords struct{ x, y int }
 
 func DrawDisc(x, y int, clr color.RGBA, img *image.RGBA, radius int) {
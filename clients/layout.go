@@ -0,0 +1,51 @@
+package clients
+
+import (
+	"fortio.org/terminal/ansipixels"
+	"github.com/geofpwhite/connect4-grpc/engine"
+)
+
+// sidebarW is the width kept on the right of the board for the scoreboard when the terminal is wide enough.
+const sidebarW = 24
+
+// layout maps board cells to terminal cells, keeping one empty cell of margin around the board and
+// the given number of rows under it.
+type layout struct {
+	cellW, cellH int
+	h            int
+	sidebarX     int
+}
+
+func newLayout(ap *ansipixels.AnsiPixels, board *engine.Board, bottom int) layout {
+	w := ap.W
+	h := max(1, ap.H-bottom)
+	if w > 3*sidebarW {
+		w -= sidebarW
+	}
+	cellW := max(1, w/(board.Cols()+2))
+	return layout{
+		cellW:    cellW,
+		cellH:    max(1, h/(board.Rows()+2)),
+		h:        h,
+		sidebarX: cellW*(board.Cols()+1) + 2,
+	}
+}
+
+// columnBounds returns the horizontal extent of the 0 based column.
+func (l layout) columnBounds(col int) (int, int) {
+	x := l.cellW * (1 + col)
+	return x, x + l.cellW
+}
+
+// column returns the 1 based column under x, the way the server numbers them.
+func (l layout) column(x int) int {
+	return x / l.cellW
+}
+
+// disc returns the center and radius of the disc at row, col with row 0 at the bottom.
+func (l layout) disc(row, col int) (int, int, int) {
+	x, xBound := l.columnBounds(col)
+	y := l.h - l.cellH*(1+row)
+	yBound := y - l.cellH
+	return (x + xBound) / 2, yBound, min((xBound-x)/2, y-yBound)
+}
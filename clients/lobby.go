@@ -0,0 +1,194 @@
+package clients
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"fortio.org/terminal/ansipixels"
+	"github.com/geofpwhite/connect4-grpc/pb"
+	"google.golang.org/grpc/status"
+)
+
+type lobbyAction int
+
+const (
+	lobbyQuit lobbyAction = iota
+	lobbyJoin
+	lobbyNew
+	lobbyMatch
+)
+
+// lobbyTop is the screen line of the first game of the list.
+const lobbyTop = 3
+
+// pickGame shows the open games of the server, updated live, until the player joins one, asks for a
+// new game or quits. Only games matching filter are listed.
+func pickGame(ap *ansipixels.AnsiPixels, client pb.Connect4Client, filter *pb.ListGamesRequest) (int32, lobbyAction, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	watch, err := client.WatchLobby(ctx, filter)
+	if err != nil {
+		return 0, lobbyQuit, err
+	}
+	events := make(chan *pb.LobbyEvent)
+	watchErr := make(chan error, 1)
+	go func() {
+		for {
+			e, err := watch.Recv()
+			if err != nil {
+				watchErr <- err
+				return
+			}
+			select {
+			case events <- e:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	ap.MouseClickOn()
+	defer ap.MouseClickOff()
+	games := make(map[int32]*pb.GameSummary)
+	selected := int32(0)
+	notice := ""
+	id, action := int32(0), lobbyQuit
+	err = ap.FPSTicks(ctx, func(context.Context) bool {
+		select {
+		case e := <-events:
+			if e.GetKind() == pb.LobbyEventKind_removed {
+				delete(games, e.GetGame().GetId())
+			} else {
+				games[e.GetGame().GetId()] = e.GetGame()
+			}
+		case err := <-watchErr:
+			notice = "Lobby unavailable: " + status.Convert(err).Message()
+		default:
+		}
+		list := make([]*pb.GameSummary, 0, len(games))
+		for _, g := range games {
+			list = append(list, g)
+		}
+		slices.SortFunc(list, func(a, b *pb.GameSummary) int {
+			return cmp.Or(a.GetCreatedAt().AsTime().Compare(b.GetCreatedAt().AsTime()), cmp.Compare(a.GetId(), b.GetId()))
+		})
+		current := slices.IndexFunc(list, func(g *pb.GameSummary) bool { return g.GetId() == selected })
+		if current < 0 && len(list) > 0 {
+			current = 0
+		}
+		switch string(ap.Data) {
+		case "q":
+			return false
+		case "n":
+			action = lobbyNew
+			return false
+		case "m":
+			action = lobbyMatch
+			return false
+		case "\x1b[A", "k":
+			current = max(current-1, 0)
+		case "\x1b[B", "j":
+			current = min(current+1, len(list)-1)
+		case "\r", "\n":
+			if current >= 0 {
+				id, action = list[current].GetId(), lobbyJoin
+				return false
+			}
+		}
+		// mouse rows start at 1
+		if row := ap.My - 1 - lobbyTop; ap.LeftClick() && row >= 0 && row < len(list) {
+			id, action = list[row].GetId(), lobbyJoin
+			return false
+		}
+		if current >= 0 {
+			selected = list[current].GetId()
+		}
+
+		ap.ClearScreen()
+		ap.WriteAtStr(1, 0, "Open games - arrows or click to pick, enter to join, n for a new game, m to find an opponent, q to quit")
+		ap.WriteAtStr(1, lobbyTop-1, fmt.Sprintf("  %-11s %-16s %-16s %-12s %s", "ID", "Host", "Board", "Match", "Watching"))
+		for i, g := range list {
+			if lobbyTop+i >= ap.H-1 {
+				break
+			}
+			match := "endless"
+			if g.GetFirstTo() > 0 {
+				match = fmt.Sprintf("first to %d", g.GetFirstTo())
+			}
+			if g.GetRated() {
+				match += ", rated"
+			}
+			line := fmt.Sprintf("  %-11d %-16.16s %-16s %-18s %d", g.GetId(), playerName(g.GetHostName(), "anonymous"),
+				g.GetVariant().Engine(), match, g.GetSpectators())
+			if i == current {
+				line = ansipixels.Inverse + ">" + line[1:] + ansipixels.Reset
+			}
+			ap.WriteAtStr(1, lobbyTop+i, line)
+		}
+		if len(list) == 0 {
+			ap.WriteAtStr(3, lobbyTop, "No open game yet, press n to start one")
+		}
+		if notice != "" {
+			ap.WriteCentered(ap.H-1, "%s", notice)
+		}
+		return true
+	})
+	return id, action, err
+}
+
+// findMatch waits in the matchmaking queue until the server pairs the player, returning the seat
+// assigned or nil if the player gave up.
+func findMatch(ap *ansipixels.AnsiPixels, client pb.Connect4Client, req *pb.FindMatchRequest) (*pb.GameIDAndTeam, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.FindMatch(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	updates := make(chan *pb.MatchUpdate)
+	streamErr := make(chan error, 1)
+	go func() {
+		for {
+			update, err := stream.Recv()
+			if err != nil {
+				streamErr <- err
+				return
+			}
+			select {
+			case updates <- update:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	start := time.Now()
+	var seat *pb.GameIDAndTeam
+	var recvErr error
+	err = ap.FPSTicks(ctx, func(context.Context) bool {
+		select {
+		case update := <-updates:
+			seat = update.GetMatch()
+			if seat != nil {
+				return false
+			}
+		case recvErr = <-streamErr:
+			return false
+		default:
+		}
+		if len(ap.Data) > 0 && ap.Data[0] == 'q' {
+			return false
+		}
+		ap.ClearScreen()
+		ap.WriteCentered(ap.H/2, "Looking for an opponent on a %s board... %s", req.GetVariant().Engine(),
+			time.Since(start).Truncate(time.Second))
+		ap.WriteCentered(ap.H/2+1, "press q to give up")
+		return true
+	})
+	if err == nil {
+		err = recvErr
+	}
+	return seat, err
+}
@@ -0,0 +1,61 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/geofpwhite/connect4-grpc/pb"
+)
+
+// ratingMessage tells how a rated board changed the rating of the player, or of both players for
+// spectators.
+func ratingMessage(changes *pb.RatingChanges, team pb.Team) string {
+	switch {
+	case changes == nil:
+		return ""
+	case team == pb.Team_red:
+		return " " + ratingChange(changes.GetRed())
+	case team == pb.Team_yellow:
+		return " " + ratingChange(changes.GetYellow())
+	}
+	return fmt.Sprintf(", Red %s, Yellow %s", ratingChange(changes.GetRed()), ratingChange(changes.GetYellow()))
+}
+
+func ratingChange(c *pb.RatingChange) string {
+	before, after := math.Round(c.GetBefore()), math.Round(c.GetAfter())
+	return fmt.Sprintf("%.0f → %.0f (%+.0f)", before, after, after-before)
+}
+
+// printRatings prints the profile of username, if any, then the count best rated players.
+func printRatings(client pb.Connect4Client, username string, count int) error {
+	ctx := context.Background()
+	if username != "" {
+		p, err := client.GetPlayerProfile(ctx, &pb.PlayerProfileRequest{Username: &username})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s: %.0f (±%.0f), %d wins, %d losses, %d draws", p.GetUsername(), p.GetRating(), 2*p.GetDeviation(),
+			p.GetWins(), p.GetLosses(), p.GetDraws())
+		if p.GetRank() > 0 {
+			fmt.Printf(", ranked #%d", p.GetRank())
+		}
+		fmt.Printf(", playing since %s\n", p.GetCreatedAt().AsTime().Format("2006-01-02"))
+	}
+	req := &pb.LeaderboardRequest{}
+	for count > 0 {
+		page, err := client.GetLeaderboard(ctx, req)
+		if err != nil {
+			return err
+		}
+		for _, p := range page.GetPlayers()[:min(count, len(page.GetPlayers()))] {
+			fmt.Printf("%4d. %-20s %5.0f  %d/%d/%d\n", p.GetRank(), p.GetUsername(), p.GetRating(), p.GetWins(), p.GetLosses(), p.GetDraws())
+			count--
+		}
+		if page.GetNextPageToken() == "" {
+			break
+		}
+		req.PageToken = page.NextPageToken
+	}
+	return nil
+}
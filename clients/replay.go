@@ -0,0 +1,101 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+
+	"fortio.org/terminal/ansipixels"
+	"github.com/geofpwhite/connect4-grpc/engine"
+	"github.com/geofpwhite/connect4-grpc/pb"
+)
+
+// viewReplay shows the boards of a game one move at a time, the player stepping through them with
+// the arrow keys until they quit.
+func viewReplay(ap *ansipixels.AnsiPixels, client pb.Connect4Client, id int32) error {
+	history, err := client.GetGameHistory(context.Background(), &pb.GameID{Id: &id})
+	if err != nil {
+		return err
+	}
+	boards := history.GetBoards()
+	if len(boards) == 0 {
+		return fmt.Errorf("game %d has no board", id)
+	}
+	current := len(boards) - 1
+	played := len(boards[current].GetMoves())
+	ap.TrueColor = true
+	ap.HideCursor()
+	frame := 0
+	return ap.FPSTicks(context.Background(), func(context.Context) bool {
+		frame = (frame + 1) % 60
+		moves := boards[current].GetMoves()
+		switch string(ap.Data) {
+		case "q":
+			return false
+		case "\x1b[C", "l":
+			played = min(played+1, len(moves))
+		case "\x1b[D", "h":
+			played = max(played-1, 0)
+		case "\x1b[H", "g":
+			played = 0
+		case "\x1b[F", "G":
+			played = len(moves)
+		case "n":
+			if current < len(boards)-1 {
+				current, played = current+1, 0
+			}
+		case "p":
+			if current > 0 {
+				current, played = current-1, 0
+			}
+		}
+		moves = boards[current].GetMoves()
+		board, err := replayed(history.GetVariant().Engine(), boards[current], played)
+		if err != nil {
+			return false
+		}
+		winningLine := make(map[engine.Coord]bool)
+		for _, c := range board.WinningLine() {
+			winningLine[c] = true
+		}
+
+		ap.ClearScreen()
+		l := newLayout(ap, board, 0)
+		drawDiscs(ap, l, board, winningLine, frame < 30)
+		drawColumns(ap, l, board)
+		drawScoreboard(ap, l, history.GetScore(), history.GetPlayers())
+		ap.WriteAtStr(1, 0, fmt.Sprintf("Game %d - left/right to step, home/end, n/p for the next or previous board, q to quit", id))
+		status := fmt.Sprintf("Board %d/%d, move %d/%d", current+1, len(boards), played, len(moves))
+		if played > 0 {
+			last := moves[played-1]
+			status += fmt.Sprintf(", %s in column %d at %s", last.GetTeam(), last.GetColumn(),
+				last.GetPlayedAt().AsTime().Local().Format("15:04:05"))
+		}
+		msg := resultMessage(boards[current].GetResult(), boards[current].GetEndReason(), pb.Team_empty)
+		if msg != "" && played == len(moves) {
+			status += " - " + msg
+		}
+		ap.WriteCentered(ap.H-1, "%s", status)
+		return true
+	})
+}
+
+// replayed returns the board of history once its first played moves are dropped.
+func replayed(variant engine.Variant, history *pb.BoardHistory, played int) (*engine.Board, error) {
+	first := history.GetFirst()
+	if first == pb.Team_empty && len(history.GetMoves()) > 0 {
+		first = history.GetMoves()[0].GetTeam() // from a server not telling who started
+	}
+	if first == pb.Team_empty {
+		first = pb.Team_red
+	}
+	board, err := engine.NewStarting(variant, engine.Piece(first))
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range history.GetMoves()[:played] {
+		if _, err := board.Drop(int(m.GetColumn()) - 1); err != nil {
+			break // the server sent an invalid history, show what could be played
+		}
+	}
+	return board, nil
+}
@@ -0,0 +1,29 @@
+package clients
+
+import (
+	"testing"
+
+	"github.com/geofpwhite/connect4-grpc/engine"
+	"github.com/geofpwhite/connect4-grpc/pb"
+)
+
+func TestReplayedYellowFirst(t *testing.T) {
+	column, yellow, red := int32(5), pb.Team_yellow, pb.Team_red
+	moves := []*pb.Move{{Column: &column, Team: &yellow}, {Column: &column, Team: &red}}
+	for _, history := range []*pb.BoardHistory{
+		{Moves: moves, First: &yellow},
+		{Moves: moves}, // from a server not telling who started
+	} {
+		board, err := replayed(engine.DefaultVariant, history, 1)
+		if err != nil {
+			t.Fatalf("Failed to replay the board: %v", err)
+		}
+		if board.At(0, 4) != engine.Yellow || board.Turn() != engine.Red {
+			t.Fatalf("expected yellow's disc in column 5 and red to move, got %v and %v to move", board.At(0, 4), board.Turn())
+		}
+	}
+	board, err := replayed(engine.DefaultVariant, &pb.BoardHistory{First: &yellow}, 0)
+	if err != nil || board.Turn() != engine.Yellow {
+		t.Fatalf("expected yellow to open an empty board yellow starts, got %v (%v)", board, err)
+	}
+}
@@ -0,0 +1,94 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/geofpwhite/connect4-grpc/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	minBackoff = 250 * time.Millisecond
+	maxBackoff = 5 * time.Second
+)
+
+// seatStream is the CommunicateState stream of a player, reopened with the resume token of the seat
+// whenever the connection drops.
+type seatStream struct {
+	client pb.Connect4Client
+	seat   *pb.GameIDAndTeam
+	mut    sync.Mutex
+	stream grpc.BidiStreamingClient[pb.Input, pb.State]
+}
+
+func newSeatStream(client pb.Connect4Client, seat *pb.GameIDAndTeam) (*seatStream, error) {
+	s := &seatStream{client: client, seat: seat}
+	return s, s.connect()
+}
+
+// connect opens a stream and registers it on the seat, the server answering with the current state.
+func (s *seatStream) connect() error {
+	stream, err := s.client.CommunicateState(context.Background())
+	if err != nil {
+		return err
+	}
+	register := int32(-1)
+	err = stream.Send(&pb.Input{GameId: s.seat.Id, Action: &pb.Input_Column{Column: register}, InputTeam: s.seat.Team,
+		ResumeToken: s.seat.ResumeToken})
+	if err != nil {
+		return err
+	}
+	s.mut.Lock()
+	s.stream = stream
+	s.mut.Unlock()
+	return nil
+}
+
+// Send sends the action of in for the seat. Actions sent while the connection is down are lost.
+func (s *seatStream) Send(in *pb.Input) error {
+	s.mut.Lock()
+	stream := s.stream
+	s.mut.Unlock()
+	in.GameId, in.InputTeam = s.seat.Id, s.seat.Team
+	return stream.Send(in)
+}
+
+// Recv returns the next state, reconnecting with exponential backoff when the stream broke. It only
+// fails once the seat can't be resumed anymore.
+func (s *seatStream) Recv() (*pb.State, error) {
+	for {
+		s.mut.Lock()
+		stream := s.stream
+		s.mut.Unlock()
+		state, err := stream.Recv()
+		if err == nil || errors.Is(err, io.EOF) || !retryable(err) {
+			return state, err
+		}
+		for backoff := minBackoff; ; backoff = min(2*backoff, maxBackoff) {
+			time.Sleep(backoff)
+			err = s.connect()
+			if err == nil {
+				break
+			}
+			if !retryable(err) {
+				return nil, err
+			}
+		}
+	}
+}
+
+// retryable reports whether err is a connection problem rather than the server refusing the seat.
+func retryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.Canceled, codes.DeadlineExceeded, codes.Internal, codes.Unknown, codes.ResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
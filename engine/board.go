@@ -0,0 +1,352 @@
+// Package engine implements the connect 4 rules independently of the grpc server and clients.
+package engine
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	DefaultRows    = 8
+	DefaultCols    = 8
+	DefaultConnect = 4
+
+	MinSize    = 4
+	MaxSize    = 16
+	MinConnect = 3
+)
+
+var (
+	ErrColumnOutOfRange = errors.New("column out of range")
+	ErrColumnFull       = errors.New("column is full")
+	ErrGameOver         = errors.New("game is over")
+	ErrNoMoves          = errors.New("no moves to undo")
+	ErrInvalidGrid      = errors.New("invalid grid")
+	ErrInvalidVariant   = errors.New("invalid variant")
+)
+
+// Variant describes the board dimensions and how many aligned discs win.
+type Variant struct {
+	Rows, Cols, Connect int
+}
+
+// DefaultVariant is the 8x8 connect 4 board the server has always used.
+var DefaultVariant = Variant{DefaultRows, DefaultCols, DefaultConnect}
+
+// Classic is the 7 columns by 6 rows commercial board.
+var Classic = Variant{Rows: 6, Cols: 7, Connect: 4}
+
+func (v Variant) Validate() error {
+	if v.Rows < MinSize || v.Rows > MaxSize || v.Cols < MinSize || v.Cols > MaxSize {
+		return fmt.Errorf("%w: board must be between %dx%d and %dx%d, got %dx%d",
+			ErrInvalidVariant, MinSize, MinSize, MaxSize, MaxSize, v.Cols, v.Rows)
+	}
+	if v.Connect < MinConnect || v.Connect > max(v.Rows, v.Cols) {
+		return fmt.Errorf("%w: connect must be between %d and %d, got %d",
+			ErrInvalidVariant, MinConnect, max(v.Rows, v.Cols), v.Connect)
+	}
+	return nil
+}
+
+func (v Variant) String() string {
+	return fmt.Sprintf("%dx%d connect %d", v.Cols, v.Rows, v.Connect)
+}
+
+// Piece is the content of a cell, its values match pb.Team.
+type Piece int8
+
+const (
+	Empty Piece = iota
+	Red
+	Yellow
+)
+
+func (p Piece) Opponent() Piece {
+	switch p {
+	case Red:
+		return Yellow
+	case Yellow:
+		return Red
+	case Empty:
+	}
+	return Empty
+}
+
+func (p Piece) String() string {
+	switch p {
+	case Red:
+		return "red"
+	case Yellow:
+		return "yellow"
+	case Empty:
+	}
+	return "empty"
+}
+
+// Coord is a cell position, row 0 is the bottom of the board and col 0 the leftmost column.
+type Coord struct{ Row, Col int }
+
+type Board struct {
+	rows, cols, connect int
+	cells               []Piece // row major, row 0 at the bottom
+	heights             []int
+	moves               []int
+	turn                Piece
+	winner              Piece
+	line                []Coord
+}
+
+// NewBoard returns an empty board of the default variant with red to move.
+func NewBoard() *Board {
+	return newBoard(DefaultVariant)
+}
+
+// New returns an empty board of the given variant with red to move.
+func New(v Variant) (*Board, error) {
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
+	return newBoard(v), nil
+}
+
+// NewStarting returns an empty board of the given variant with first to move.
+func NewStarting(v Variant, first Piece) (*Board, error) {
+	b, err := New(v)
+	if err != nil {
+		return nil, err
+	}
+	if first != Red && first != Yellow {
+		return nil, fmt.Errorf("%w: %v can't move first", ErrInvalidGrid, first)
+	}
+	b.turn = first
+	return b, nil
+}
+
+func newBoard(v Variant) *Board {
+	return &Board{
+		rows:    v.Rows,
+		cols:    v.Cols,
+		connect: v.Connect,
+		cells:   make([]Piece, v.Rows*v.Cols),
+		heights: make([]int, v.Cols),
+		turn:    Red,
+	}
+}
+
+// FromGrid rebuilds a board from a grid indexed [row][col] with row 0 at the bottom. The side to move
+// is deduced from the disc count, red moving first unless yellow has one more disc. Move history is
+// not known so Undo is unavailable.
+func FromGrid(grid [][]Piece, connect int) (*Board, error) {
+	if len(grid) == 0 {
+		return nil, ErrInvalidGrid
+	}
+	v := Variant{Rows: len(grid), Cols: len(grid[0]), Connect: connect}
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
+	b := newBoard(v)
+	reds, yellows := 0, 0
+	for r, row := range grid {
+		if len(row) != b.cols {
+			return nil, ErrInvalidGrid
+		}
+		for c, p := range row {
+			switch p {
+			case Red:
+				reds++
+			case Yellow:
+				yellows++
+			case Empty:
+				continue
+			default:
+				return nil, ErrInvalidGrid
+			}
+			if r > 0 && grid[r-1][c] == Empty {
+				return nil, ErrInvalidGrid // floating disc
+			}
+			b.cells[b.index(r, c)] = p
+			b.heights[c] = r + 1
+		}
+	}
+	switch reds - yellows {
+	case 0:
+		b.turn = Red
+	case 1:
+		b.turn = Yellow
+	case -1: // yellow moved first
+		b.turn = Red
+	default:
+		return nil, ErrInvalidGrid
+	}
+	b.findWinner()
+	return b, nil
+}
+
+func (b *Board) index(row, col int) int {
+	return row*b.cols + col
+}
+
+func (b *Board) Rows() int     { return b.rows }
+func (b *Board) Cols() int     { return b.cols }
+func (b *Board) ConnectN() int { return b.connect }
+
+func (b *Board) Variant() Variant {
+	return Variant{Rows: b.rows, Cols: b.cols, Connect: b.connect}
+}
+
+// Turn returns the piece that plays next.
+func (b *Board) Turn() Piece { return b.turn }
+
+// At returns the piece at the given cell, Empty when out of bounds.
+func (b *Board) At(row, col int) Piece {
+	if row < 0 || row >= b.rows || col < 0 || col >= b.cols {
+		return Empty
+	}
+	return b.cells[b.index(row, col)]
+}
+
+// Grid returns a copy of the board indexed [row][col].
+func (b *Board) Grid() [][]Piece {
+	grid := make([][]Piece, b.rows)
+	for r := range grid {
+		grid[r] = make([]Piece, b.cols)
+		copy(grid[r], b.cells[r*b.cols:(r+1)*b.cols])
+	}
+	return grid
+}
+
+// Moves returns the columns played so far, in order.
+func (b *Board) Moves() []int {
+	return append([]int(nil), b.moves...)
+}
+
+func (b *Board) Clone() *Board {
+	c := *b
+	c.cells = append([]Piece(nil), b.cells...)
+	c.heights = append([]int(nil), b.heights...)
+	c.moves = append([]int(nil), b.moves...)
+	c.line = append([]Coord(nil), b.line...)
+	return &c
+}
+
+// CanDrop reports whether a disc can be dropped in column.
+func (b *Board) CanDrop(column int) bool {
+	return b.winner == Empty && column >= 0 && column < b.cols && b.heights[column] < b.rows
+}
+
+// LegalMoves returns the columns that accept a disc, none once the game is over.
+func (b *Board) LegalMoves() []int {
+	moves := make([]int, 0, b.cols)
+	for c := range b.cols {
+		if b.CanDrop(c) {
+			moves = append(moves, c)
+		}
+	}
+	return moves
+}
+
+// Drop plays a disc for the side to move in column and returns the row it landed on.
+func (b *Board) Drop(column int) (int, error) {
+	switch {
+	case b.winner != Empty:
+		return -1, ErrGameOver
+	case column < 0 || column >= b.cols:
+		return -1, ErrColumnOutOfRange
+	case b.heights[column] >= b.rows:
+		return -1, ErrColumnFull
+	}
+	row := b.heights[column]
+	b.cells[b.index(row, column)] = b.turn
+	b.heights[column]++
+	b.moves = append(b.moves, column)
+	if line := b.lineThrough(row, column); line != nil {
+		b.winner = b.turn
+		b.line = line
+	}
+	b.turn = b.turn.Opponent()
+	return row, nil
+}
+
+// Undo takes back the last move.
+func (b *Board) Undo() error {
+	if len(b.moves) == 0 {
+		return ErrNoMoves
+	}
+	column := b.moves[len(b.moves)-1]
+	b.moves = b.moves[:len(b.moves)-1]
+	b.heights[column]--
+	b.cells[b.index(b.heights[column], column)] = Empty
+	b.turn = b.turn.Opponent()
+	b.winner = Empty
+	b.line = nil
+	return nil
+}
+
+// Winner returns the piece that connected a line, Empty if nobody has yet.
+func (b *Board) Winner() Piece { return b.winner }
+
+// WinningLine returns the cells of the winning line, nil if there is no winner.
+func (b *Board) WinningLine() []Coord {
+	return append([]Coord(nil), b.line...)
+}
+
+// IsFull reports whether every cell holds a disc.
+func (b *Board) IsFull() bool {
+	for _, h := range b.heights {
+		if h < b.rows {
+			return false
+		}
+	}
+	return true
+}
+
+// IsDraw reports whether the board is full without a winner.
+func (b *Board) IsDraw() bool {
+	return b.winner == Empty && b.IsFull()
+}
+
+// IsOver reports whether the game ended, by a win or a draw.
+func (b *Board) IsOver() bool {
+	return b.winner != Empty || b.IsFull()
+}
+
+var directions = [...]Coord{{0, 1}, {1, 0}, {1, 1}, {1, -1}}
+
+// lineThrough returns the first line of at least connect discs going through the given cell, in
+// the order of directions, nil if there is none. The line has all its discs, even past connect.
+func (b *Board) lineThrough(row, col int) []Coord {
+	p := b.At(row, col)
+	if p == Empty {
+		return nil
+	}
+	for _, d := range directions {
+		start, end := 0, 0
+		for b.At(row+(start-1)*d.Row, col+(start-1)*d.Col) == p {
+			start--
+		}
+		for b.At(row+(end+1)*d.Row, col+(end+1)*d.Col) == p {
+			end++
+		}
+		if end-start+1 < b.connect {
+			continue
+		}
+		line := make([]Coord, 0, end-start+1)
+		for i := start; i <= end; i++ {
+			line = append(line, Coord{row + i*d.Row, col + i*d.Col})
+		}
+		return line
+	}
+	return nil
+}
+
+func (b *Board) findWinner() {
+	for r := range b.rows {
+		for c := range b.cols {
+			if line := b.lineThrough(r, c); line != nil {
+				b.winner = b.At(r, c)
+				b.line = line
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+)
+
+func play(t *testing.T, b *Board, columns ...int) {
+	t.Helper()
+	for _, c := range columns {
+		if _, err := b.Drop(c); err != nil {
+			t.Fatalf("drop in column %d: %v", c, err)
+		}
+	}
+}
+
+func TestDropStacks(t *testing.T) {
+	b := NewBoard()
+	for i := range b.Rows() {
+		row, err := b.Drop(3)
+		if err != nil {
+			t.Fatalf("drop %d: %v", i, err)
+		}
+		if row != i {
+			t.Fatalf("expected row %d, got %d", i, row)
+		}
+	}
+	if _, err := b.Drop(3); !errors.Is(err, ErrColumnFull) {
+		t.Fatalf("expected ErrColumnFull, got %v", err)
+	}
+	if _, err := b.Drop(-1); !errors.Is(err, ErrColumnOutOfRange) {
+		t.Fatalf("expected ErrColumnOutOfRange, got %v", err)
+	}
+	if _, err := b.Drop(b.Cols()); !errors.Is(err, ErrColumnOutOfRange) {
+		t.Fatalf("expected ErrColumnOutOfRange, got %v", err)
+	}
+	if len(b.LegalMoves()) != b.Cols()-1 {
+		t.Fatalf("expected %d legal moves, got %v", b.Cols()-1, b.LegalMoves())
+	}
+}
+
+func TestWinner(t *testing.T) {
+	tests := []struct {
+		name    string
+		columns []int
+		winner  Piece
+	}{
+		{"horizontal", []int{0, 0, 1, 1, 2, 2, 3}, Red},
+		{"vertical", []int{0, 1, 0, 1, 0, 1, 5, 1}, Yellow},
+		{"diagonal", []int{0, 1, 1, 2, 2, 3, 2, 3, 3, 6, 3}, Red},
+		{"anti diagonal", []int{3, 2, 2, 1, 1, 0, 1, 0, 0, 6, 0}, Red},
+		{"none", []int{0, 1, 2, 3, 4, 5}, Empty},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBoard()
+			play(t, b, tt.columns...)
+			if b.Winner() != tt.winner {
+				t.Fatalf("expected winner %v, got %v", tt.winner, b.Winner())
+			}
+			if tt.winner == Empty {
+				return
+			}
+			line := b.WinningLine()
+			if len(line) != b.ConnectN() {
+				t.Fatalf("expected a line of %d, got %v", b.ConnectN(), line)
+			}
+			for _, c := range line {
+				if b.At(c.Row, c.Col) != tt.winner {
+					t.Fatalf("cell %v of the winning line is %v", c, b.At(c.Row, c.Col))
+				}
+			}
+			if _, err := b.Drop(4); !errors.Is(err, ErrGameOver) {
+				t.Fatalf("expected ErrGameOver, got %v", err)
+			}
+			if len(b.LegalMoves()) != 0 {
+				t.Fatalf("expected no legal moves, got %v", b.LegalMoves())
+			}
+		})
+	}
+}
+
+func TestUndo(t *testing.T) {
+	b := NewBoard()
+	if err := b.Undo(); !errors.Is(err, ErrNoMoves) {
+		t.Fatalf("expected ErrNoMoves, got %v", err)
+	}
+	play(t, b, 0, 0, 1, 1, 2, 2, 3)
+	if err := b.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if b.Winner() != Empty || b.Turn() != Red || b.At(0, 3) != Empty {
+		t.Fatalf("undo did not restore the position: winner %v turn %v", b.Winner(), b.Turn())
+	}
+	if got := b.Moves(); len(got) != 6 {
+		t.Fatalf("expected 6 moves, got %v", got)
+	}
+}
+
+func TestDraw(t *testing.T) {
+	b := NewBoard()
+	// columns are filled in pairs shifted by one so no four ever line up
+	order := []int{0, 1, 2, 3, 4, 5, 6, 7}
+	for pass := range b.Rows() {
+		for _, c := range order {
+			col := c
+			if pass%4 >= 2 {
+				col = order[len(order)-1-c]
+			}
+			play(t, b, col)
+		}
+	}
+	if !b.IsFull() {
+		t.Fatal("expected a full board")
+	}
+	if b.Winner() != Empty || !b.IsDraw() || !b.IsOver() {
+		t.Fatalf("expected a draw, winner %v line %v", b.Winner(), b.WinningLine())
+	}
+}
+
+func TestFromGrid(t *testing.T) {
+	b := NewBoard()
+	play(t, b, 3, 3, 4)
+	rebuilt, err := FromGrid(b.Grid(), b.ConnectN())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rebuilt.Turn() != Yellow || rebuilt.At(0, 4) != Red || rebuilt.At(1, 3) != Yellow {
+		t.Fatal("grid was not rebuilt correctly")
+	}
+	grid := b.Grid()
+	grid[3][0] = Red
+	if _, err := FromGrid(grid, b.ConnectN()); !errors.Is(err, ErrInvalidGrid) {
+		t.Fatalf("expected ErrInvalidGrid for a floating disc, got %v", err)
+	}
+
+	b, err = NewStarting(DefaultVariant, Yellow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	play(t, b, 3)
+	if rebuilt, err = FromGrid(b.Grid(), b.ConnectN()); err != nil || rebuilt.Turn() != Red {
+		t.Fatalf("expected red to move after yellow opened, got %v, %v", rebuilt, err)
+	}
+}
+
+func TestVariants(t *testing.T) {
+	b, err := New(Classic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Rows() != 6 || b.Cols() != 7 {
+		t.Fatalf("expected a 7x6 board, got %dx%d", b.Cols(), b.Rows())
+	}
+	connect3, err := New(Variant{Rows: 5, Cols: 5, Connect: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	play(t, connect3, 0, 0, 1, 1, 2)
+	if connect3.Winner() != Red || len(connect3.WinningLine()) != 3 {
+		t.Fatalf("expected red to win with 3 in a row, got %v", connect3.Winner())
+	}
+	connect5, err := New(Variant{Rows: 7, Cols: 9, Connect: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	play(t, connect5, 0, 0, 1, 1, 2, 2, 3, 3)
+	if connect5.Winner() != Empty {
+		t.Fatal("4 in a row should not win connect 5")
+	}
+	play(t, connect5, 4)
+	if connect5.Winner() != Red {
+		t.Fatal("expected red to win with 5 in a row")
+	}
+	for _, v := range []Variant{{3, 7, 4}, {6, 17, 4}, {6, 7, 2}, {6, 7, 8}} {
+		if _, err := New(v); !errors.Is(err, ErrInvalidVariant) {
+			t.Errorf("expected %v to be rejected, got %v", v, err)
+		}
+	}
+}
@@ -4,6 +4,7 @@ go 1.25.0
 
 require (
 	fortio.org/log v1.17.2
+	go.etcd.io/bbolt v1.4.3
 	google.golang.org/grpc v1.75.0
 	google.golang.org/protobuf v1.36.9
 )
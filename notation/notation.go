@@ -0,0 +1,230 @@
+// Package notation reads and writes connect 4 games as text, in a format modeled on chess PGN:
+//
+//	[Red "alice"]
+//	[Yellow "bob"]
+//	[Date "2026.10.18"]
+//	[Variant "7x6 connect 4"]
+//	[Result "1-0"]
+//
+//	1. 4 4 2. 3 5 3. 2 6 4. 1 1-0
+//
+// The header is a list of tags, one per line, each a name and a value quoted like a Go string.
+// Variant is the board as columns x rows and the number of aligned discs needed to win, the 7x6
+// connect 4 board when missing. Result is "1-0" when red won, "0-1" when yellow won, "1/2-1/2" for
+// a draw and "*" for a game still going on. First is the color moving first, "Red" when missing.
+// Other tags are kept as is.
+//
+// The movetext follows a blank line. It lists the 1 based columns played, in turn from the first
+// player, and may end with the result. Move numbers ("1.") and comments between braces are ignored.
+package notation
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/geofpwhite/connect4-grpc/engine"
+)
+
+const (
+	RedWon     = "1-0"
+	YellowWon  = "0-1"
+	Draw       = "1/2-1/2"
+	InProgress = "*"
+)
+
+var ErrSyntax = errors.New("invalid notation")
+
+type Tag struct {
+	Name, Value string
+}
+
+// Game is a game read from or written as text.
+type Game struct {
+	Tags  []Tag
+	Moves []int // 0 based columns
+}
+
+// Tag returns the value of the first tag called name, empty if there is none.
+func (g *Game) Tag(name string) string {
+	for _, t := range g.Tags {
+		if t.Name == name {
+			return t.Value
+		}
+	}
+	return ""
+}
+
+// Variant returns the board of the Variant tag.
+func (g *Game) Variant() (engine.Variant, error) {
+	tag := g.Tag("Variant")
+	if tag == "" {
+		return engine.Classic, nil
+	}
+	var v engine.Variant
+	if _, err := fmt.Sscanf(tag, "%dx%d connect %d", &v.Cols, &v.Rows, &v.Connect); err != nil {
+		return v, fmt.Errorf("%w: variant %q isn't like \"7x6 connect 4\"", ErrSyntax, tag)
+	}
+	return v, v.Validate()
+}
+
+// First returns the color of the First tag.
+func (g *Game) First() (engine.Piece, error) {
+	switch tag := g.Tag("First"); tag {
+	case "", "Red":
+		return engine.Red, nil
+	case "Yellow":
+		return engine.Yellow, nil
+	default:
+		return engine.Empty, fmt.Errorf("%w: first player %q is neither Red nor Yellow", ErrSyntax, tag)
+	}
+}
+
+// Board plays the moves on the board of the variant.
+func (g *Game) Board() (*engine.Board, error) {
+	v, err := g.Variant()
+	if err != nil {
+		return nil, err
+	}
+	first, err := g.First()
+	if err != nil {
+		return nil, err
+	}
+	b, err := engine.NewStarting(v, first)
+	if err != nil {
+		return nil, err
+	}
+	for i, col := range g.Moves {
+		if _, err := b.Drop(col); err != nil {
+			return nil, fmt.Errorf("move %d in column %d: %w", i+1, col+1, err)
+		}
+	}
+	return b, nil
+}
+
+// ResultOf returns the result token of the board.
+func ResultOf(b *engine.Board) string {
+	switch {
+	case b.Winner() == engine.Red:
+		return RedWon
+	case b.Winner() == engine.Yellow:
+		return YellowWon
+	case b.IsDraw():
+		return Draw
+	}
+	return InProgress
+}
+
+// String writes g in the notation, ending the movetext with the Result tag.
+func (g *Game) String() string {
+	var sb strings.Builder
+	for _, t := range g.Tags {
+		fmt.Fprintf(&sb, "[%s %s]\n", t.Name, strconv.Quote(t.Value))
+	}
+	sb.WriteString("\n")
+	for i, col := range g.Moves {
+		if i%2 == 0 {
+			fmt.Fprintf(&sb, "%d. ", i/2+1)
+		}
+		fmt.Fprintf(&sb, "%d ", col+1)
+	}
+	result := g.Tag("Result")
+	if result == "" {
+		result = InProgress
+	}
+	sb.WriteString(result + "\n")
+	return sb.String()
+}
+
+// Parse reads a game, checking the moves are legal on its board.
+func Parse(text string) (*Game, error) {
+	g := &Game{}
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	i := 0
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "[") {
+			break
+		}
+		tag, err := parseTag(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		g.Tags = append(g.Tags, tag)
+	}
+	result, err := g.parseMoves(strings.Join(lines[i:], "\n"))
+	if err != nil {
+		return nil, err
+	}
+	if tag := g.Tag("Result"); result != "" && tag != "" && tag != result {
+		return nil, fmt.Errorf("%w: movetext ends with %s but the Result tag is %s", ErrSyntax, result, tag)
+	}
+	if _, err := g.Board(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func parseTag(line string) (Tag, error) {
+	inner, ok := strings.CutSuffix(line[1:], "]")
+	name, quoted, found := strings.Cut(inner, " ")
+	if !ok || !found || name == "" {
+		return Tag{}, fmt.Errorf("%w: tag %q isn't like [Name \"value\"]", ErrSyntax, line)
+	}
+	value, err := strconv.Unquote(strings.TrimSpace(quoted))
+	if err != nil {
+		return Tag{}, fmt.Errorf("%w: tag %s has an invalid value %s", ErrSyntax, name, quoted)
+	}
+	return Tag{Name: name, Value: value}, nil
+}
+
+// parseMoves reads the columns of movetext into g, returning the result ending it if any.
+func (g *Game) parseMoves(movetext string) (string, error) {
+	var result string
+	for len(movetext) > 0 {
+		if start := strings.IndexByte(movetext, '{'); start >= 0 {
+			end := strings.IndexByte(movetext[start:], '}')
+			if end < 0 {
+				return "", fmt.Errorf("%w: unterminated comment", ErrSyntax)
+			}
+			if err := g.parseTokens(movetext[:start], &result); err != nil {
+				return "", err
+			}
+			movetext = movetext[start+end+1:]
+			continue
+		}
+		if err := g.parseTokens(movetext, &result); err != nil {
+			return "", err
+		}
+		break
+	}
+	return result, nil
+}
+
+func (g *Game) parseTokens(text string, result *string) error {
+	for token := range strings.FieldsSeq(text) {
+		if *result != "" {
+			return fmt.Errorf("%w: %q after the result", ErrSyntax, token)
+		}
+		switch token {
+		case RedWon, YellowWon, Draw, InProgress:
+			*result = token
+			continue
+		}
+		if number, ok := strings.CutSuffix(token, "."); ok {
+			if _, err := strconv.Atoi(number); err == nil {
+				continue
+			}
+		}
+		col, err := strconv.Atoi(token)
+		if err != nil || col < 1 {
+			return fmt.Errorf("%w: %q isn't a column", ErrSyntax, token)
+		}
+		g.Moves = append(g.Moves, col-1)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package notation
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/geofpwhite/connect4-grpc/engine"
+)
+
+func TestRoundTrip(t *testing.T) {
+	g := &Game{
+		Tags: []Tag{
+			{"Red", `alice "the great"`},
+			{"Yellow", `bob\`},
+			{"Variant", "7x6 connect 4"},
+			{"Result", RedWon},
+		},
+		Moves: []int{3, 3, 2, 4, 1, 5, 0},
+	}
+	text := g.String()
+	want := "[Red \"alice \\\"the great\\\"\"]\n[Yellow \"bob\\\\\"]\n[Variant \"7x6 connect 4\"]\n[Result \"1-0\"]\n\n" +
+		"1. 4 4 2. 3 5 3. 2 6 4. 1 1-0\n"
+	if text != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, text)
+	}
+	parsed, err := Parse(text)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	if !slices.Equal(parsed.Tags, g.Tags) || !slices.Equal(parsed.Moves, g.Moves) {
+		t.Fatalf("expected %+v, got %+v", g, parsed)
+	}
+	b, err := parsed.Board()
+	if err != nil {
+		t.Fatalf("Failed to play the moves: %v", err)
+	}
+	if ResultOf(b) != RedWon {
+		t.Fatalf("expected red to have won, got %s", ResultOf(b))
+	}
+}
+
+func TestParse(t *testing.T) {
+	g, err := Parse("{a puzzle}\n4 {center} 4 3\r\n")
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	if !slices.Equal(g.Moves, []int{3, 3, 2}) {
+		t.Fatalf("expected the moves 4 4 3, got %v", g.Moves)
+	}
+	if v, _ := g.Variant(); v != engine.Classic {
+		t.Fatalf("expected the classic board without a Variant tag, got %v", v)
+	}
+	g, err = Parse("[First \"Yellow\"]\n\n4 4 3")
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	if b, _ := g.Board(); b.At(0, 3) != engine.Yellow || b.Turn() != engine.Red {
+		t.Fatalf("expected yellow to have opened, got %v", b.Grid())
+	}
+
+	for name, text := range map[string]string{
+		"bad tag":          "[Red alice]\n\n4",
+		"bad variant":      "[Variant \"big\"]\n\n4",
+		"bad first":        "[First \"Blue\"]\n\n4",
+		"bad column":       "4 x",
+		"after result":     "4 1-0 4",
+		"result mismatch":  "[Result \"0-1\"]\n\n4 1-0",
+		"open comment":     "4 {oops",
+		"column too large": "8",
+		"full column":      "[Variant \"4x4 connect 3\"]\n\n1 1 1 1 1",
+	} {
+		if _, err := Parse(text); err == nil {
+			t.Errorf("%s: expected an error parsing %q", name, text)
+		}
+	}
+	if _, err := Parse("4 x"); !errors.Is(err, ErrSyntax) {
+		t.Fatalf("expected ErrSyntax, got %v", err)
+	}
+}
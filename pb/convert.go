@@ -0,0 +1,58 @@
+package pb
+
+import "github.com/geofpwhite/connect4-grpc/engine"
+
+// Engine returns the variant with the fields left unset replaced by the defaults.
+func (x *Variant) Engine() engine.Variant {
+	variant := engine.DefaultVariant
+	if x.GetRows() != 0 {
+		variant.Rows = int(x.GetRows())
+	}
+	if x.GetColumns() != 0 {
+		variant.Cols = int(x.GetColumns())
+	}
+	if x.GetConnect() != 0 {
+		variant.Connect = int(x.GetConnect())
+	}
+	return variant
+}
+
+func NewVariant(v engine.Variant) *Variant {
+	rows, cols, connect := int32(v.Rows), int32(v.Cols), int32(v.Connect) //nolint:gosec // validated to be small
+	return &Variant{Rows: &rows, Columns: &cols, Connect: &connect}
+}
+
+func NewField(b *engine.Board) *Field {
+	field := &Field{Rows: make([]*Row, b.Rows())}
+	for i, row := range b.Grid() {
+		field.Rows[i] = &Row{Values: make([]Team, len(row))}
+		for j, p := range row {
+			field.Rows[i].Values[j] = Team(p)
+		}
+	}
+	return field
+}
+
+// Board rebuilds the position held by the field, connect 0 meaning the default.
+func (x *Field) Board(connect int) (*engine.Board, error) {
+	if connect == 0 {
+		connect = engine.DefaultConnect
+	}
+	grid := make([][]engine.Piece, len(x.GetRows()))
+	for i, row := range x.GetRows() {
+		grid[i] = make([]engine.Piece, len(row.GetValues()))
+		for j, value := range row.GetValues() {
+			grid[i][j] = engine.Piece(value)
+		}
+	}
+	return engine.FromGrid(grid, connect)
+}
+
+func NewCells(coords []engine.Coord) []*Cell {
+	cells := make([]*Cell, len(coords))
+	for i, c := range coords {
+		row, col := int32(c.Row), int32(c.Col) //nolint:gosec // board is at most engine.MaxSize wide
+		cells[i] = &Cell{Row: &row, Column: &col}
+	}
+	return cells
+}
@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.36.6
+// 	protoc-gen-go v1.36.9
 // 	protoc        v6.31.1
 // source: pb/moves.proto
 
@@ -9,6 +9,8 @@ package pb
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -80,30 +82,3186 @@ func (Team) EnumDescriptor() ([]byte, []int) {
 	return file_pb_moves_proto_rawDescGZIP(), []int{0}
 }
 
+type OfferKind int32
+
+const (
+	OfferKind_draw_offer OfferKind = 1
+	OfferKind_takeback   OfferKind = 2
+	OfferKind_rematch    OfferKind = 3 // offered once the board is over
+)
+
+// Enum value maps for OfferKind.
+var (
+	OfferKind_name = map[int32]string{
+		1: "draw_offer",
+		2: "takeback",
+		3: "rematch",
+	}
+	OfferKind_value = map[string]int32{
+		"draw_offer": 1,
+		"takeback":   2,
+		"rematch":    3,
+	}
+)
+
+func (x OfferKind) Enum() *OfferKind {
+	p := new(OfferKind)
+	*p = x
+	return p
+}
+
+func (x OfferKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OfferKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_pb_moves_proto_enumTypes[1].Descriptor()
+}
+
+func (OfferKind) Type() protoreflect.EnumType {
+	return &file_pb_moves_proto_enumTypes[1]
+}
+
+func (x OfferKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *OfferKind) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = OfferKind(num)
+	return nil
+}
+
+// Deprecated: Use OfferKind.Descriptor instead.
+func (OfferKind) EnumDescriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{1}
+}
+
+type Emote int32
+
+const (
+	Emote_no_emote    Emote = 0
+	Emote_hello       Emote = 1
+	Emote_good_game   Emote = 2
+	Emote_well_played Emote = 3
+	Emote_oops        Emote = 4
+	Emote_thinking    Emote = 5
+	Emote_thanks      Emote = 6
+)
+
+// Enum value maps for Emote.
+var (
+	Emote_name = map[int32]string{
+		0: "no_emote",
+		1: "hello",
+		2: "good_game",
+		3: "well_played",
+		4: "oops",
+		5: "thinking",
+		6: "thanks",
+	}
+	Emote_value = map[string]int32{
+		"no_emote":    0,
+		"hello":       1,
+		"good_game":   2,
+		"well_played": 3,
+		"oops":        4,
+		"thinking":    5,
+		"thanks":      6,
+	}
+)
+
+func (x Emote) Enum() *Emote {
+	p := new(Emote)
+	*p = x
+	return p
+}
+
+func (x Emote) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Emote) Descriptor() protoreflect.EnumDescriptor {
+	return file_pb_moves_proto_enumTypes[2].Descriptor()
+}
+
+func (Emote) Type() protoreflect.EnumType {
+	return &file_pb_moves_proto_enumTypes[2]
+}
+
+func (x Emote) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *Emote) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = Emote(num)
+	return nil
+}
+
+// Deprecated: Use Emote.Descriptor instead.
+func (Emote) EnumDescriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{2}
+}
+
+type EndReason int32
+
+const (
+	EndReason_not_over    EndReason = 0
+	EndReason_aligned     EndReason = 1
+	EndReason_board_full  EndReason = 2
+	EndReason_timeout     EndReason = 3 // the side to move ran out of time
+	EndReason_resigned    EndReason = 4
+	EndReason_agreed_draw EndReason = 5
+)
+
+// Enum value maps for EndReason.
+var (
+	EndReason_name = map[int32]string{
+		0: "not_over",
+		1: "aligned",
+		2: "board_full",
+		3: "timeout",
+		4: "resigned",
+		5: "agreed_draw",
+	}
+	EndReason_value = map[string]int32{
+		"not_over":    0,
+		"aligned":     1,
+		"board_full":  2,
+		"timeout":     3,
+		"resigned":    4,
+		"agreed_draw": 5,
+	}
+)
+
+func (x EndReason) Enum() *EndReason {
+	p := new(EndReason)
+	*p = x
+	return p
+}
+
+func (x EndReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EndReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_pb_moves_proto_enumTypes[3].Descriptor()
+}
+
+func (EndReason) Type() protoreflect.EnumType {
+	return &file_pb_moves_proto_enumTypes[3]
+}
+
+func (x EndReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *EndReason) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = EndReason(num)
+	return nil
+}
+
+// Deprecated: Use EndReason.Descriptor instead.
+func (EndReason) EnumDescriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{3}
+}
+
+type RejectionReason int32
+
+const (
+	RejectionReason_not_rejected         RejectionReason = 0
+	RejectionReason_not_your_turn        RejectionReason = 1
+	RejectionReason_column_full          RejectionReason = 2
+	RejectionReason_column_out_of_range  RejectionReason = 3
+	RejectionReason_wrong_game           RejectionReason = 4 // the input names another game than the one the stream joined
+	RejectionReason_match_over           RejectionReason = 5
+	RejectionReason_wrong_team           RejectionReason = 6 // the input plays for another team than the seat of the stream
+	RejectionReason_board_over           RejectionReason = 7 // resigning or offering after the end of the board
+	RejectionReason_no_offer             RejectionReason = 8 // accepting or declining without an offer from the opponent
+	RejectionReason_offer_pending        RejectionReason = 9 // an offer already waits for an answer
+	RejectionReason_nothing_to_take_back RejectionReason = 10
+	RejectionReason_board_in_play        RejectionReason = 11 // asking for a rematch before the end of the board
+	RejectionReason_invalid_chat         RejectionReason = 12 // empty, too long or holding control characters
+	RejectionReason_shutting_down        RejectionReason = 13 // asking for a rematch while the server shuts down
+	RejectionReason_unknown_action       RejectionReason = 14 // the input has no action the server knows
+)
+
+// Enum value maps for RejectionReason.
+var (
+	RejectionReason_name = map[int32]string{
+		0:  "not_rejected",
+		1:  "not_your_turn",
+		2:  "column_full",
+		3:  "column_out_of_range",
+		4:  "wrong_game",
+		5:  "match_over",
+		6:  "wrong_team",
+		7:  "board_over",
+		8:  "no_offer",
+		9:  "offer_pending",
+		10: "nothing_to_take_back",
+		11: "board_in_play",
+		12: "invalid_chat",
+		13: "shutting_down",
+		14: "unknown_action",
+	}
+	RejectionReason_value = map[string]int32{
+		"not_rejected":         0,
+		"not_your_turn":        1,
+		"column_full":          2,
+		"column_out_of_range":  3,
+		"wrong_game":           4,
+		"match_over":           5,
+		"wrong_team":           6,
+		"board_over":           7,
+		"no_offer":             8,
+		"offer_pending":        9,
+		"nothing_to_take_back": 10,
+		"board_in_play":        11,
+		"invalid_chat":         12,
+		"shutting_down":        13,
+		"unknown_action":       14,
+	}
+)
+
+func (x RejectionReason) Enum() *RejectionReason {
+	p := new(RejectionReason)
+	*p = x
+	return p
+}
+
+func (x RejectionReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RejectionReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_pb_moves_proto_enumTypes[4].Descriptor()
+}
+
+func (RejectionReason) Type() protoreflect.EnumType {
+	return &file_pb_moves_proto_enumTypes[4]
+}
+
+func (x RejectionReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *RejectionReason) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = RejectionReason(num)
+	return nil
+}
+
+// Deprecated: Use RejectionReason.Descriptor instead.
+func (RejectionReason) EnumDescriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{4}
+}
+
+type Result int32
+
+const (
+	Result_in_progress Result = 0
+	Result_red_won     Result = 1
+	Result_yellow_won  Result = 2
+	Result_draw        Result = 3
+)
+
+// Enum value maps for Result.
+var (
+	Result_name = map[int32]string{
+		0: "in_progress",
+		1: "red_won",
+		2: "yellow_won",
+		3: "draw",
+	}
+	Result_value = map[string]int32{
+		"in_progress": 0,
+		"red_won":     1,
+		"yellow_won":  2,
+		"draw":        3,
+	}
+)
+
+func (x Result) Enum() *Result {
+	p := new(Result)
+	*p = x
+	return p
+}
+
+func (x Result) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Result) Descriptor() protoreflect.EnumDescriptor {
+	return file_pb_moves_proto_enumTypes[5].Descriptor()
+}
+
+func (Result) Type() protoreflect.EnumType {
+	return &file_pb_moves_proto_enumTypes[5]
+}
+
+func (x Result) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *Result) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = Result(num)
+	return nil
+}
+
+// Deprecated: Use Result.Descriptor instead.
+func (Result) EnumDescriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{5}
+}
+
+type FirstPlayer int32
+
+const (
+	FirstPlayer_alternate    FirstPlayer = 0
+	FirstPlayer_loser_starts FirstPlayer = 1 // alternating after a draw
+	FirstPlayer_random_first FirstPlayer = 2
+)
+
+// Enum value maps for FirstPlayer.
+var (
+	FirstPlayer_name = map[int32]string{
+		0: "alternate",
+		1: "loser_starts",
+		2: "random_first",
+	}
+	FirstPlayer_value = map[string]int32{
+		"alternate":    0,
+		"loser_starts": 1,
+		"random_first": 2,
+	}
+)
+
+func (x FirstPlayer) Enum() *FirstPlayer {
+	p := new(FirstPlayer)
+	*p = x
+	return p
+}
+
+func (x FirstPlayer) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FirstPlayer) Descriptor() protoreflect.EnumDescriptor {
+	return file_pb_moves_proto_enumTypes[6].Descriptor()
+}
+
+func (FirstPlayer) Type() protoreflect.EnumType {
+	return &file_pb_moves_proto_enumTypes[6]
+}
+
+func (x FirstPlayer) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *FirstPlayer) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = FirstPlayer(num)
+	return nil
+}
+
+// Deprecated: Use FirstPlayer.Descriptor instead.
+func (FirstPlayer) EnumDescriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{6}
+}
+
+type Opponent int32
+
+const (
+	Opponent_human Opponent = 0
+	Opponent_bot   Opponent = 1 // the server plays yellow
+)
+
+// Enum value maps for Opponent.
+var (
+	Opponent_name = map[int32]string{
+		0: "human",
+		1: "bot",
+	}
+	Opponent_value = map[string]int32{
+		"human": 0,
+		"bot":   1,
+	}
+)
+
+func (x Opponent) Enum() *Opponent {
+	p := new(Opponent)
+	*p = x
+	return p
+}
+
+func (x Opponent) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Opponent) Descriptor() protoreflect.EnumDescriptor {
+	return file_pb_moves_proto_enumTypes[7].Descriptor()
+}
+
+func (Opponent) Type() protoreflect.EnumType {
+	return &file_pb_moves_proto_enumTypes[7]
+}
+
+func (x Opponent) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *Opponent) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = Opponent(num)
+	return nil
+}
+
+// Deprecated: Use Opponent.Descriptor instead.
+func (Opponent) EnumDescriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{7}
+}
+
+type Outcome int32
+
+const (
+	Outcome_unsolved    Outcome = 0 // not proven within the budget
+	Outcome_forced_win  Outcome = 1
+	Outcome_forced_loss Outcome = 2
+	Outcome_forced_draw Outcome = 3
+)
+
+// Enum value maps for Outcome.
+var (
+	Outcome_name = map[int32]string{
+		0: "unsolved",
+		1: "forced_win",
+		2: "forced_loss",
+		3: "forced_draw",
+	}
+	Outcome_value = map[string]int32{
+		"unsolved":    0,
+		"forced_win":  1,
+		"forced_loss": 2,
+		"forced_draw": 3,
+	}
+)
+
+func (x Outcome) Enum() *Outcome {
+	p := new(Outcome)
+	*p = x
+	return p
+}
+
+func (x Outcome) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Outcome) Descriptor() protoreflect.EnumDescriptor {
+	return file_pb_moves_proto_enumTypes[8].Descriptor()
+}
+
+func (Outcome) Type() protoreflect.EnumType {
+	return &file_pb_moves_proto_enumTypes[8]
+}
+
+func (x Outcome) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *Outcome) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = Outcome(num)
+	return nil
+}
+
+// Deprecated: Use Outcome.Descriptor instead.
+func (Outcome) EnumDescriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{8}
+}
+
+type LobbyEventKind int32
+
+const (
+	LobbyEventKind_added   LobbyEventKind = 0
+	LobbyEventKind_updated LobbyEventKind = 1
+	LobbyEventKind_removed LobbyEventKind = 2 // the game was deleted or no longer matches the filter
+)
+
+// Enum value maps for LobbyEventKind.
+var (
+	LobbyEventKind_name = map[int32]string{
+		0: "added",
+		1: "updated",
+		2: "removed",
+	}
+	LobbyEventKind_value = map[string]int32{
+		"added":   0,
+		"updated": 1,
+		"removed": 2,
+	}
+)
+
+func (x LobbyEventKind) Enum() *LobbyEventKind {
+	p := new(LobbyEventKind)
+	*p = x
+	return p
+}
+
+func (x LobbyEventKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LobbyEventKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_pb_moves_proto_enumTypes[9].Descriptor()
+}
+
+func (LobbyEventKind) Type() protoreflect.EnumType {
+	return &file_pb_moves_proto_enumTypes[9]
+}
+
+func (x LobbyEventKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *LobbyEventKind) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = LobbyEventKind(num)
+	return nil
+}
+
+// Deprecated: Use LobbyEventKind.Descriptor instead.
+func (LobbyEventKind) EnumDescriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{9}
+}
+
 type Input struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	GameId      *int32                 `protobuf:"varint,1,req,name=game_id,json=gameId" json:"game_id,omitempty"`
+	InputTeam   *Team                  `protobuf:"varint,3,req,name=input_team,json=inputTeam,enum=Team" json:"input_team,omitempty"`
+	ResumeToken *string                `protobuf:"bytes,4,opt,name=resume_token,json=resumeToken" json:"resume_token,omitempty"` // of the seat, checked on the first message of a stream
+	// Types that are valid to be assigned to Action:
+	//
+	//	*Input_Column
+	//	*Input_Resign
+	//	*Input_OfferDraw
+	//	*Input_Accept
+	//	*Input_Decline
+	//	*Input_RequestTakeback
+	//	*Input_Rematch
+	//	*Input_Chat
+	//	*Input_MuteChat
+	Action        isInput_Action `protobuf_oneof:"action"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Input) Reset() {
+	*x = Input{}
+	mi := &file_pb_moves_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Input) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Input) ProtoMessage() {}
+
+func (x *Input) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Input.ProtoReflect.Descriptor instead.
+func (*Input) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Input) GetGameId() int32 {
+	if x != nil && x.GameId != nil {
+		return *x.GameId
+	}
+	return 0
+}
+
+func (x *Input) GetInputTeam() Team {
+	if x != nil && x.InputTeam != nil {
+		return *x.InputTeam
+	}
+	return Team_empty
+}
+
+func (x *Input) GetResumeToken() string {
+	if x != nil && x.ResumeToken != nil {
+		return *x.ResumeToken
+	}
+	return ""
+}
+
+func (x *Input) GetAction() isInput_Action {
+	if x != nil {
+		return x.Action
+	}
+	return nil
+}
+
+func (x *Input) GetColumn() int32 {
+	if x != nil {
+		if x, ok := x.Action.(*Input_Column); ok {
+			return x.Column
+		}
+	}
+	return 0
+}
+
+func (x *Input) GetResign() *Empty {
+	if x != nil {
+		if x, ok := x.Action.(*Input_Resign); ok {
+			return x.Resign
+		}
+	}
+	return nil
+}
+
+func (x *Input) GetOfferDraw() *Empty {
+	if x != nil {
+		if x, ok := x.Action.(*Input_OfferDraw); ok {
+			return x.OfferDraw
+		}
+	}
+	return nil
+}
+
+func (x *Input) GetAccept() *Empty {
+	if x != nil {
+		if x, ok := x.Action.(*Input_Accept); ok {
+			return x.Accept
+		}
+	}
+	return nil
+}
+
+func (x *Input) GetDecline() *Empty {
+	if x != nil {
+		if x, ok := x.Action.(*Input_Decline); ok {
+			return x.Decline
+		}
+	}
+	return nil
+}
+
+func (x *Input) GetRequestTakeback() *Empty {
+	if x != nil {
+		if x, ok := x.Action.(*Input_RequestTakeback); ok {
+			return x.RequestTakeback
+		}
+	}
+	return nil
+}
+
+func (x *Input) GetRematch() *Empty {
+	if x != nil {
+		if x, ok := x.Action.(*Input_Rematch); ok {
+			return x.Rematch
+		}
+	}
+	return nil
+}
+
+func (x *Input) GetChat() *ChatMessage {
+	if x != nil {
+		if x, ok := x.Action.(*Input_Chat); ok {
+			return x.Chat
+		}
+	}
+	return nil
+}
+
+func (x *Input) GetMuteChat() bool {
+	if x != nil {
+		if x, ok := x.Action.(*Input_MuteChat); ok {
+			return x.MuteChat
+		}
+	}
+	return false
+}
+
+type isInput_Action interface {
+	isInput_Action()
+}
+
+type Input_Column struct {
+	Column int32 `protobuf:"varint,2,opt,name=column,oneof"` // 1 based column to play in, -1 on the first message registering the stream
+}
+
+type Input_Resign struct {
+	Resign *Empty `protobuf:"bytes,5,opt,name=resign,oneof"`
+}
+
+type Input_OfferDraw struct {
+	OfferDraw *Empty `protobuf:"bytes,6,opt,name=offer_draw,json=offerDraw,oneof"` // accepts the draw offer of the opponent if there is one
+}
+
+type Input_Accept struct {
+	Accept *Empty `protobuf:"bytes,7,opt,name=accept,oneof"` // the pending offer of the opponent
+}
+
+type Input_Decline struct {
+	Decline *Empty `protobuf:"bytes,8,opt,name=decline,oneof"`
+}
+
+type Input_RequestTakeback struct {
+	RequestTakeback *Empty `protobuf:"bytes,9,opt,name=request_takeback,json=requestTakeback,oneof"` // undoes the last move of the player once the opponent accepts
+}
+
+type Input_Rematch struct {
+	Rematch *Empty `protobuf:"bytes,10,opt,name=rematch,oneof"` // starts the next board once the opponent accepts, or accepts their rematch offer
+}
+
+type Input_Chat struct {
+	Chat *ChatMessage `protobuf:"bytes,11,opt,name=chat,oneof"` // sent to the opponent, unless they muted the chat, and to the spectators
+}
+
+type Input_MuteChat struct {
+	MuteChat bool `protobuf:"varint,12,opt,name=mute_chat,json=muteChat,oneof"` // stops or resumes sending the chat of the opponent to this seat
+}
+
+func (*Input_Column) isInput_Action() {}
+
+func (*Input_Resign) isInput_Action() {}
+
+func (*Input_OfferDraw) isInput_Action() {}
+
+func (*Input_Accept) isInput_Action() {}
+
+func (*Input_Decline) isInput_Action() {}
+
+func (*Input_RequestTakeback) isInput_Action() {}
+
+func (*Input_Rematch) isInput_Action() {}
+
+func (*Input_Chat) isInput_Action() {}
+
+func (*Input_MuteChat) isInput_Action() {}
+
+// Offer waiting for an answer, withdrawn when a move is played.
+type Offer struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          *OfferKind             `protobuf:"varint,1,req,name=kind,enum=OfferKind" json:"kind,omitempty"`
+	From          *Team                  `protobuf:"varint,2,req,name=from,enum=Team" json:"from,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Offer) Reset() {
+	*x = Offer{}
+	mi := &file_pb_moves_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Offer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Offer) ProtoMessage() {}
+
+func (x *Offer) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Offer.ProtoReflect.Descriptor instead.
+func (*Offer) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Offer) GetKind() OfferKind {
+	if x != nil && x.Kind != nil {
+		return *x.Kind
+	}
+	return OfferKind_draw_offer
+}
+
+func (x *Offer) GetFrom() Team {
+	if x != nil && x.From != nil {
+		return *x.From
+	}
+	return Team_empty
+}
+
+type State struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Field         *Field                 `protobuf:"bytes,1,req,name=field" json:"field,omitempty"`
+	Turn          *Team                  `protobuf:"varint,2,req,name=turn,enum=Team" json:"turn,omitempty"`
+	Variant       *Variant               `protobuf:"bytes,3,opt,name=variant" json:"variant,omitempty"`
+	Result        *Result                `protobuf:"varint,4,opt,name=result,enum=Result" json:"result,omitempty"`
+	WinningLine   []*Cell                `protobuf:"bytes,5,rep,name=winning_line,json=winningLine" json:"winning_line,omitempty"`
+	Rejection     *Rejection             `protobuf:"bytes,6,opt,name=rejection" json:"rejection,omitempty"` // only sent to the player whose input was refused
+	Score         *Score                 `protobuf:"bytes,7,opt,name=score" json:"score,omitempty"`
+	Players       *Players               `protobuf:"bytes,8,opt,name=players" json:"players,omitempty"`
+	Clocks        *Clocks                `protobuf:"bytes,9,opt,name=clocks" json:"clocks,omitempty"` // unset for untimed games
+	EndReason     *EndReason             `protobuf:"varint,10,opt,name=end_reason,json=endReason,enum=EndReason" json:"end_reason,omitempty"`
+	Offer         *Offer                 `protobuf:"bytes,11,opt,name=offer" json:"offer,omitempty"`
+	NextFirst     *Team                  `protobuf:"varint,12,opt,name=next_first,json=nextFirst,enum=Team" json:"next_first,omitempty"`  // who moves first on the next board, set once the board is over
+	Chat          *ChatLine              `protobuf:"bytes,13,opt,name=chat" json:"chat,omitempty"`                                        // a chat message sent along the current state
+	RatingChanges *RatingChanges         `protobuf:"bytes,14,opt,name=rating_changes,json=ratingChanges" json:"rating_changes,omitempty"` // set once a rated board is over
+	// set once the server is shutting down: the board in play may be finished until then, the game
+	// being saved for the players to resume it after the restart
+	ShutdownAt    *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=shutdown_at,json=shutdownAt" json:"shutdown_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *State) Reset() {
+	*x = State{}
+	mi := &file_pb_moves_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *State) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*State) ProtoMessage() {}
+
+func (x *State) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use State.ProtoReflect.Descriptor instead.
+func (*State) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *State) GetField() *Field {
+	if x != nil {
+		return x.Field
+	}
+	return nil
+}
+
+func (x *State) GetTurn() Team {
+	if x != nil && x.Turn != nil {
+		return *x.Turn
+	}
+	return Team_empty
+}
+
+func (x *State) GetVariant() *Variant {
+	if x != nil {
+		return x.Variant
+	}
+	return nil
+}
+
+func (x *State) GetResult() Result {
+	if x != nil && x.Result != nil {
+		return *x.Result
+	}
+	return Result_in_progress
+}
+
+func (x *State) GetWinningLine() []*Cell {
+	if x != nil {
+		return x.WinningLine
+	}
+	return nil
+}
+
+func (x *State) GetRejection() *Rejection {
+	if x != nil {
+		return x.Rejection
+	}
+	return nil
+}
+
+func (x *State) GetScore() *Score {
+	if x != nil {
+		return x.Score
+	}
+	return nil
+}
+
+func (x *State) GetPlayers() *Players {
+	if x != nil {
+		return x.Players
+	}
+	return nil
+}
+
+func (x *State) GetClocks() *Clocks {
+	if x != nil {
+		return x.Clocks
+	}
+	return nil
+}
+
+func (x *State) GetEndReason() EndReason {
+	if x != nil && x.EndReason != nil {
+		return *x.EndReason
+	}
+	return EndReason_not_over
+}
+
+func (x *State) GetOffer() *Offer {
+	if x != nil {
+		return x.Offer
+	}
+	return nil
+}
+
+func (x *State) GetNextFirst() Team {
+	if x != nil && x.NextFirst != nil {
+		return *x.NextFirst
+	}
+	return Team_empty
+}
+
+func (x *State) GetChat() *ChatLine {
+	if x != nil {
+		return x.Chat
+	}
+	return nil
+}
+
+func (x *State) GetRatingChanges() *RatingChanges {
+	if x != nil {
+		return x.RatingChanges
+	}
+	return nil
+}
+
+func (x *State) GetShutdownAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ShutdownAt
+	}
+	return nil
+}
+
+type RatingChanges struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Red           *RatingChange          `protobuf:"bytes,1,opt,name=red" json:"red,omitempty"`
+	Yellow        *RatingChange          `protobuf:"bytes,2,opt,name=yellow" json:"yellow,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RatingChanges) Reset() {
+	*x = RatingChanges{}
+	mi := &file_pb_moves_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RatingChanges) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RatingChanges) ProtoMessage() {}
+
+func (x *RatingChanges) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RatingChanges.ProtoReflect.Descriptor instead.
+func (*RatingChanges) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RatingChanges) GetRed() *RatingChange {
+	if x != nil {
+		return x.Red
+	}
+	return nil
+}
+
+func (x *RatingChanges) GetYellow() *RatingChange {
+	if x != nil {
+		return x.Yellow
+	}
+	return nil
+}
+
+type RatingChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Before        *float64               `protobuf:"fixed64,1,req,name=before" json:"before,omitempty"`
+	After         *float64               `protobuf:"fixed64,2,req,name=after" json:"after,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RatingChange) Reset() {
+	*x = RatingChange{}
+	mi := &file_pb_moves_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RatingChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RatingChange) ProtoMessage() {}
+
+func (x *RatingChange) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RatingChange.ProtoReflect.Descriptor instead.
+func (*RatingChange) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RatingChange) GetBefore() float64 {
+	if x != nil && x.Before != nil {
+		return *x.Before
+	}
+	return 0
+}
+
+func (x *RatingChange) GetAfter() float64 {
+	if x != nil && x.After != nil {
+		return *x.After
+	}
+	return 0
+}
+
+type ChatMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          *string                `protobuf:"bytes,1,opt,name=text" json:"text,omitempty"` // at most 200 characters, without control characters
+	Emote         *Emote                 `protobuf:"varint,2,opt,name=emote,enum=Emote" json:"emote,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatMessage) Reset() {
+	*x = ChatMessage{}
+	mi := &file_pb_moves_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatMessage) ProtoMessage() {}
+
+func (x *ChatMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatMessage.ProtoReflect.Descriptor instead.
+func (*ChatMessage) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ChatMessage) GetText() string {
+	if x != nil && x.Text != nil {
+		return *x.Text
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetEmote() Emote {
+	if x != nil && x.Emote != nil {
+		return *x.Emote
+	}
+	return Emote_no_emote
+}
+
+type ChatLine struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          *Team                  `protobuf:"varint,1,req,name=from,enum=Team" json:"from,omitempty"`
+	Name          *string                `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	Text          *string                `protobuf:"bytes,3,opt,name=text" json:"text,omitempty"`
+	Emote         *Emote                 `protobuf:"varint,4,opt,name=emote,enum=Emote" json:"emote,omitempty"`
+	SentAt        *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=sent_at,json=sentAt" json:"sent_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatLine) Reset() {
+	*x = ChatLine{}
+	mi := &file_pb_moves_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatLine) ProtoMessage() {}
+
+func (x *ChatLine) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatLine.ProtoReflect.Descriptor instead.
+func (*ChatLine) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ChatLine) GetFrom() Team {
+	if x != nil && x.From != nil {
+		return *x.From
+	}
+	return Team_empty
+}
+
+func (x *ChatLine) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *ChatLine) GetText() string {
+	if x != nil && x.Text != nil {
+		return *x.Text
+	}
+	return ""
+}
+
+func (x *ChatLine) GetEmote() Emote {
+	if x != nil && x.Emote != nil {
+		return *x.Emote
+	}
+	return Emote_no_emote
+}
+
+func (x *ChatLine) GetSentAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SentAt
+	}
+	return nil
+}
+
+// Time left to each player when the state was sent.
+type Clocks struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Red           *durationpb.Duration   `protobuf:"bytes,1,opt,name=red" json:"red,omitempty"`
+	Yellow        *durationpb.Duration   `protobuf:"bytes,2,opt,name=yellow" json:"yellow,omitempty"`
+	Running       *bool                  `protobuf:"varint,3,opt,name=running" json:"running,omitempty"` // the clock of the side to move is counting down
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Clocks) Reset() {
+	*x = Clocks{}
+	mi := &file_pb_moves_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Clocks) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Clocks) ProtoMessage() {}
+
+func (x *Clocks) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Clocks.ProtoReflect.Descriptor instead.
+func (*Clocks) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Clocks) GetRed() *durationpb.Duration {
+	if x != nil {
+		return x.Red
+	}
+	return nil
+}
+
+func (x *Clocks) GetYellow() *durationpb.Duration {
+	if x != nil {
+		return x.Yellow
+	}
+	return nil
+}
+
+func (x *Clocks) GetRunning() bool {
+	if x != nil && x.Running != nil {
+		return *x.Running
+	}
+	return false
+}
+
+// How long players have to move, running out of time losing the board.
+type TimeControl struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Kind:
+	//
+	//	*TimeControl_Fischer
+	//	*TimeControl_PerMove
+	//	*TimeControl_CorrespondenceDays
+	Kind          isTimeControl_Kind `protobuf_oneof:"kind"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimeControl) Reset() {
+	*x = TimeControl{}
+	mi := &file_pb_moves_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimeControl) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeControl) ProtoMessage() {}
+
+func (x *TimeControl) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeControl.ProtoReflect.Descriptor instead.
+func (*TimeControl) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *TimeControl) GetKind() isTimeControl_Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return nil
+}
+
+func (x *TimeControl) GetFischer() *Fischer {
+	if x != nil {
+		if x, ok := x.Kind.(*TimeControl_Fischer); ok {
+			return x.Fischer
+		}
+	}
+	return nil
+}
+
+func (x *TimeControl) GetPerMove() *durationpb.Duration {
+	if x != nil {
+		if x, ok := x.Kind.(*TimeControl_PerMove); ok {
+			return x.PerMove
+		}
+	}
+	return nil
+}
+
+func (x *TimeControl) GetCorrespondenceDays() int32 {
+	if x != nil {
+		if x, ok := x.Kind.(*TimeControl_CorrespondenceDays); ok {
+			return x.CorrespondenceDays
+		}
+	}
+	return 0
+}
+
+type isTimeControl_Kind interface {
+	isTimeControl_Kind()
+}
+
+type TimeControl_Fischer struct {
+	Fischer *Fischer `protobuf:"bytes,1,opt,name=fischer,oneof"`
+}
+
+type TimeControl_PerMove struct {
+	PerMove *durationpb.Duration `protobuf:"bytes,2,opt,name=per_move,json=perMove,oneof"` // the same time for every move, what is left being lost
+}
+
+type TimeControl_CorrespondenceDays struct {
+	CorrespondenceDays int32 `protobuf:"varint,3,opt,name=correspondence_days,json=correspondenceDays,oneof"` // days for every move
+}
+
+func (*TimeControl_Fischer) isTimeControl_Kind() {}
+
+func (*TimeControl_PerMove) isTimeControl_Kind() {}
+
+func (*TimeControl_CorrespondenceDays) isTimeControl_Kind() {}
+
+type Fischer struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Initial       *durationpb.Duration   `protobuf:"bytes,1,req,name=initial" json:"initial,omitempty"`
+	Increment     *durationpb.Duration   `protobuf:"bytes,2,opt,name=increment" json:"increment,omitempty"` // added after every move
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Fischer) Reset() {
+	*x = Fischer{}
+	mi := &file_pb_moves_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Fischer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Fischer) ProtoMessage() {}
+
+func (x *Fischer) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Fischer.ProtoReflect.Descriptor instead.
+func (*Fischer) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Fischer) GetInitial() *durationpb.Duration {
+	if x != nil {
+		return x.Initial
+	}
+	return nil
+}
+
+func (x *Fischer) GetIncrement() *durationpb.Duration {
+	if x != nil {
+		return x.Increment
+	}
+	return nil
+}
+
+type Players struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Red           *string                `protobuf:"bytes,1,opt,name=red" json:"red,omitempty"`
+	Yellow        *string                `protobuf:"bytes,2,opt,name=yellow" json:"yellow,omitempty"`
+	Spectators    *int32                 `protobuf:"varint,3,opt,name=spectators" json:"spectators,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Players) Reset() {
+	*x = Players{}
+	mi := &file_pb_moves_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Players) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Players) ProtoMessage() {}
+
+func (x *Players) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Players.ProtoReflect.Descriptor instead.
+func (*Players) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Players) GetRed() string {
+	if x != nil && x.Red != nil {
+		return *x.Red
+	}
+	return ""
+}
+
+func (x *Players) GetYellow() string {
+	if x != nil && x.Yellow != nil {
+		return *x.Yellow
+	}
+	return ""
+}
+
+func (x *Players) GetSpectators() int32 {
+	if x != nil && x.Spectators != nil {
+		return *x.Spectators
+	}
+	return 0
+}
+
+// Running score of the series of games played on the same game id.
+type Score struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RedWins       *int32                 `protobuf:"varint,1,opt,name=red_wins,json=redWins" json:"red_wins,omitempty"`
+	YellowWins    *int32                 `protobuf:"varint,2,opt,name=yellow_wins,json=yellowWins" json:"yellow_wins,omitempty"`
+	Draws         *int32                 `protobuf:"varint,3,opt,name=draws" json:"draws,omitempty"`
+	GamesPlayed   *int32                 `protobuf:"varint,4,opt,name=games_played,json=gamesPlayed" json:"games_played,omitempty"`
+	FirstTo       *int32                 `protobuf:"varint,5,opt,name=first_to,json=firstTo" json:"first_to,omitempty"`                       // 0 when the series has no end
+	MatchWinner   *Team                  `protobuf:"varint,6,opt,name=match_winner,json=matchWinner,enum=Team" json:"match_winner,omitempty"` // set once a player reached first_to wins
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Score) Reset() {
+	*x = Score{}
+	mi := &file_pb_moves_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Score) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Score) ProtoMessage() {}
+
+func (x *Score) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Score.ProtoReflect.Descriptor instead.
+func (*Score) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Score) GetRedWins() int32 {
+	if x != nil && x.RedWins != nil {
+		return *x.RedWins
+	}
+	return 0
+}
+
+func (x *Score) GetYellowWins() int32 {
+	if x != nil && x.YellowWins != nil {
+		return *x.YellowWins
+	}
+	return 0
+}
+
+func (x *Score) GetDraws() int32 {
+	if x != nil && x.Draws != nil {
+		return *x.Draws
+	}
+	return 0
+}
+
+func (x *Score) GetGamesPlayed() int32 {
+	if x != nil && x.GamesPlayed != nil {
+		return *x.GamesPlayed
+	}
+	return 0
+}
+
+func (x *Score) GetFirstTo() int32 {
+	if x != nil && x.FirstTo != nil {
+		return *x.FirstTo
+	}
+	return 0
+}
+
+func (x *Score) GetMatchWinner() Team {
+	if x != nil && x.MatchWinner != nil {
+		return *x.MatchWinner
+	}
+	return Team_empty
+}
+
+type Rejection struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reason        *RejectionReason       `protobuf:"varint,1,req,name=reason,enum=RejectionReason" json:"reason,omitempty"`
+	Message       *string                `protobuf:"bytes,2,opt,name=message" json:"message,omitempty"`
+	Column        *int32                 `protobuf:"varint,3,opt,name=column" json:"column,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Rejection) Reset() {
+	*x = Rejection{}
+	mi := &file_pb_moves_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Rejection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rejection) ProtoMessage() {}
+
+func (x *Rejection) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rejection.ProtoReflect.Descriptor instead.
+func (*Rejection) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Rejection) GetReason() RejectionReason {
+	if x != nil && x.Reason != nil {
+		return *x.Reason
+	}
+	return RejectionReason_not_rejected
+}
+
+func (x *Rejection) GetMessage() string {
+	if x != nil && x.Message != nil {
+		return *x.Message
+	}
+	return ""
+}
+
+func (x *Rejection) GetColumn() int32 {
+	if x != nil && x.Column != nil {
+		return *x.Column
+	}
+	return 0
+}
+
+// Row 0 is the bottom of the board, column 0 the leftmost one.
+type Cell struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Row           *int32                 `protobuf:"varint,1,req,name=row" json:"row,omitempty"`
+	Column        *int32                 `protobuf:"varint,2,req,name=column" json:"column,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Cell) Reset() {
+	*x = Cell{}
+	mi := &file_pb_moves_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Cell) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Cell) ProtoMessage() {}
+
+func (x *Cell) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Cell.ProtoReflect.Descriptor instead.
+func (*Cell) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Cell) GetRow() int32 {
+	if x != nil && x.Row != nil {
+		return *x.Row
+	}
+	return 0
+}
+
+func (x *Cell) GetColumn() int32 {
+	if x != nil && x.Column != nil {
+		return *x.Column
+	}
+	return 0
+}
+
+// Board dimensions and the number of aligned discs needed to win, unset fields use the server defaults.
+type Variant struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rows          *int32                 `protobuf:"varint,1,opt,name=rows" json:"rows,omitempty"`
+	Columns       *int32                 `protobuf:"varint,2,opt,name=columns" json:"columns,omitempty"`
+	Connect       *int32                 `protobuf:"varint,3,opt,name=connect" json:"connect,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Variant) Reset() {
+	*x = Variant{}
+	mi := &file_pb_moves_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Variant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Variant) ProtoMessage() {}
+
+func (x *Variant) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Variant.ProtoReflect.Descriptor instead.
+func (*Variant) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Variant) GetRows() int32 {
+	if x != nil && x.Rows != nil {
+		return *x.Rows
+	}
+	return 0
+}
+
+func (x *Variant) GetColumns() int32 {
+	if x != nil && x.Columns != nil {
+		return *x.Columns
+	}
+	return 0
+}
+
+func (x *Variant) GetConnect() int32 {
+	if x != nil && x.Connect != nil {
+		return *x.Connect
+	}
+	return 0
+}
+
+type NewGameRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Variant     *Variant               `protobuf:"bytes,1,opt,name=variant" json:"variant,omitempty"`
+	FirstTo     *int32                 `protobuf:"varint,2,opt,name=first_to,json=firstTo" json:"first_to,omitempty"` // ends the match once a player wins that many games, 0 plays forever
+	Opponent    *Opponent              `protobuf:"varint,3,opt,name=opponent,enum=Opponent" json:"opponent,omitempty"`
+	BotLevel    *int32                 `protobuf:"varint,4,opt,name=bot_level,json=botLevel" json:"bot_level,omitempty"` // from 1 (easy) to 5 (hard), 3 when unset
+	PlayerName  *string                `protobuf:"bytes,5,opt,name=player_name,json=playerName" json:"player_name,omitempty"`
+	Private     *bool                  `protobuf:"varint,6,opt,name=private" json:"private,omitempty"`                                             // not listed in the lobby, joined by id only
+	TimeControl *TimeControl           `protobuf:"bytes,7,opt,name=time_control,json=timeControl" json:"time_control,omitempty"`                   // untimed when unset
+	FirstPlayer *FirstPlayer           `protobuf:"varint,8,opt,name=first_player,json=firstPlayer,enum=FirstPlayer" json:"first_player,omitempty"` // who moves first on the boards after the first one, red opening it
+	// Changes the ratings of the players after every board. Both need an account, and the server
+	// may refuse to rate bot games.
+	Rated         *bool `protobuf:"varint,9,opt,name=rated" json:"rated,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NewGameRequest) Reset() {
+	*x = NewGameRequest{}
+	mi := &file_pb_moves_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NewGameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NewGameRequest) ProtoMessage() {}
+
+func (x *NewGameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NewGameRequest.ProtoReflect.Descriptor instead.
+func (*NewGameRequest) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *NewGameRequest) GetVariant() *Variant {
+	if x != nil {
+		return x.Variant
+	}
+	return nil
+}
+
+func (x *NewGameRequest) GetFirstTo() int32 {
+	if x != nil && x.FirstTo != nil {
+		return *x.FirstTo
+	}
+	return 0
+}
+
+func (x *NewGameRequest) GetOpponent() Opponent {
+	if x != nil && x.Opponent != nil {
+		return *x.Opponent
+	}
+	return Opponent_human
+}
+
+func (x *NewGameRequest) GetBotLevel() int32 {
+	if x != nil && x.BotLevel != nil {
+		return *x.BotLevel
+	}
+	return 0
+}
+
+func (x *NewGameRequest) GetPlayerName() string {
+	if x != nil && x.PlayerName != nil {
+		return *x.PlayerName
+	}
+	return ""
+}
+
+func (x *NewGameRequest) GetPrivate() bool {
+	if x != nil && x.Private != nil {
+		return *x.Private
+	}
+	return false
+}
+
+func (x *NewGameRequest) GetTimeControl() *TimeControl {
+	if x != nil {
+		return x.TimeControl
+	}
+	return nil
+}
+
+func (x *NewGameRequest) GetFirstPlayer() FirstPlayer {
+	if x != nil && x.FirstPlayer != nil {
+		return *x.FirstPlayer
+	}
+	return FirstPlayer_alternate
+}
+
+func (x *NewGameRequest) GetRated() bool {
+	if x != nil && x.Rated != nil {
+		return *x.Rated
+	}
+	return false
+}
+
+type Field struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rows          []*Row                 `protobuf:"bytes,1,rep,name=rows" json:"rows,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Field) Reset() {
+	*x = Field{}
+	mi := &file_pb_moves_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Field) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Field) ProtoMessage() {}
+
+func (x *Field) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Field.ProtoReflect.Descriptor instead.
+func (*Field) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Field) GetRows() []*Row {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+type Row struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []Team                 `protobuf:"varint,1,rep,name=values,enum=Team" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Row) Reset() {
+	*x = Row{}
+	mi := &file_pb_moves_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Row) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Row) ProtoMessage() {}
+
+func (x *Row) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Row.ProtoReflect.Descriptor instead.
+func (*Row) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Row) GetValues() []Team {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type Empty struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Empty) Reset() {
+	*x = Empty{}
+	mi := &file_pb_moves_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Empty) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Empty) ProtoMessage() {}
+
+func (x *Empty) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Empty.ProtoReflect.Descriptor instead.
+func (*Empty) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{18}
+}
+
+type GameIDAndTeam struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Id      *int32                 `protobuf:"varint,1,req,name=id" json:"id,omitempty"`
+	Team    *Team                  `protobuf:"varint,2,req,name=team,enum=Team" json:"team,omitempty"`
+	Variant *Variant               `protobuf:"bytes,3,opt,name=variant" json:"variant,omitempty"`
+	// Secret proving the seat belongs to the player, needed to play on it, resume it after a
+	// disconnection and leave it.
+	ResumeToken   *string `protobuf:"bytes,4,opt,name=resume_token,json=resumeToken" json:"resume_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GameIDAndTeam) Reset() {
+	*x = GameIDAndTeam{}
+	mi := &file_pb_moves_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameIDAndTeam) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameIDAndTeam) ProtoMessage() {}
+
+func (x *GameIDAndTeam) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameIDAndTeam.ProtoReflect.Descriptor instead.
+func (*GameIDAndTeam) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GameIDAndTeam) GetId() int32 {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return 0
+}
+
+func (x *GameIDAndTeam) GetTeam() Team {
+	if x != nil && x.Team != nil {
+		return *x.Team
+	}
+	return Team_empty
+}
+
+func (x *GameIDAndTeam) GetVariant() *Variant {
+	if x != nil {
+		return x.Variant
+	}
+	return nil
+}
+
+func (x *GameIDAndTeam) GetResumeToken() string {
+	if x != nil && x.ResumeToken != nil {
+		return *x.ResumeToken
+	}
+	return ""
+}
+
+type GameID struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            *int32                 `protobuf:"varint,1,req,name=id" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GameID) Reset() {
+	*x = GameID{}
+	mi := &file_pb_moves_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameID) ProtoMessage() {}
+
+func (x *GameID) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameID.ProtoReflect.Descriptor instead.
+func (*GameID) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GameID) GetId() int32 {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return 0
+}
+
+type JoinGameRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            *int32                 `protobuf:"varint,1,req,name=id" json:"id,omitempty"`
+	PlayerName    *string                `protobuf:"bytes,2,opt,name=player_name,json=playerName" json:"player_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JoinGameRequest) Reset() {
+	*x = JoinGameRequest{}
+	mi := &file_pb_moves_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JoinGameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinGameRequest) ProtoMessage() {}
+
+func (x *JoinGameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinGameRequest.ProtoReflect.Descriptor instead.
+func (*JoinGameRequest) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *JoinGameRequest) GetId() int32 {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return 0
+}
+
+func (x *JoinGameRequest) GetPlayerName() string {
+	if x != nil && x.PlayerName != nil {
+		return *x.PlayerName
+	}
+	return ""
+}
+
+// Analyzes the position of a live game, or the given field when game_id is unset.
+type AnalyzeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GameId        *int32                 `protobuf:"varint,1,opt,name=game_id,json=gameId" json:"game_id,omitempty"`
+	Field         *Field                 `protobuf:"bytes,2,opt,name=field" json:"field,omitempty"`
+	Connect       *int32                 `protobuf:"varint,3,opt,name=connect" json:"connect,omitempty"`                              // for field, 4 when unset
+	MaxDepth      *int32                 `protobuf:"varint,4,opt,name=max_depth,json=maxDepth" json:"max_depth,omitempty"`            // plies, 0 searches until solved or out of time
+	TimeLimitMs   *int32                 `protobuf:"varint,5,opt,name=time_limit_ms,json=timeLimitMs" json:"time_limit_ms,omitempty"` // capped by the server
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalyzeRequest) Reset() {
+	*x = AnalyzeRequest{}
+	mi := &file_pb_moves_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalyzeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyzeRequest) ProtoMessage() {}
+
+func (x *AnalyzeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyzeRequest.ProtoReflect.Descriptor instead.
+func (*AnalyzeRequest) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *AnalyzeRequest) GetGameId() int32 {
+	if x != nil && x.GameId != nil {
+		return *x.GameId
+	}
+	return 0
+}
+
+func (x *AnalyzeRequest) GetField() *Field {
+	if x != nil {
+		return x.Field
+	}
+	return nil
+}
+
+func (x *AnalyzeRequest) GetConnect() int32 {
+	if x != nil && x.Connect != nil {
+		return *x.Connect
+	}
+	return 0
+}
+
+func (x *AnalyzeRequest) GetMaxDepth() int32 {
+	if x != nil && x.MaxDepth != nil {
+		return *x.MaxDepth
+	}
+	return 0
+}
+
+func (x *AnalyzeRequest) GetTimeLimitMs() int32 {
+	if x != nil && x.TimeLimitMs != nil {
+		return *x.TimeLimitMs
+	}
+	return 0
+}
+
+// Value of playing in a column for the side to move.
+type ColumnAnalysis struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Column        *int32                 `protobuf:"varint,1,req,name=column" json:"column,omitempty"` // 1 based like Input.column
+	Legal         *bool                  `protobuf:"varint,2,opt,name=legal" json:"legal,omitempty"`
+	Outcome       *Outcome               `protobuf:"varint,3,opt,name=outcome,enum=Outcome" json:"outcome,omitempty"`
+	Plies         *int32                 `protobuf:"varint,4,opt,name=plies" json:"plies,omitempty"` // until the end of the game with perfect play, for wins and losses
+	Score         *int32                 `protobuf:"varint,5,opt,name=score" json:"score,omitempty"` // positive for wins, negative for losses, larger magnitudes ending sooner
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ColumnAnalysis) Reset() {
+	*x = ColumnAnalysis{}
+	mi := &file_pb_moves_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ColumnAnalysis) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ColumnAnalysis) ProtoMessage() {}
+
+func (x *ColumnAnalysis) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ColumnAnalysis.ProtoReflect.Descriptor instead.
+func (*ColumnAnalysis) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ColumnAnalysis) GetColumn() int32 {
+	if x != nil && x.Column != nil {
+		return *x.Column
+	}
+	return 0
+}
+
+func (x *ColumnAnalysis) GetLegal() bool {
+	if x != nil && x.Legal != nil {
+		return *x.Legal
+	}
+	return false
+}
+
+func (x *ColumnAnalysis) GetOutcome() Outcome {
+	if x != nil && x.Outcome != nil {
+		return *x.Outcome
+	}
+	return Outcome_unsolved
+}
+
+func (x *ColumnAnalysis) GetPlies() int32 {
+	if x != nil && x.Plies != nil {
+		return *x.Plies
+	}
+	return 0
+}
+
+func (x *ColumnAnalysis) GetScore() int32 {
+	if x != nil && x.Score != nil {
+		return *x.Score
+	}
+	return 0
+}
+
+type Analysis struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Columns       []*ColumnAnalysis      `protobuf:"bytes,1,rep,name=columns" json:"columns,omitempty"`
+	Turn          *Team                  `protobuf:"varint,2,opt,name=turn,enum=Team" json:"turn,omitempty"`
+	Depth         *int32                 `protobuf:"varint,3,opt,name=depth" json:"depth,omitempty"`       // plies fully searched
+	Complete      *bool                  `protobuf:"varint,4,opt,name=complete" json:"complete,omitempty"` // every legal column was proven
+	Nodes         *int64                 `protobuf:"varint,5,opt,name=nodes" json:"nodes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Analysis) Reset() {
+	*x = Analysis{}
+	mi := &file_pb_moves_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Analysis) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Analysis) ProtoMessage() {}
+
+func (x *Analysis) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Analysis.ProtoReflect.Descriptor instead.
+func (*Analysis) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *Analysis) GetColumns() []*ColumnAnalysis {
+	if x != nil {
+		return x.Columns
+	}
+	return nil
+}
+
+func (x *Analysis) GetTurn() Team {
+	if x != nil && x.Turn != nil {
+		return *x.Turn
+	}
+	return Team_empty
+}
+
+func (x *Analysis) GetDepth() int32 {
+	if x != nil && x.Depth != nil {
+		return *x.Depth
+	}
+	return 0
+}
+
+func (x *Analysis) GetComplete() bool {
+	if x != nil && x.Complete != nil {
+		return *x.Complete
+	}
+	return false
+}
+
+func (x *Analysis) GetNodes() int64 {
+	if x != nil && x.Nodes != nil {
+		return *x.Nodes
+	}
+	return 0
+}
+
+type GameSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            *int32                 `protobuf:"varint,1,req,name=id" json:"id,omitempty"`
+	HostName      *string                `protobuf:"bytes,2,opt,name=host_name,json=hostName" json:"host_name,omitempty"`
+	Variant       *Variant               `protobuf:"bytes,3,opt,name=variant" json:"variant,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
+	SeatsFree     *int32                 `protobuf:"varint,5,opt,name=seats_free,json=seatsFree" json:"seats_free,omitempty"`
+	FirstTo       *int32                 `protobuf:"varint,6,opt,name=first_to,json=firstTo" json:"first_to,omitempty"`
+	Spectators    *int32                 `protobuf:"varint,7,opt,name=spectators" json:"spectators,omitempty"`
+	TimeControl   *TimeControl           `protobuf:"bytes,8,opt,name=time_control,json=timeControl" json:"time_control,omitempty"`
+	Rated         *bool                  `protobuf:"varint,9,opt,name=rated" json:"rated,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GameSummary) Reset() {
+	*x = GameSummary{}
+	mi := &file_pb_moves_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameSummary) ProtoMessage() {}
+
+func (x *GameSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameSummary.ProtoReflect.Descriptor instead.
+func (*GameSummary) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GameSummary) GetId() int32 {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return 0
+}
+
+func (x *GameSummary) GetHostName() string {
+	if x != nil && x.HostName != nil {
+		return *x.HostName
+	}
+	return ""
+}
+
+func (x *GameSummary) GetVariant() *Variant {
+	if x != nil {
+		return x.Variant
+	}
+	return nil
+}
+
+func (x *GameSummary) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *GameSummary) GetSeatsFree() int32 {
+	if x != nil && x.SeatsFree != nil {
+		return *x.SeatsFree
+	}
+	return 0
+}
+
+func (x *GameSummary) GetFirstTo() int32 {
+	if x != nil && x.FirstTo != nil {
+		return *x.FirstTo
+	}
+	return 0
+}
+
+func (x *GameSummary) GetSpectators() int32 {
+	if x != nil && x.Spectators != nil {
+		return *x.Spectators
+	}
+	return 0
+}
+
+func (x *GameSummary) GetTimeControl() *TimeControl {
+	if x != nil {
+		return x.TimeControl
+	}
+	return nil
+}
+
+func (x *GameSummary) GetRated() bool {
+	if x != nil && x.Rated != nil {
+		return *x.Rated
+	}
+	return false
+}
+
+type ListGamesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Variant       *Variant               `protobuf:"bytes,1,opt,name=variant" json:"variant,omitempty"`                             // only games matching the non zero fields
+	IncludeFull   *bool                  `protobuf:"varint,2,opt,name=include_full,json=includeFull" json:"include_full,omitempty"` // also list games without a free seat
+	PageSize      *int32                 `protobuf:"varint,3,opt,name=page_size,json=pageSize" json:"page_size,omitempty"`          // 50 when unset, at most 200, ignored by WatchLobby
+	PageToken     *string                `protobuf:"bytes,4,opt,name=page_token,json=pageToken" json:"page_token,omitempty"`        // next_page_token of the previous page
+	TimeControl   *TimeControl           `protobuf:"bytes,5,opt,name=time_control,json=timeControl" json:"time_control,omitempty"`  // only games with that clock, an empty one for untimed games
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListGamesRequest) Reset() {
+	*x = ListGamesRequest{}
+	mi := &file_pb_moves_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGamesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGamesRequest) ProtoMessage() {}
+
+func (x *ListGamesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGamesRequest.ProtoReflect.Descriptor instead.
+func (*ListGamesRequest) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ListGamesRequest) GetVariant() *Variant {
+	if x != nil {
+		return x.Variant
+	}
+	return nil
+}
+
+func (x *ListGamesRequest) GetIncludeFull() bool {
+	if x != nil && x.IncludeFull != nil {
+		return *x.IncludeFull
+	}
+	return false
+}
+
+func (x *ListGamesRequest) GetPageSize() int32 {
+	if x != nil && x.PageSize != nil {
+		return *x.PageSize
+	}
+	return 0
+}
+
+func (x *ListGamesRequest) GetPageToken() string {
+	if x != nil && x.PageToken != nil {
+		return *x.PageToken
+	}
+	return ""
+}
+
+func (x *ListGamesRequest) GetTimeControl() *TimeControl {
+	if x != nil {
+		return x.TimeControl
+	}
+	return nil
+}
+
+type ListGamesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Games         []*GameSummary         `protobuf:"bytes,1,rep,name=games" json:"games,omitempty"`                                        // oldest first
+	NextPageToken *string                `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken" json:"next_page_token,omitempty"` // empty on the last page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListGamesResponse) Reset() {
+	*x = ListGamesResponse{}
+	mi := &file_pb_moves_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGamesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGamesResponse) ProtoMessage() {}
+
+func (x *ListGamesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGamesResponse.ProtoReflect.Descriptor instead.
+func (*ListGamesResponse) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ListGamesResponse) GetGames() []*GameSummary {
+	if x != nil {
+		return x.Games
+	}
+	return nil
+}
+
+func (x *ListGamesResponse) GetNextPageToken() string {
+	if x != nil && x.NextPageToken != nil {
+		return *x.NextPageToken
+	}
+	return ""
+}
+
+type LobbyEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          *LobbyEventKind        `protobuf:"varint,1,req,name=kind,enum=LobbyEventKind" json:"kind,omitempty"`
+	Game          *GameSummary           `protobuf:"bytes,2,req,name=game" json:"game,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LobbyEvent) Reset() {
+	*x = LobbyEvent{}
+	mi := &file_pb_moves_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LobbyEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LobbyEvent) ProtoMessage() {}
+
+func (x *LobbyEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LobbyEvent.ProtoReflect.Descriptor instead.
+func (*LobbyEvent) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *LobbyEvent) GetKind() LobbyEventKind {
+	if x != nil && x.Kind != nil {
+		return *x.Kind
+	}
+	return LobbyEventKind_added
+}
+
+func (x *LobbyEvent) GetGame() *GameSummary {
+	if x != nil {
+		return x.Game
+	}
+	return nil
+}
+
+type FindMatchRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	GameId        *int32                 `protobuf:"varint,1,req,name=game_id,json=gameId" json:"game_id,omitempty"`
-	Column        *int32                 `protobuf:"varint,2,req,name=column" json:"column,omitempty"`
-	InputTeam     *Team                  `protobuf:"varint,3,req,name=input_team,json=inputTeam,enum=Team" json:"input_team,omitempty"`
+	Variant       *Variant               `protobuf:"bytes,1,opt,name=variant" json:"variant,omitempty"`
+	Rated         *bool                  `protobuf:"varint,2,opt,name=rated" json:"rated,omitempty"` // only paired with other rated players of a close rating
+	PlayerName    *string                `protobuf:"bytes,3,opt,name=player_name,json=playerName" json:"player_name,omitempty"`
+	TimeControl   *TimeControl           `protobuf:"bytes,4,opt,name=time_control,json=timeControl" json:"time_control,omitempty"` // only paired with players asking for the same one
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindMatchRequest) Reset() {
+	*x = FindMatchRequest{}
+	mi := &file_pb_moves_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindMatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindMatchRequest) ProtoMessage() {}
+
+func (x *FindMatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindMatchRequest.ProtoReflect.Descriptor instead.
+func (*FindMatchRequest) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *FindMatchRequest) GetVariant() *Variant {
+	if x != nil {
+		return x.Variant
+	}
+	return nil
+}
+
+func (x *FindMatchRequest) GetRated() bool {
+	if x != nil && x.Rated != nil {
+		return *x.Rated
+	}
+	return false
+}
+
+func (x *FindMatchRequest) GetPlayerName() string {
+	if x != nil && x.PlayerName != nil {
+		return *x.PlayerName
+	}
+	return ""
+}
+
+func (x *FindMatchRequest) GetTimeControl() *TimeControl {
+	if x != nil {
+		return x.TimeControl
+	}
+	return nil
+}
+
+type MatchUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Queued        *int32                 `protobuf:"varint,1,opt,name=queued" json:"queued,omitempty"` // players waiting in the queue, sent when entering it
+	Match         *GameIDAndTeam         `protobuf:"bytes,2,opt,name=match" json:"match,omitempty"`    // last message of the stream
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MatchUpdate) Reset() {
+	*x = MatchUpdate{}
+	mi := &file_pb_moves_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MatchUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MatchUpdate) ProtoMessage() {}
+
+func (x *MatchUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MatchUpdate.ProtoReflect.Descriptor instead.
+func (*MatchUpdate) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *MatchUpdate) GetQueued() int32 {
+	if x != nil && x.Queued != nil {
+		return *x.Queued
+	}
+	return 0
+}
+
+func (x *MatchUpdate) GetMatch() *GameIDAndTeam {
+	if x != nil {
+		return x.Match
+	}
+	return nil
+}
+
+type Credentials struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      *string                `protobuf:"bytes,1,req,name=username" json:"username,omitempty"`
+	Password      *string                `protobuf:"bytes,2,req,name=password" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Credentials) Reset() {
+	*x = Credentials{}
+	mi := &file_pb_moves_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Credentials) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Credentials) ProtoMessage() {}
+
+func (x *Credentials) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Credentials.ProtoReflect.Descriptor instead.
+func (*Credentials) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *Credentials) GetUsername() string {
+	if x != nil && x.Username != nil {
+		return *x.Username
+	}
+	return ""
+}
+
+func (x *Credentials) GetPassword() string {
+	if x != nil && x.Password != nil {
+		return *x.Password
+	}
+	return ""
+}
+
+type Session struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         *string                `protobuf:"bytes,1,req,name=token" json:"token,omitempty"`
+	Username      *string                `protobuf:"bytes,2,req,name=username" json:"username,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Session) Reset() {
+	*x = Session{}
+	mi := &file_pb_moves_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Session) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Session) ProtoMessage() {}
+
+func (x *Session) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Session.ProtoReflect.Descriptor instead.
+func (*Session) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *Session) GetToken() string {
+	if x != nil && x.Token != nil {
+		return *x.Token
+	}
+	return ""
+}
+
+func (x *Session) GetUsername() string {
+	if x != nil && x.Username != nil {
+		return *x.Username
+	}
+	return ""
+}
+
+func (x *Session) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+type Move struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Column        *int32                 `protobuf:"varint,1,req,name=column" json:"column,omitempty"` // 1 based like Input.column
+	Team          *Team                  `protobuf:"varint,2,req,name=team,enum=Team" json:"team,omitempty"`
+	PlayedAt      *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=played_at,json=playedAt" json:"played_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Move) Reset() {
+	*x = Move{}
+	mi := &file_pb_moves_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Move) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Move) ProtoMessage() {}
+
+func (x *Move) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Move.ProtoReflect.Descriptor instead.
+func (*Move) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *Move) GetColumn() int32 {
+	if x != nil && x.Column != nil {
+		return *x.Column
+	}
+	return 0
+}
+
+func (x *Move) GetTeam() Team {
+	if x != nil && x.Team != nil {
+		return *x.Team
+	}
+	return Team_empty
+}
+
+func (x *Move) GetPlayedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PlayedAt
+	}
+	return nil
+}
+
+// One board of the series played on a game id.
+type BoardHistory struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Moves         []*Move                `protobuf:"bytes,1,rep,name=moves" json:"moves,omitempty"`
+	Result        *Result                `protobuf:"varint,2,opt,name=result,enum=Result" json:"result,omitempty"`
+	EndReason     *EndReason             `protobuf:"varint,3,opt,name=end_reason,json=endReason,enum=EndReason" json:"end_reason,omitempty"`
+	First         *Team                  `protobuf:"varint,4,opt,name=first,enum=Team" json:"first,omitempty"` // who moved first on the board
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BoardHistory) Reset() {
+	*x = BoardHistory{}
+	mi := &file_pb_moves_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BoardHistory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BoardHistory) ProtoMessage() {}
+
+func (x *BoardHistory) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BoardHistory.ProtoReflect.Descriptor instead.
+func (*BoardHistory) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *BoardHistory) GetMoves() []*Move {
+	if x != nil {
+		return x.Moves
+	}
+	return nil
+}
+
+func (x *BoardHistory) GetResult() Result {
+	if x != nil && x.Result != nil {
+		return *x.Result
+	}
+	return Result_in_progress
+}
+
+func (x *BoardHistory) GetEndReason() EndReason {
+	if x != nil && x.EndReason != nil {
+		return *x.EndReason
+	}
+	return EndReason_not_over
+}
+
+func (x *BoardHistory) GetFirst() Team {
+	if x != nil && x.First != nil {
+		return *x.First
+	}
+	return Team_empty
+}
+
+type GameHistory struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            *int32                 `protobuf:"varint,1,req,name=id" json:"id,omitempty"`
+	Variant       *Variant               `protobuf:"bytes,2,opt,name=variant" json:"variant,omitempty"`
+	Players       *Players               `protobuf:"bytes,3,opt,name=players" json:"players,omitempty"`
+	Score         *Score                 `protobuf:"bytes,4,opt,name=score" json:"score,omitempty"`
+	Boards        []*BoardHistory        `protobuf:"bytes,5,rep,name=boards" json:"boards,omitempty"` // oldest first, the last one being in play until the game finished
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
+	FinishedAt    *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=finished_at,json=finishedAt" json:"finished_at,omitempty"` // unset while the game is on
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GameHistory) Reset() {
+	*x = GameHistory{}
+	mi := &file_pb_moves_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameHistory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameHistory) ProtoMessage() {}
+
+func (x *GameHistory) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameHistory.ProtoReflect.Descriptor instead.
+func (*GameHistory) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *GameHistory) GetId() int32 {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return 0
+}
+
+func (x *GameHistory) GetVariant() *Variant {
+	if x != nil {
+		return x.Variant
+	}
+	return nil
+}
+
+func (x *GameHistory) GetPlayers() *Players {
+	if x != nil {
+		return x.Players
+	}
+	return nil
+}
+
+func (x *GameHistory) GetScore() *Score {
+	if x != nil {
+		return x.Score
+	}
+	return nil
+}
+
+func (x *GameHistory) GetBoards() []*BoardHistory {
+	if x != nil {
+		return x.Boards
+	}
+	return nil
+}
+
+func (x *GameHistory) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *GameHistory) GetFinishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return nil
+}
+
+type ReplayRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    *int32                 `protobuf:"varint,1,req,name=id" json:"id,omitempty"`
+	Board *int32                 `protobuf:"varint,2,opt,name=board" json:"board,omitempty"` // index in GameHistory.boards, every board in turn when unset
+	// Playback rate, 1 keeping the time taken by the players and 2 going twice as fast. Unset or 0
+	// plays a move per second.
+	Speed         *float64 `protobuf:"fixed64,3,opt,name=speed" json:"speed,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Input) Reset() {
-	*x = Input{}
-	mi := &file_pb_moves_proto_msgTypes[0]
+func (x *ReplayRequest) Reset() {
+	*x = ReplayRequest{}
+	mi := &file_pb_moves_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Input) String() string {
+func (x *ReplayRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Input) ProtoMessage() {}
+func (*ReplayRequest) ProtoMessage() {}
 
-func (x *Input) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_moves_proto_msgTypes[0]
+func (x *ReplayRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -114,55 +3272,55 @@ func (x *Input) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Input.ProtoReflect.Descriptor instead.
-func (*Input) Descriptor() ([]byte, []int) {
-	return file_pb_moves_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use ReplayRequest.ProtoReflect.Descriptor instead.
+func (*ReplayRequest) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{36}
 }
 
-func (x *Input) GetGameId() int32 {
-	if x != nil && x.GameId != nil {
-		return *x.GameId
+func (x *ReplayRequest) GetId() int32 {
+	if x != nil && x.Id != nil {
+		return *x.Id
 	}
 	return 0
 }
 
-func (x *Input) GetColumn() int32 {
-	if x != nil && x.Column != nil {
-		return *x.Column
+func (x *ReplayRequest) GetBoard() int32 {
+	if x != nil && x.Board != nil {
+		return *x.Board
 	}
 	return 0
 }
 
-func (x *Input) GetInputTeam() Team {
-	if x != nil && x.InputTeam != nil {
-		return *x.InputTeam
+func (x *ReplayRequest) GetSpeed() float64 {
+	if x != nil && x.Speed != nil {
+		return *x.Speed
 	}
-	return Team_empty
+	return 0
 }
 
-type State struct {
+type ExportGameRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Field         *Field                 `protobuf:"bytes,1,req,name=field" json:"field,omitempty"`
-	Turn          *Team                  `protobuf:"varint,2,req,name=turn,enum=Team" json:"turn,omitempty"`
+	Id            *int32                 `protobuf:"varint,1,req,name=id" json:"id,omitempty"`
+	Board         *int32                 `protobuf:"varint,2,opt,name=board" json:"board,omitempty"` // index in GameHistory.boards, the last one when unset
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *State) Reset() {
-	*x = State{}
-	mi := &file_pb_moves_proto_msgTypes[1]
+func (x *ExportGameRequest) Reset() {
+	*x = ExportGameRequest{}
+	mi := &file_pb_moves_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *State) String() string {
+func (x *ExportGameRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*State) ProtoMessage() {}
+func (*ExportGameRequest) ProtoMessage() {}
 
-func (x *State) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_moves_proto_msgTypes[1]
+func (x *ExportGameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -173,47 +3331,47 @@ func (x *State) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use State.ProtoReflect.Descriptor instead.
-func (*State) Descriptor() ([]byte, []int) {
-	return file_pb_moves_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use ExportGameRequest.ProtoReflect.Descriptor instead.
+func (*ExportGameRequest) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{37}
 }
 
-func (x *State) GetField() *Field {
-	if x != nil {
-		return x.Field
+func (x *ExportGameRequest) GetId() int32 {
+	if x != nil && x.Id != nil {
+		return *x.Id
 	}
-	return nil
+	return 0
 }
 
-func (x *State) GetTurn() Team {
-	if x != nil && x.Turn != nil {
-		return *x.Turn
+func (x *ExportGameRequest) GetBoard() int32 {
+	if x != nil && x.Board != nil {
+		return *x.Board
 	}
-	return Team_empty
+	return 0
 }
 
-type Field struct {
+type GameNotation struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Rows          []*Row                 `protobuf:"bytes,1,rep,name=rows" json:"rows,omitempty"`
+	Notation      *string                `protobuf:"bytes,1,req,name=notation" json:"notation,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Field) Reset() {
-	*x = Field{}
-	mi := &file_pb_moves_proto_msgTypes[2]
+func (x *GameNotation) Reset() {
+	*x = GameNotation{}
+	mi := &file_pb_moves_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Field) String() string {
+func (x *GameNotation) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Field) ProtoMessage() {}
+func (*GameNotation) ProtoMessage() {}
 
-func (x *Field) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_moves_proto_msgTypes[2]
+func (x *GameNotation) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -224,40 +3382,42 @@ func (x *Field) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Field.ProtoReflect.Descriptor instead.
-func (*Field) Descriptor() ([]byte, []int) {
-	return file_pb_moves_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use GameNotation.ProtoReflect.Descriptor instead.
+func (*GameNotation) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{38}
 }
 
-func (x *Field) GetRows() []*Row {
-	if x != nil {
-		return x.Rows
+func (x *GameNotation) GetNotation() string {
+	if x != nil && x.Notation != nil {
+		return *x.Notation
 	}
-	return nil
+	return ""
 }
 
-type Row struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Values        []Team                 `protobuf:"varint,1,rep,name=values,enum=Team" json:"values,omitempty"`
+type ImportPositionRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Notation *string                `protobuf:"bytes,1,req,name=notation" json:"notation,omitempty"`
+	// Settings of the game created like for NewGame, the variant coming from the notation.
+	Game          *NewGameRequest `protobuf:"bytes,2,opt,name=game" json:"game,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Row) Reset() {
-	*x = Row{}
-	mi := &file_pb_moves_proto_msgTypes[3]
+func (x *ImportPositionRequest) Reset() {
+	*x = ImportPositionRequest{}
+	mi := &file_pb_moves_proto_msgTypes[39]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Row) String() string {
+func (x *ImportPositionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Row) ProtoMessage() {}
+func (*ImportPositionRequest) ProtoMessage() {}
 
-func (x *Row) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_moves_proto_msgTypes[3]
+func (x *ImportPositionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[39]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -268,39 +3428,47 @@ func (x *Row) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Row.ProtoReflect.Descriptor instead.
-func (*Row) Descriptor() ([]byte, []int) {
-	return file_pb_moves_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use ImportPositionRequest.ProtoReflect.Descriptor instead.
+func (*ImportPositionRequest) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{39}
 }
 
-func (x *Row) GetValues() []Team {
+func (x *ImportPositionRequest) GetNotation() string {
+	if x != nil && x.Notation != nil {
+		return *x.Notation
+	}
+	return ""
+}
+
+func (x *ImportPositionRequest) GetGame() *NewGameRequest {
 	if x != nil {
-		return x.Values
+		return x.Game
 	}
 	return nil
 }
 
-type Empty struct {
+type PlayerProfileRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      *string                `protobuf:"bytes,1,opt,name=username" json:"username,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Empty) Reset() {
-	*x = Empty{}
-	mi := &file_pb_moves_proto_msgTypes[4]
+func (x *PlayerProfileRequest) Reset() {
+	*x = PlayerProfileRequest{}
+	mi := &file_pb_moves_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Empty) String() string {
+func (x *PlayerProfileRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Empty) ProtoMessage() {}
+func (*PlayerProfileRequest) ProtoMessage() {}
 
-func (x *Empty) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_moves_proto_msgTypes[4]
+func (x *PlayerProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -311,34 +3479,47 @@ func (x *Empty) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Empty.ProtoReflect.Descriptor instead.
-func (*Empty) Descriptor() ([]byte, []int) {
-	return file_pb_moves_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use PlayerProfileRequest.ProtoReflect.Descriptor instead.
+func (*PlayerProfileRequest) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{40}
 }
 
-type GameIDAndTeam struct {
+func (x *PlayerProfileRequest) GetUsername() string {
+	if x != nil && x.Username != nil {
+		return *x.Username
+	}
+	return ""
+}
+
+type PlayerProfile struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            *int32                 `protobuf:"varint,1,req,name=id" json:"id,omitempty"`
-	Team          *Team                  `protobuf:"varint,2,req,name=team,enum=Team" json:"team,omitempty"`
+	Username      *string                `protobuf:"bytes,1,req,name=username" json:"username,omitempty"`
+	Rating        *float64               `protobuf:"fixed64,2,opt,name=rating" json:"rating,omitempty"`
+	Deviation     *float64               `protobuf:"fixed64,3,opt,name=deviation" json:"deviation,omitempty"` // how uncertain the rating is, shrinking as rated games are played
+	Wins          *int32                 `protobuf:"varint,4,opt,name=wins" json:"wins,omitempty"`
+	Losses        *int32                 `protobuf:"varint,5,opt,name=losses" json:"losses,omitempty"`
+	Draws         *int32                 `protobuf:"varint,6,opt,name=draws" json:"draws,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
+	Rank          *int32                 `protobuf:"varint,8,opt,name=rank" json:"rank,omitempty"` // 1 based place on the leaderboard, 0 before the first rated game
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GameIDAndTeam) Reset() {
-	*x = GameIDAndTeam{}
-	mi := &file_pb_moves_proto_msgTypes[5]
+func (x *PlayerProfile) Reset() {
+	*x = PlayerProfile{}
+	mi := &file_pb_moves_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GameIDAndTeam) String() string {
+func (x *PlayerProfile) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GameIDAndTeam) ProtoMessage() {}
+func (*PlayerProfile) ProtoMessage() {}
 
-func (x *GameIDAndTeam) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_moves_proto_msgTypes[5]
+func (x *PlayerProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -349,47 +3530,90 @@ func (x *GameIDAndTeam) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GameIDAndTeam.ProtoReflect.Descriptor instead.
-func (*GameIDAndTeam) Descriptor() ([]byte, []int) {
-	return file_pb_moves_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use PlayerProfile.ProtoReflect.Descriptor instead.
+func (*PlayerProfile) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{41}
 }
 
-func (x *GameIDAndTeam) GetId() int32 {
-	if x != nil && x.Id != nil {
-		return *x.Id
+func (x *PlayerProfile) GetUsername() string {
+	if x != nil && x.Username != nil {
+		return *x.Username
+	}
+	return ""
+}
+
+func (x *PlayerProfile) GetRating() float64 {
+	if x != nil && x.Rating != nil {
+		return *x.Rating
 	}
 	return 0
 }
 
-func (x *GameIDAndTeam) GetTeam() Team {
-	if x != nil && x.Team != nil {
-		return *x.Team
+func (x *PlayerProfile) GetDeviation() float64 {
+	if x != nil && x.Deviation != nil {
+		return *x.Deviation
 	}
-	return Team_empty
+	return 0
 }
 
-type GameID struct {
+func (x *PlayerProfile) GetWins() int32 {
+	if x != nil && x.Wins != nil {
+		return *x.Wins
+	}
+	return 0
+}
+
+func (x *PlayerProfile) GetLosses() int32 {
+	if x != nil && x.Losses != nil {
+		return *x.Losses
+	}
+	return 0
+}
+
+func (x *PlayerProfile) GetDraws() int32 {
+	if x != nil && x.Draws != nil {
+		return *x.Draws
+	}
+	return 0
+}
+
+func (x *PlayerProfile) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *PlayerProfile) GetRank() int32 {
+	if x != nil && x.Rank != nil {
+		return *x.Rank
+	}
+	return 0
+}
+
+type LeaderboardRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            *int32                 `protobuf:"varint,1,req,name=id" json:"id,omitempty"`
+	PageSize      *int32                 `protobuf:"varint,1,opt,name=page_size,json=pageSize" json:"page_size,omitempty"`   // 50 when unset, at most 200
+	PageToken     *string                `protobuf:"bytes,2,opt,name=page_token,json=pageToken" json:"page_token,omitempty"` // next_page_token of the previous page
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GameID) Reset() {
-	*x = GameID{}
-	mi := &file_pb_moves_proto_msgTypes[6]
+func (x *LeaderboardRequest) Reset() {
+	*x = LeaderboardRequest{}
+	mi := &file_pb_moves_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GameID) String() string {
+func (x *LeaderboardRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GameID) ProtoMessage() {}
+func (*LeaderboardRequest) ProtoMessage() {}
 
-func (x *GameID) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_moves_proto_msgTypes[6]
+func (x *LeaderboardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -400,51 +3624,410 @@ func (x *GameID) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GameID.ProtoReflect.Descriptor instead.
-func (*GameID) Descriptor() ([]byte, []int) {
-	return file_pb_moves_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use LeaderboardRequest.ProtoReflect.Descriptor instead.
+func (*LeaderboardRequest) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{42}
 }
 
-func (x *GameID) GetId() int32 {
-	if x != nil && x.Id != nil {
-		return *x.Id
+func (x *LeaderboardRequest) GetPageSize() int32 {
+	if x != nil && x.PageSize != nil {
+		return *x.PageSize
 	}
 	return 0
 }
 
+func (x *LeaderboardRequest) GetPageToken() string {
+	if x != nil && x.PageToken != nil {
+		return *x.PageToken
+	}
+	return ""
+}
+
+type Leaderboard struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Players       []*PlayerProfile       `protobuf:"bytes,1,rep,name=players" json:"players,omitempty"`
+	NextPageToken *string                `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken" json:"next_page_token,omitempty"` // empty on the last page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Leaderboard) Reset() {
+	*x = Leaderboard{}
+	mi := &file_pb_moves_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Leaderboard) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Leaderboard) ProtoMessage() {}
+
+func (x *Leaderboard) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_moves_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Leaderboard.ProtoReflect.Descriptor instead.
+func (*Leaderboard) Descriptor() ([]byte, []int) {
+	return file_pb_moves_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *Leaderboard) GetPlayers() []*PlayerProfile {
+	if x != nil {
+		return x.Players
+	}
+	return nil
+}
+
+func (x *Leaderboard) GetNextPageToken() string {
+	if x != nil && x.NextPageToken != nil {
+		return *x.NextPageToken
+	}
+	return ""
+}
+
 var File_pb_moves_proto protoreflect.FileDescriptor
 
 const file_pb_moves_proto_rawDesc = "" +
 	"\n" +
-	"\x0epb/moves.proto\"^\n" +
+	"\x0epb/moves.proto\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xba\x03\n" +
 	"\x05Input\x12\x17\n" +
-	"\agame_id\x18\x01 \x02(\x05R\x06gameId\x12\x16\n" +
-	"\x06column\x18\x02 \x02(\x05R\x06column\x12$\n" +
+	"\agame_id\x18\x01 \x02(\x05R\x06gameId\x12$\n" +
+	"\n" +
+	"input_team\x18\x03 \x02(\x0e2\x05.teamR\tinputTeam\x12!\n" +
+	"\fresume_token\x18\x04 \x01(\tR\vresumeToken\x12\x18\n" +
+	"\x06column\x18\x02 \x01(\x05H\x00R\x06column\x12 \n" +
+	"\x06resign\x18\x05 \x01(\v2\x06.EmptyH\x00R\x06resign\x12'\n" +
 	"\n" +
-	"input_team\x18\x03 \x02(\x0e2\x05.teamR\tinputTeam\"@\n" +
+	"offer_draw\x18\x06 \x01(\v2\x06.EmptyH\x00R\tofferDraw\x12 \n" +
+	"\x06accept\x18\a \x01(\v2\x06.EmptyH\x00R\x06accept\x12\"\n" +
+	"\adecline\x18\b \x01(\v2\x06.EmptyH\x00R\adecline\x123\n" +
+	"\x10request_takeback\x18\t \x01(\v2\x06.EmptyH\x00R\x0frequestTakeback\x12\"\n" +
+	"\arematch\x18\n" +
+	" \x01(\v2\x06.EmptyH\x00R\arematch\x12\"\n" +
+	"\x04chat\x18\v \x01(\v2\f.ChatMessageH\x00R\x04chat\x12\x1d\n" +
+	"\tmute_chat\x18\f \x01(\bH\x00R\bmuteChatB\b\n" +
+	"\x06action\"C\n" +
+	"\x05Offer\x12\x1f\n" +
+	"\x04kind\x18\x01 \x02(\x0e2\v.offer_kindR\x04kind\x12\x19\n" +
+	"\x04from\x18\x02 \x02(\x0e2\x05.teamR\x04from\"\xbf\x04\n" +
 	"\x05State\x12\x1c\n" +
 	"\x05field\x18\x01 \x02(\v2\x06.FieldR\x05field\x12\x19\n" +
-	"\x04turn\x18\x02 \x02(\x0e2\x05.teamR\x04turn\"!\n" +
+	"\x04turn\x18\x02 \x02(\x0e2\x05.teamR\x04turn\x12\"\n" +
+	"\avariant\x18\x03 \x01(\v2\b.VariantR\avariant\x12\x1f\n" +
+	"\x06result\x18\x04 \x01(\x0e2\a.resultR\x06result\x12(\n" +
+	"\fwinning_line\x18\x05 \x03(\v2\x05.CellR\vwinningLine\x12(\n" +
+	"\trejection\x18\x06 \x01(\v2\n" +
+	".RejectionR\trejection\x12\x1c\n" +
+	"\x05score\x18\a \x01(\v2\x06.ScoreR\x05score\x12\"\n" +
+	"\aplayers\x18\b \x01(\v2\b.PlayersR\aplayers\x12\x1f\n" +
+	"\x06clocks\x18\t \x01(\v2\a.ClocksR\x06clocks\x12*\n" +
+	"\n" +
+	"end_reason\x18\n" +
+	" \x01(\x0e2\v.end_reasonR\tendReason\x12\x1c\n" +
+	"\x05offer\x18\v \x01(\v2\x06.OfferR\x05offer\x12$\n" +
+	"\n" +
+	"next_first\x18\f \x01(\x0e2\x05.teamR\tnextFirst\x12\x1d\n" +
+	"\x04chat\x18\r \x01(\v2\t.ChatLineR\x04chat\x125\n" +
+	"\x0erating_changes\x18\x0e \x01(\v2\x0e.RatingChangesR\rratingChanges\x12;\n" +
+	"\vshutdown_at\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"shutdownAt\"W\n" +
+	"\rRatingChanges\x12\x1f\n" +
+	"\x03red\x18\x01 \x01(\v2\r.RatingChangeR\x03red\x12%\n" +
+	"\x06yellow\x18\x02 \x01(\v2\r.RatingChangeR\x06yellow\"<\n" +
+	"\fRatingChange\x12\x16\n" +
+	"\x06before\x18\x01 \x02(\x01R\x06before\x12\x14\n" +
+	"\x05after\x18\x02 \x02(\x01R\x05after\"?\n" +
+	"\vChatMessage\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12\x1c\n" +
+	"\x05emote\x18\x02 \x01(\x0e2\x06.emoteR\x05emote\"\xa0\x01\n" +
+	"\bChatLine\x12\x19\n" +
+	"\x04from\x18\x01 \x02(\x0e2\x05.teamR\x04from\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04text\x18\x03 \x01(\tR\x04text\x12\x1c\n" +
+	"\x05emote\x18\x04 \x01(\x0e2\x06.emoteR\x05emote\x123\n" +
+	"\asent_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x06sentAt\"\x82\x01\n" +
+	"\x06Clocks\x12+\n" +
+	"\x03red\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\x03red\x121\n" +
+	"\x06yellow\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\x06yellow\x12\x18\n" +
+	"\arunning\x18\x03 \x01(\bR\arunning\"\xa6\x01\n" +
+	"\vTimeControl\x12$\n" +
+	"\afischer\x18\x01 \x01(\v2\b.FischerH\x00R\afischer\x126\n" +
+	"\bper_move\x18\x02 \x01(\v2\x19.google.protobuf.DurationH\x00R\aperMove\x121\n" +
+	"\x13correspondence_days\x18\x03 \x01(\x05H\x00R\x12correspondenceDaysB\x06\n" +
+	"\x04kind\"w\n" +
+	"\aFischer\x123\n" +
+	"\ainitial\x18\x01 \x02(\v2\x19.google.protobuf.DurationR\ainitial\x127\n" +
+	"\tincrement\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\tincrement\"S\n" +
+	"\aPlayers\x12\x10\n" +
+	"\x03red\x18\x01 \x01(\tR\x03red\x12\x16\n" +
+	"\x06yellow\x18\x02 \x01(\tR\x06yellow\x12\x1e\n" +
+	"\n" +
+	"spectators\x18\x03 \x01(\x05R\n" +
+	"spectators\"\xc1\x01\n" +
+	"\x05Score\x12\x19\n" +
+	"\bred_wins\x18\x01 \x01(\x05R\aredWins\x12\x1f\n" +
+	"\vyellow_wins\x18\x02 \x01(\x05R\n" +
+	"yellowWins\x12\x14\n" +
+	"\x05draws\x18\x03 \x01(\x05R\x05draws\x12!\n" +
+	"\fgames_played\x18\x04 \x01(\x05R\vgamesPlayed\x12\x19\n" +
+	"\bfirst_to\x18\x05 \x01(\x05R\afirstTo\x12(\n" +
+	"\fmatch_winner\x18\x06 \x01(\x0e2\x05.teamR\vmatchWinner\"h\n" +
+	"\tRejection\x12)\n" +
+	"\x06reason\x18\x01 \x02(\x0e2\x11.rejection_reasonR\x06reason\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x16\n" +
+	"\x06column\x18\x03 \x01(\x05R\x06column\"0\n" +
+	"\x04Cell\x12\x10\n" +
+	"\x03row\x18\x01 \x02(\x05R\x03row\x12\x16\n" +
+	"\x06column\x18\x02 \x02(\x05R\x06column\"Q\n" +
+	"\aVariant\x12\x12\n" +
+	"\x04rows\x18\x01 \x01(\x05R\x04rows\x12\x18\n" +
+	"\acolumns\x18\x02 \x01(\x05R\acolumns\x12\x18\n" +
+	"\aconnect\x18\x03 \x01(\x05R\aconnect\"\xc7\x02\n" +
+	"\x0eNewGameRequest\x12\"\n" +
+	"\avariant\x18\x01 \x01(\v2\b.VariantR\avariant\x12\x19\n" +
+	"\bfirst_to\x18\x02 \x01(\x05R\afirstTo\x12%\n" +
+	"\bopponent\x18\x03 \x01(\x0e2\t.opponentR\bopponent\x12\x1b\n" +
+	"\tbot_level\x18\x04 \x01(\x05R\bbotLevel\x12\x1f\n" +
+	"\vplayer_name\x18\x05 \x01(\tR\n" +
+	"playerName\x12\x18\n" +
+	"\aprivate\x18\x06 \x01(\bR\aprivate\x12/\n" +
+	"\ftime_control\x18\a \x01(\v2\f.TimeControlR\vtimeControl\x120\n" +
+	"\ffirst_player\x18\b \x01(\x0e2\r.first_playerR\vfirstPlayer\x12\x14\n" +
+	"\x05rated\x18\t \x01(\bR\x05rated\"!\n" +
 	"\x05Field\x12\x18\n" +
 	"\x04rows\x18\x01 \x03(\v2\x04.RowR\x04rows\"$\n" +
 	"\x03Row\x12\x1d\n" +
 	"\x06values\x18\x01 \x03(\x0e2\x05.teamR\x06values\"\a\n" +
-	"\x05Empty\":\n" +
+	"\x05Empty\"\x81\x01\n" +
 	"\rGameIDAndTeam\x12\x0e\n" +
 	"\x02id\x18\x01 \x02(\x05R\x02id\x12\x19\n" +
-	"\x04team\x18\x02 \x02(\x0e2\x05.teamR\x04team\"\x18\n" +
+	"\x04team\x18\x02 \x02(\x0e2\x05.teamR\x04team\x12\"\n" +
+	"\avariant\x18\x03 \x01(\v2\b.VariantR\avariant\x12!\n" +
+	"\fresume_token\x18\x04 \x01(\tR\vresumeToken\"\x18\n" +
 	"\x06GameID\x12\x0e\n" +
-	"\x02id\x18\x01 \x02(\x05R\x02id*&\n" +
+	"\x02id\x18\x01 \x02(\x05R\x02id\"B\n" +
+	"\x0fJoinGameRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x02(\x05R\x02id\x12\x1f\n" +
+	"\vplayer_name\x18\x02 \x01(\tR\n" +
+	"playerName\"\xa2\x01\n" +
+	"\x0eAnalyzeRequest\x12\x17\n" +
+	"\agame_id\x18\x01 \x01(\x05R\x06gameId\x12\x1c\n" +
+	"\x05field\x18\x02 \x01(\v2\x06.FieldR\x05field\x12\x18\n" +
+	"\aconnect\x18\x03 \x01(\x05R\aconnect\x12\x1b\n" +
+	"\tmax_depth\x18\x04 \x01(\x05R\bmaxDepth\x12\"\n" +
+	"\rtime_limit_ms\x18\x05 \x01(\x05R\vtimeLimitMs\"\x8e\x01\n" +
+	"\x0eColumnAnalysis\x12\x16\n" +
+	"\x06column\x18\x01 \x02(\x05R\x06column\x12\x14\n" +
+	"\x05legal\x18\x02 \x01(\bR\x05legal\x12\"\n" +
+	"\aoutcome\x18\x03 \x01(\x0e2\b.outcomeR\aoutcome\x12\x14\n" +
+	"\x05plies\x18\x04 \x01(\x05R\x05plies\x12\x14\n" +
+	"\x05score\x18\x05 \x01(\x05R\x05score\"\x98\x01\n" +
+	"\bAnalysis\x12)\n" +
+	"\acolumns\x18\x01 \x03(\v2\x0f.ColumnAnalysisR\acolumns\x12\x19\n" +
+	"\x04turn\x18\x02 \x01(\x0e2\x05.teamR\x04turn\x12\x14\n" +
+	"\x05depth\x18\x03 \x01(\x05R\x05depth\x12\x1a\n" +
+	"\bcomplete\x18\x04 \x01(\bR\bcomplete\x12\x14\n" +
+	"\x05nodes\x18\x05 \x01(\x03R\x05nodes\"\xba\x02\n" +
+	"\vGameSummary\x12\x0e\n" +
+	"\x02id\x18\x01 \x02(\x05R\x02id\x12\x1b\n" +
+	"\thost_name\x18\x02 \x01(\tR\bhostName\x12\"\n" +
+	"\avariant\x18\x03 \x01(\v2\b.VariantR\avariant\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"seats_free\x18\x05 \x01(\x05R\tseatsFree\x12\x19\n" +
+	"\bfirst_to\x18\x06 \x01(\x05R\afirstTo\x12\x1e\n" +
+	"\n" +
+	"spectators\x18\a \x01(\x05R\n" +
+	"spectators\x12/\n" +
+	"\ftime_control\x18\b \x01(\v2\f.TimeControlR\vtimeControl\x12\x14\n" +
+	"\x05rated\x18\t \x01(\bR\x05rated\"\xc6\x01\n" +
+	"\x10ListGamesRequest\x12\"\n" +
+	"\avariant\x18\x01 \x01(\v2\b.VariantR\avariant\x12!\n" +
+	"\finclude_full\x18\x02 \x01(\bR\vincludeFull\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\x12/\n" +
+	"\ftime_control\x18\x05 \x01(\v2\f.TimeControlR\vtimeControl\"_\n" +
+	"\x11ListGamesResponse\x12\"\n" +
+	"\x05games\x18\x01 \x03(\v2\f.GameSummaryR\x05games\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"U\n" +
+	"\n" +
+	"LobbyEvent\x12%\n" +
+	"\x04kind\x18\x01 \x02(\x0e2\x11.lobby_event_kindR\x04kind\x12 \n" +
+	"\x04game\x18\x02 \x02(\v2\f.GameSummaryR\x04game\"\x9e\x01\n" +
+	"\x10FindMatchRequest\x12\"\n" +
+	"\avariant\x18\x01 \x01(\v2\b.VariantR\avariant\x12\x14\n" +
+	"\x05rated\x18\x02 \x01(\bR\x05rated\x12\x1f\n" +
+	"\vplayer_name\x18\x03 \x01(\tR\n" +
+	"playerName\x12/\n" +
+	"\ftime_control\x18\x04 \x01(\v2\f.TimeControlR\vtimeControl\"K\n" +
+	"\vMatchUpdate\x12\x16\n" +
+	"\x06queued\x18\x01 \x01(\x05R\x06queued\x12$\n" +
+	"\x05match\x18\x02 \x01(\v2\x0e.GameIDAndTeamR\x05match\"E\n" +
+	"\vCredentials\x12\x1a\n" +
+	"\busername\x18\x01 \x02(\tR\busername\x12\x1a\n" +
+	"\bpassword\x18\x02 \x02(\tR\bpassword\"v\n" +
+	"\aSession\x12\x14\n" +
+	"\x05token\x18\x01 \x02(\tR\x05token\x12\x1a\n" +
+	"\busername\x18\x02 \x02(\tR\busername\x129\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"r\n" +
+	"\x04Move\x12\x16\n" +
+	"\x06column\x18\x01 \x02(\x05R\x06column\x12\x19\n" +
+	"\x04team\x18\x02 \x02(\x0e2\x05.teamR\x04team\x127\n" +
+	"\tplayed_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\bplayedAt\"\x95\x01\n" +
+	"\fBoardHistory\x12\x1b\n" +
+	"\x05moves\x18\x01 \x03(\v2\x05.MoveR\x05moves\x12\x1f\n" +
+	"\x06result\x18\x02 \x01(\x0e2\a.resultR\x06result\x12*\n" +
+	"\n" +
+	"end_reason\x18\x03 \x01(\x0e2\v.end_reasonR\tendReason\x12\x1b\n" +
+	"\x05first\x18\x04 \x01(\x0e2\x05.teamR\x05first\"\xa2\x02\n" +
+	"\vGameHistory\x12\x0e\n" +
+	"\x02id\x18\x01 \x02(\x05R\x02id\x12\"\n" +
+	"\avariant\x18\x02 \x01(\v2\b.VariantR\avariant\x12\"\n" +
+	"\aplayers\x18\x03 \x01(\v2\b.PlayersR\aplayers\x12\x1c\n" +
+	"\x05score\x18\x04 \x01(\v2\x06.ScoreR\x05score\x12%\n" +
+	"\x06boards\x18\x05 \x03(\v2\r.BoardHistoryR\x06boards\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12;\n" +
+	"\vfinished_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"finishedAt\"K\n" +
+	"\rReplayRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x02(\x05R\x02id\x12\x14\n" +
+	"\x05board\x18\x02 \x01(\x05R\x05board\x12\x14\n" +
+	"\x05speed\x18\x03 \x01(\x01R\x05speed\"9\n" +
+	"\x11ExportGameRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x02(\x05R\x02id\x12\x14\n" +
+	"\x05board\x18\x02 \x01(\x05R\x05board\"*\n" +
+	"\fGameNotation\x12\x1a\n" +
+	"\bnotation\x18\x01 \x02(\tR\bnotation\"X\n" +
+	"\x15ImportPositionRequest\x12\x1a\n" +
+	"\bnotation\x18\x01 \x02(\tR\bnotation\x12#\n" +
+	"\x04game\x18\x02 \x01(\v2\x0f.NewGameRequestR\x04game\"2\n" +
+	"\x14PlayerProfileRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\"\xf2\x01\n" +
+	"\rPlayerProfile\x12\x1a\n" +
+	"\busername\x18\x01 \x02(\tR\busername\x12\x16\n" +
+	"\x06rating\x18\x02 \x01(\x01R\x06rating\x12\x1c\n" +
+	"\tdeviation\x18\x03 \x01(\x01R\tdeviation\x12\x12\n" +
+	"\x04wins\x18\x04 \x01(\x05R\x04wins\x12\x16\n" +
+	"\x06losses\x18\x05 \x01(\x05R\x06losses\x12\x14\n" +
+	"\x05draws\x18\x06 \x01(\x05R\x05draws\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x12\n" +
+	"\x04rank\x18\b \x01(\x05R\x04rank\"P\n" +
+	"\x12LeaderboardRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\"_\n" +
+	"\vLeaderboard\x12(\n" +
+	"\aplayers\x18\x01 \x03(\v2\x0e.PlayerProfileR\aplayers\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken*&\n" +
 	"\x04team\x12\t\n" +
 	"\x05empty\x10\x00\x12\n" +
 	"\n" +
 	"\x06yellow\x10\x02\x12\a\n" +
-	"\x03red\x10\x012\xa7\x01\n" +
-	"\bconnect4\x12(\n" +
-	"\x10CommunicateState\x12\x06.Input\x1a\x06.State\"\x00(\x010\x01\x12#\n" +
-	"\aNewGame\x12\x06.Empty\x1a\x0e.GameIDAndTeam\"\x00\x12%\n" +
-	"\bJoinGame\x12\a.GameID\x1a\x0e.GameIDAndTeam\"\x00\x12%\n" +
-	"\tLeaveGame\x12\x0e.GameIDAndTeam\x1a\x06.Empty\"\x00B\x12Z\x10connect4-grpc/pb"
+	"\x03red\x10\x01*7\n" +
+	"\n" +
+	"offer_kind\x12\x0e\n" +
+	"\n" +
+	"draw_offer\x10\x01\x12\f\n" +
+	"\btakeback\x10\x02\x12\v\n" +
+	"\arematch\x10\x03*d\n" +
+	"\x05emote\x12\f\n" +
+	"\bno_emote\x10\x00\x12\t\n" +
+	"\x05hello\x10\x01\x12\r\n" +
+	"\tgood_game\x10\x02\x12\x0f\n" +
+	"\vwell_played\x10\x03\x12\b\n" +
+	"\x04oops\x10\x04\x12\f\n" +
+	"\bthinking\x10\x05\x12\n" +
+	"\n" +
+	"\x06thanks\x10\x06*c\n" +
+	"\n" +
+	"end_reason\x12\f\n" +
+	"\bnot_over\x10\x00\x12\v\n" +
+	"\aaligned\x10\x01\x12\x0e\n" +
+	"\n" +
+	"board_full\x10\x02\x12\v\n" +
+	"\atimeout\x10\x03\x12\f\n" +
+	"\bresigned\x10\x04\x12\x0f\n" +
+	"\vagreed_draw\x10\x05*\xa8\x02\n" +
+	"\x10rejection_reason\x12\x10\n" +
+	"\fnot_rejected\x10\x00\x12\x11\n" +
+	"\rnot_your_turn\x10\x01\x12\x0f\n" +
+	"\vcolumn_full\x10\x02\x12\x17\n" +
+	"\x13column_out_of_range\x10\x03\x12\x0e\n" +
+	"\n" +
+	"wrong_game\x10\x04\x12\x0e\n" +
+	"\n" +
+	"match_over\x10\x05\x12\x0e\n" +
+	"\n" +
+	"wrong_team\x10\x06\x12\x0e\n" +
+	"\n" +
+	"board_over\x10\a\x12\f\n" +
+	"\bno_offer\x10\b\x12\x11\n" +
+	"\roffer_pending\x10\t\x12\x18\n" +
+	"\x14nothing_to_take_back\x10\n" +
+	"\x12\x11\n" +
+	"\rboard_in_play\x10\v\x12\x10\n" +
+	"\finvalid_chat\x10\f\x12\x11\n" +
+	"\rshutting_down\x10\r\x12\x12\n" +
+	"\x0eunknown_action\x10\x0e*@\n" +
+	"\x06result\x12\x0f\n" +
+	"\vin_progress\x10\x00\x12\v\n" +
+	"\ared_won\x10\x01\x12\x0e\n" +
+	"\n" +
+	"yellow_won\x10\x02\x12\b\n" +
+	"\x04draw\x10\x03*A\n" +
+	"\ffirst_player\x12\r\n" +
+	"\talternate\x10\x00\x12\x10\n" +
+	"\floser_starts\x10\x01\x12\x10\n" +
+	"\frandom_first\x10\x02*\x1e\n" +
+	"\bopponent\x12\t\n" +
+	"\x05human\x10\x00\x12\a\n" +
+	"\x03bot\x10\x01*I\n" +
+	"\aoutcome\x12\f\n" +
+	"\bunsolved\x10\x00\x12\x0e\n" +
+	"\n" +
+	"forced_win\x10\x01\x12\x0f\n" +
+	"\vforced_loss\x10\x02\x12\x0f\n" +
+	"\vforced_draw\x10\x03*7\n" +
+	"\x10lobby_event_kind\x12\t\n" +
+	"\x05added\x10\x00\x12\v\n" +
+	"\aupdated\x10\x01\x12\v\n" +
+	"\aremoved\x10\x022\xb7\x06\n" +
+	"\bconnect4\x12$\n" +
+	"\bRegister\x12\f.Credentials\x1a\b.Session\"\x00\x12!\n" +
+	"\x05Login\x12\f.Credentials\x1a\b.Session\"\x00\x12\x1b\n" +
+	"\x05Guest\x12\x06.Empty\x1a\b.Session\"\x00\x12(\n" +
+	"\x10CommunicateState\x12\x06.Input\x1a\x06.State\"\x00(\x010\x01\x12,\n" +
+	"\aNewGame\x12\x0f.NewGameRequest\x1a\x0e.GameIDAndTeam\"\x00\x12.\n" +
+	"\bJoinGame\x12\x10.JoinGameRequest\x1a\x0e.GameIDAndTeam\"\x00\x12%\n" +
+	"\tLeaveGame\x12\x0e.GameIDAndTeam\x1a\x06.Empty\"\x00\x12'\n" +
+	"\aAnalyze\x12\x0f.AnalyzeRequest\x1a\t.Analysis\"\x00\x12\x1f\n" +
+	"\bSpectate\x12\a.GameID\x1a\x06.State\"\x000\x01\x124\n" +
+	"\tListGames\x12\x11.ListGamesRequest\x1a\x12.ListGamesResponse\"\x00\x120\n" +
+	"\n" +
+	"WatchLobby\x12\x11.ListGamesRequest\x1a\v.LobbyEvent\"\x000\x01\x120\n" +
+	"\tFindMatch\x12\x11.FindMatchRequest\x1a\f.MatchUpdate\"\x000\x01\x12)\n" +
+	"\x0eGetGameHistory\x12\a.GameID\x1a\f.GameHistory\"\x00\x12$\n" +
+	"\x06Replay\x12\x0e.ReplayRequest\x1a\x06.State\"\x000\x01\x121\n" +
+	"\n" +
+	"ExportGame\x12\x12.ExportGameRequest\x1a\r.GameNotation\"\x00\x12:\n" +
+	"\x0eImportPosition\x12\x16.ImportPositionRequest\x1a\x0e.GameIDAndTeam\"\x00\x12;\n" +
+	"\x10GetPlayerProfile\x12\x15.PlayerProfileRequest\x1a\x0e.PlayerProfile\"\x00\x125\n" +
+	"\x0eGetLeaderboard\x12\x13.LeaderboardRequest\x1a\f.Leaderboard\"\x00B\x12Z\x10connect4-grpc/pb"
 
 var (
 	file_pb_moves_proto_rawDescOnce sync.Once
@@ -458,38 +4041,186 @@ func file_pb_moves_proto_rawDescGZIP() []byte {
 	return file_pb_moves_proto_rawDescData
 }
 
-var file_pb_moves_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_pb_moves_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_pb_moves_proto_enumTypes = make([]protoimpl.EnumInfo, 10)
+var file_pb_moves_proto_msgTypes = make([]protoimpl.MessageInfo, 44)
 var file_pb_moves_proto_goTypes = []any{
-	(Team)(0),             // 0: team
-	(*Input)(nil),         // 1: Input
-	(*State)(nil),         // 2: State
-	(*Field)(nil),         // 3: Field
-	(*Row)(nil),           // 4: Row
-	(*Empty)(nil),         // 5: Empty
-	(*GameIDAndTeam)(nil), // 6: GameIDAndTeam
-	(*GameID)(nil),        // 7: GameID
+	(Team)(0),                     // 0: team
+	(OfferKind)(0),                // 1: offer_kind
+	(Emote)(0),                    // 2: emote
+	(EndReason)(0),                // 3: end_reason
+	(RejectionReason)(0),          // 4: rejection_reason
+	(Result)(0),                   // 5: result
+	(FirstPlayer)(0),              // 6: first_player
+	(Opponent)(0),                 // 7: opponent
+	(Outcome)(0),                  // 8: outcome
+	(LobbyEventKind)(0),           // 9: lobby_event_kind
+	(*Input)(nil),                 // 10: Input
+	(*Offer)(nil),                 // 11: Offer
+	(*State)(nil),                 // 12: State
+	(*RatingChanges)(nil),         // 13: RatingChanges
+	(*RatingChange)(nil),          // 14: RatingChange
+	(*ChatMessage)(nil),           // 15: ChatMessage
+	(*ChatLine)(nil),              // 16: ChatLine
+	(*Clocks)(nil),                // 17: Clocks
+	(*TimeControl)(nil),           // 18: TimeControl
+	(*Fischer)(nil),               // 19: Fischer
+	(*Players)(nil),               // 20: Players
+	(*Score)(nil),                 // 21: Score
+	(*Rejection)(nil),             // 22: Rejection
+	(*Cell)(nil),                  // 23: Cell
+	(*Variant)(nil),               // 24: Variant
+	(*NewGameRequest)(nil),        // 25: NewGameRequest
+	(*Field)(nil),                 // 26: Field
+	(*Row)(nil),                   // 27: Row
+	(*Empty)(nil),                 // 28: Empty
+	(*GameIDAndTeam)(nil),         // 29: GameIDAndTeam
+	(*GameID)(nil),                // 30: GameID
+	(*JoinGameRequest)(nil),       // 31: JoinGameRequest
+	(*AnalyzeRequest)(nil),        // 32: AnalyzeRequest
+	(*ColumnAnalysis)(nil),        // 33: ColumnAnalysis
+	(*Analysis)(nil),              // 34: Analysis
+	(*GameSummary)(nil),           // 35: GameSummary
+	(*ListGamesRequest)(nil),      // 36: ListGamesRequest
+	(*ListGamesResponse)(nil),     // 37: ListGamesResponse
+	(*LobbyEvent)(nil),            // 38: LobbyEvent
+	(*FindMatchRequest)(nil),      // 39: FindMatchRequest
+	(*MatchUpdate)(nil),           // 40: MatchUpdate
+	(*Credentials)(nil),           // 41: Credentials
+	(*Session)(nil),               // 42: Session
+	(*Move)(nil),                  // 43: Move
+	(*BoardHistory)(nil),          // 44: BoardHistory
+	(*GameHistory)(nil),           // 45: GameHistory
+	(*ReplayRequest)(nil),         // 46: ReplayRequest
+	(*ExportGameRequest)(nil),     // 47: ExportGameRequest
+	(*GameNotation)(nil),          // 48: GameNotation
+	(*ImportPositionRequest)(nil), // 49: ImportPositionRequest
+	(*PlayerProfileRequest)(nil),  // 50: PlayerProfileRequest
+	(*PlayerProfile)(nil),         // 51: PlayerProfile
+	(*LeaderboardRequest)(nil),    // 52: LeaderboardRequest
+	(*Leaderboard)(nil),           // 53: Leaderboard
+	(*timestamppb.Timestamp)(nil), // 54: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),   // 55: google.protobuf.Duration
 }
 var file_pb_moves_proto_depIdxs = []int32{
 	0,  // 0: Input.input_team:type_name -> team
-	3,  // 1: State.field:type_name -> Field
-	0,  // 2: State.turn:type_name -> team
-	4,  // 3: Field.rows:type_name -> Row
-	0,  // 4: Row.values:type_name -> team
-	0,  // 5: GameIDAndTeam.team:type_name -> team
-	1,  // 6: connect4.CommunicateState:input_type -> Input
-	5,  // 7: connect4.NewGame:input_type -> Empty
-	7,  // 8: connect4.JoinGame:input_type -> GameID
-	6,  // 9: connect4.LeaveGame:input_type -> GameIDAndTeam
-	2,  // 10: connect4.CommunicateState:output_type -> State
-	6,  // 11: connect4.NewGame:output_type -> GameIDAndTeam
-	6,  // 12: connect4.JoinGame:output_type -> GameIDAndTeam
-	5,  // 13: connect4.LeaveGame:output_type -> Empty
-	10, // [10:14] is the sub-list for method output_type
-	6,  // [6:10] is the sub-list for method input_type
-	6,  // [6:6] is the sub-list for extension type_name
-	6,  // [6:6] is the sub-list for extension extendee
-	0,  // [0:6] is the sub-list for field type_name
+	28, // 1: Input.resign:type_name -> Empty
+	28, // 2: Input.offer_draw:type_name -> Empty
+	28, // 3: Input.accept:type_name -> Empty
+	28, // 4: Input.decline:type_name -> Empty
+	28, // 5: Input.request_takeback:type_name -> Empty
+	28, // 6: Input.rematch:type_name -> Empty
+	15, // 7: Input.chat:type_name -> ChatMessage
+	1,  // 8: Offer.kind:type_name -> offer_kind
+	0,  // 9: Offer.from:type_name -> team
+	26, // 10: State.field:type_name -> Field
+	0,  // 11: State.turn:type_name -> team
+	24, // 12: State.variant:type_name -> Variant
+	5,  // 13: State.result:type_name -> result
+	23, // 14: State.winning_line:type_name -> Cell
+	22, // 15: State.rejection:type_name -> Rejection
+	21, // 16: State.score:type_name -> Score
+	20, // 17: State.players:type_name -> Players
+	17, // 18: State.clocks:type_name -> Clocks
+	3,  // 19: State.end_reason:type_name -> end_reason
+	11, // 20: State.offer:type_name -> Offer
+	0,  // 21: State.next_first:type_name -> team
+	16, // 22: State.chat:type_name -> ChatLine
+	13, // 23: State.rating_changes:type_name -> RatingChanges
+	54, // 24: State.shutdown_at:type_name -> google.protobuf.Timestamp
+	14, // 25: RatingChanges.red:type_name -> RatingChange
+	14, // 26: RatingChanges.yellow:type_name -> RatingChange
+	2,  // 27: ChatMessage.emote:type_name -> emote
+	0,  // 28: ChatLine.from:type_name -> team
+	2,  // 29: ChatLine.emote:type_name -> emote
+	54, // 30: ChatLine.sent_at:type_name -> google.protobuf.Timestamp
+	55, // 31: Clocks.red:type_name -> google.protobuf.Duration
+	55, // 32: Clocks.yellow:type_name -> google.protobuf.Duration
+	19, // 33: TimeControl.fischer:type_name -> Fischer
+	55, // 34: TimeControl.per_move:type_name -> google.protobuf.Duration
+	55, // 35: Fischer.initial:type_name -> google.protobuf.Duration
+	55, // 36: Fischer.increment:type_name -> google.protobuf.Duration
+	0,  // 37: Score.match_winner:type_name -> team
+	4,  // 38: Rejection.reason:type_name -> rejection_reason
+	24, // 39: NewGameRequest.variant:type_name -> Variant
+	7,  // 40: NewGameRequest.opponent:type_name -> opponent
+	18, // 41: NewGameRequest.time_control:type_name -> TimeControl
+	6,  // 42: NewGameRequest.first_player:type_name -> first_player
+	27, // 43: Field.rows:type_name -> Row
+	0,  // 44: Row.values:type_name -> team
+	0,  // 45: GameIDAndTeam.team:type_name -> team
+	24, // 46: GameIDAndTeam.variant:type_name -> Variant
+	26, // 47: AnalyzeRequest.field:type_name -> Field
+	8,  // 48: ColumnAnalysis.outcome:type_name -> outcome
+	33, // 49: Analysis.columns:type_name -> ColumnAnalysis
+	0,  // 50: Analysis.turn:type_name -> team
+	24, // 51: GameSummary.variant:type_name -> Variant
+	54, // 52: GameSummary.created_at:type_name -> google.protobuf.Timestamp
+	18, // 53: GameSummary.time_control:type_name -> TimeControl
+	24, // 54: ListGamesRequest.variant:type_name -> Variant
+	18, // 55: ListGamesRequest.time_control:type_name -> TimeControl
+	35, // 56: ListGamesResponse.games:type_name -> GameSummary
+	9,  // 57: LobbyEvent.kind:type_name -> lobby_event_kind
+	35, // 58: LobbyEvent.game:type_name -> GameSummary
+	24, // 59: FindMatchRequest.variant:type_name -> Variant
+	18, // 60: FindMatchRequest.time_control:type_name -> TimeControl
+	29, // 61: MatchUpdate.match:type_name -> GameIDAndTeam
+	54, // 62: Session.expires_at:type_name -> google.protobuf.Timestamp
+	0,  // 63: Move.team:type_name -> team
+	54, // 64: Move.played_at:type_name -> google.protobuf.Timestamp
+	43, // 65: BoardHistory.moves:type_name -> Move
+	5,  // 66: BoardHistory.result:type_name -> result
+	3,  // 67: BoardHistory.end_reason:type_name -> end_reason
+	0,  // 68: BoardHistory.first:type_name -> team
+	24, // 69: GameHistory.variant:type_name -> Variant
+	20, // 70: GameHistory.players:type_name -> Players
+	21, // 71: GameHistory.score:type_name -> Score
+	44, // 72: GameHistory.boards:type_name -> BoardHistory
+	54, // 73: GameHistory.created_at:type_name -> google.protobuf.Timestamp
+	54, // 74: GameHistory.finished_at:type_name -> google.protobuf.Timestamp
+	25, // 75: ImportPositionRequest.game:type_name -> NewGameRequest
+	54, // 76: PlayerProfile.created_at:type_name -> google.protobuf.Timestamp
+	51, // 77: Leaderboard.players:type_name -> PlayerProfile
+	41, // 78: connect4.Register:input_type -> Credentials
+	41, // 79: connect4.Login:input_type -> Credentials
+	28, // 80: connect4.Guest:input_type -> Empty
+	10, // 81: connect4.CommunicateState:input_type -> Input
+	25, // 82: connect4.NewGame:input_type -> NewGameRequest
+	31, // 83: connect4.JoinGame:input_type -> JoinGameRequest
+	29, // 84: connect4.LeaveGame:input_type -> GameIDAndTeam
+	32, // 85: connect4.Analyze:input_type -> AnalyzeRequest
+	30, // 86: connect4.Spectate:input_type -> GameID
+	36, // 87: connect4.ListGames:input_type -> ListGamesRequest
+	36, // 88: connect4.WatchLobby:input_type -> ListGamesRequest
+	39, // 89: connect4.FindMatch:input_type -> FindMatchRequest
+	30, // 90: connect4.GetGameHistory:input_type -> GameID
+	46, // 91: connect4.Replay:input_type -> ReplayRequest
+	47, // 92: connect4.ExportGame:input_type -> ExportGameRequest
+	49, // 93: connect4.ImportPosition:input_type -> ImportPositionRequest
+	50, // 94: connect4.GetPlayerProfile:input_type -> PlayerProfileRequest
+	52, // 95: connect4.GetLeaderboard:input_type -> LeaderboardRequest
+	42, // 96: connect4.Register:output_type -> Session
+	42, // 97: connect4.Login:output_type -> Session
+	42, // 98: connect4.Guest:output_type -> Session
+	12, // 99: connect4.CommunicateState:output_type -> State
+	29, // 100: connect4.NewGame:output_type -> GameIDAndTeam
+	29, // 101: connect4.JoinGame:output_type -> GameIDAndTeam
+	28, // 102: connect4.LeaveGame:output_type -> Empty
+	34, // 103: connect4.Analyze:output_type -> Analysis
+	12, // 104: connect4.Spectate:output_type -> State
+	37, // 105: connect4.ListGames:output_type -> ListGamesResponse
+	38, // 106: connect4.WatchLobby:output_type -> LobbyEvent
+	40, // 107: connect4.FindMatch:output_type -> MatchUpdate
+	45, // 108: connect4.GetGameHistory:output_type -> GameHistory
+	12, // 109: connect4.Replay:output_type -> State
+	48, // 110: connect4.ExportGame:output_type -> GameNotation
+	29, // 111: connect4.ImportPosition:output_type -> GameIDAndTeam
+	51, // 112: connect4.GetPlayerProfile:output_type -> PlayerProfile
+	53, // 113: connect4.GetLeaderboard:output_type -> Leaderboard
+	96, // [96:114] is the sub-list for method output_type
+	78, // [78:96] is the sub-list for method input_type
+	78, // [78:78] is the sub-list for extension type_name
+	78, // [78:78] is the sub-list for extension extendee
+	0,  // [0:78] is the sub-list for field type_name
 }
 
 func init() { file_pb_moves_proto_init() }
@@ -497,13 +4228,29 @@ func file_pb_moves_proto_init() {
 	if File_pb_moves_proto != nil {
 		return
 	}
+	file_pb_moves_proto_msgTypes[0].OneofWrappers = []any{
+		(*Input_Column)(nil),
+		(*Input_Resign)(nil),
+		(*Input_OfferDraw)(nil),
+		(*Input_Accept)(nil),
+		(*Input_Decline)(nil),
+		(*Input_RequestTakeback)(nil),
+		(*Input_Rematch)(nil),
+		(*Input_Chat)(nil),
+		(*Input_MuteChat)(nil),
+	}
+	file_pb_moves_proto_msgTypes[8].OneofWrappers = []any{
+		(*TimeControl_Fischer)(nil),
+		(*TimeControl_PerMove)(nil),
+		(*TimeControl_CorrespondenceDays)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pb_moves_proto_rawDesc), len(file_pb_moves_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   7,
+			NumEnums:      10,
+			NumMessages:   44,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
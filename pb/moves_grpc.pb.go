@@ -19,20 +19,62 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
+	Connect4_Register_FullMethodName         = "/connect4/Register"
+	Connect4_Login_FullMethodName            = "/connect4/Login"
+	Connect4_Guest_FullMethodName            = "/connect4/Guest"
 	Connect4_CommunicateState_FullMethodName = "/connect4/CommunicateState"
 	Connect4_NewGame_FullMethodName          = "/connect4/NewGame"
 	Connect4_JoinGame_FullMethodName         = "/connect4/JoinGame"
 	Connect4_LeaveGame_FullMethodName        = "/connect4/LeaveGame"
+	Connect4_Analyze_FullMethodName          = "/connect4/Analyze"
+	Connect4_Spectate_FullMethodName         = "/connect4/Spectate"
+	Connect4_ListGames_FullMethodName        = "/connect4/ListGames"
+	Connect4_WatchLobby_FullMethodName       = "/connect4/WatchLobby"
+	Connect4_FindMatch_FullMethodName        = "/connect4/FindMatch"
+	Connect4_GetGameHistory_FullMethodName   = "/connect4/GetGameHistory"
+	Connect4_Replay_FullMethodName           = "/connect4/Replay"
+	Connect4_ExportGame_FullMethodName       = "/connect4/ExportGame"
+	Connect4_ImportPosition_FullMethodName   = "/connect4/ImportPosition"
+	Connect4_GetPlayerProfile_FullMethodName = "/connect4/GetPlayerProfile"
+	Connect4_GetLeaderboard_FullMethodName   = "/connect4/GetLeaderboard"
 )
 
 // Connect4Client is the client API for Connect4 service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Calls acting for a player need the token of a Session in the "authorization" metadata, as
+// "Bearer <token>". Register, Login, Guest and the read only calls work without one.
 type Connect4Client interface {
+	Register(ctx context.Context, in *Credentials, opts ...grpc.CallOption) (*Session, error)
+	Login(ctx context.Context, in *Credentials, opts ...grpc.CallOption) (*Session, error)
+	// Session of a throwaway account, nothing is stored on the server.
+	Guest(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Session, error)
 	CommunicateState(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[Input, State], error)
-	NewGame(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*GameIDAndTeam, error)
-	JoinGame(ctx context.Context, in *GameID, opts ...grpc.CallOption) (*GameIDAndTeam, error)
+	NewGame(ctx context.Context, in *NewGameRequest, opts ...grpc.CallOption) (*GameIDAndTeam, error)
+	JoinGame(ctx context.Context, in *JoinGameRequest, opts ...grpc.CallOption) (*GameIDAndTeam, error)
 	LeaveGame(ctx context.Context, in *GameIDAndTeam, opts ...grpc.CallOption) (*Empty, error)
+	// Searches the position for the best columns, refused with RESOURCE_EXHAUSTED while the server
+	// runs as many analyses as it has CPUs.
+	Analyze(ctx context.Context, in *AnalyzeRequest, opts ...grpc.CallOption) (*Analysis, error)
+	Spectate(ctx context.Context, in *GameID, opts ...grpc.CallOption) (grpc.ServerStreamingClient[State], error)
+	ListGames(ctx context.Context, in *ListGamesRequest, opts ...grpc.CallOption) (*ListGamesResponse, error)
+	// Sends the games matching the filter as added events, then their changes as they happen.
+	WatchLobby(ctx context.Context, in *ListGamesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LobbyEvent], error)
+	// Queues the player until a compatible opponent is found, then sends the game and seat assigned.
+	FindMatch(ctx context.Context, in *FindMatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[MatchUpdate], error)
+	// Every move of a game, live or finished.
+	GetGameHistory(ctx context.Context, in *GameID, opts ...grpc.CallOption) (*GameHistory, error)
+	// Plays the moves of a game back as the states they led to, starting from the empty board.
+	Replay(ctx context.Context, in *ReplayRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[State], error)
+	// One board of a game in the text notation of the notation package.
+	ExportGame(ctx context.Context, in *ExportGameRequest, opts ...grpc.CallOption) (*GameNotation, error)
+	// Starts a new game from the position reached by the moves of a game in the text notation.
+	ImportPosition(ctx context.Context, in *ImportPositionRequest, opts ...grpc.CallOption) (*GameIDAndTeam, error)
+	// Rating and results of an account, the caller's when the username is empty.
+	GetPlayerProfile(ctx context.Context, in *PlayerProfileRequest, opts ...grpc.CallOption) (*PlayerProfile, error)
+	// Players who finished a rated game, best rated first.
+	GetLeaderboard(ctx context.Context, in *LeaderboardRequest, opts ...grpc.CallOption) (*Leaderboard, error)
 }
 
 type connect4Client struct {
@@ -43,6 +85,36 @@ func NewConnect4Client(cc grpc.ClientConnInterface) Connect4Client {
 	return &connect4Client{cc}
 }
 
+func (c *connect4Client) Register(ctx context.Context, in *Credentials, opts ...grpc.CallOption) (*Session, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Session)
+	err := c.cc.Invoke(ctx, Connect4_Register_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *connect4Client) Login(ctx context.Context, in *Credentials, opts ...grpc.CallOption) (*Session, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Session)
+	err := c.cc.Invoke(ctx, Connect4_Login_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *connect4Client) Guest(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Session, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Session)
+	err := c.cc.Invoke(ctx, Connect4_Guest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *connect4Client) CommunicateState(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[Input, State], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	stream, err := c.cc.NewStream(ctx, &Connect4_ServiceDesc.Streams[0], Connect4_CommunicateState_FullMethodName, cOpts...)
@@ -56,7 +128,7 @@ func (c *connect4Client) CommunicateState(ctx context.Context, opts ...grpc.Call
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Connect4_CommunicateStateClient = grpc.BidiStreamingClient[Input, State]
 
-func (c *connect4Client) NewGame(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*GameIDAndTeam, error) {
+func (c *connect4Client) NewGame(ctx context.Context, in *NewGameRequest, opts ...grpc.CallOption) (*GameIDAndTeam, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GameIDAndTeam)
 	err := c.cc.Invoke(ctx, Connect4_NewGame_FullMethodName, in, out, cOpts...)
@@ -66,7 +138,7 @@ func (c *connect4Client) NewGame(ctx context.Context, in *Empty, opts ...grpc.Ca
 	return out, nil
 }
 
-func (c *connect4Client) JoinGame(ctx context.Context, in *GameID, opts ...grpc.CallOption) (*GameIDAndTeam, error) {
+func (c *connect4Client) JoinGame(ctx context.Context, in *JoinGameRequest, opts ...grpc.CallOption) (*GameIDAndTeam, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GameIDAndTeam)
 	err := c.cc.Invoke(ctx, Connect4_JoinGame_FullMethodName, in, out, cOpts...)
@@ -86,14 +158,188 @@ func (c *connect4Client) LeaveGame(ctx context.Context, in *GameIDAndTeam, opts
 	return out, nil
 }
 
+func (c *connect4Client) Analyze(ctx context.Context, in *AnalyzeRequest, opts ...grpc.CallOption) (*Analysis, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Analysis)
+	err := c.cc.Invoke(ctx, Connect4_Analyze_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *connect4Client) Spectate(ctx context.Context, in *GameID, opts ...grpc.CallOption) (grpc.ServerStreamingClient[State], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Connect4_ServiceDesc.Streams[1], Connect4_Spectate_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GameID, State]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Connect4_SpectateClient = grpc.ServerStreamingClient[State]
+
+func (c *connect4Client) ListGames(ctx context.Context, in *ListGamesRequest, opts ...grpc.CallOption) (*ListGamesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListGamesResponse)
+	err := c.cc.Invoke(ctx, Connect4_ListGames_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *connect4Client) WatchLobby(ctx context.Context, in *ListGamesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LobbyEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Connect4_ServiceDesc.Streams[2], Connect4_WatchLobby_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListGamesRequest, LobbyEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Connect4_WatchLobbyClient = grpc.ServerStreamingClient[LobbyEvent]
+
+func (c *connect4Client) FindMatch(ctx context.Context, in *FindMatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[MatchUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Connect4_ServiceDesc.Streams[3], Connect4_FindMatch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[FindMatchRequest, MatchUpdate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Connect4_FindMatchClient = grpc.ServerStreamingClient[MatchUpdate]
+
+func (c *connect4Client) GetGameHistory(ctx context.Context, in *GameID, opts ...grpc.CallOption) (*GameHistory, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GameHistory)
+	err := c.cc.Invoke(ctx, Connect4_GetGameHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *connect4Client) Replay(ctx context.Context, in *ReplayRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[State], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Connect4_ServiceDesc.Streams[4], Connect4_Replay_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ReplayRequest, State]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Connect4_ReplayClient = grpc.ServerStreamingClient[State]
+
+func (c *connect4Client) ExportGame(ctx context.Context, in *ExportGameRequest, opts ...grpc.CallOption) (*GameNotation, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GameNotation)
+	err := c.cc.Invoke(ctx, Connect4_ExportGame_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *connect4Client) ImportPosition(ctx context.Context, in *ImportPositionRequest, opts ...grpc.CallOption) (*GameIDAndTeam, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GameIDAndTeam)
+	err := c.cc.Invoke(ctx, Connect4_ImportPosition_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *connect4Client) GetPlayerProfile(ctx context.Context, in *PlayerProfileRequest, opts ...grpc.CallOption) (*PlayerProfile, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PlayerProfile)
+	err := c.cc.Invoke(ctx, Connect4_GetPlayerProfile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *connect4Client) GetLeaderboard(ctx context.Context, in *LeaderboardRequest, opts ...grpc.CallOption) (*Leaderboard, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Leaderboard)
+	err := c.cc.Invoke(ctx, Connect4_GetLeaderboard_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Connect4Server is the server API for Connect4 service.
 // All implementations must embed UnimplementedConnect4Server
 // for forward compatibility.
+//
+// Calls acting for a player need the token of a Session in the "authorization" metadata, as
+// "Bearer <token>". Register, Login, Guest and the read only calls work without one.
 type Connect4Server interface {
+	Register(context.Context, *Credentials) (*Session, error)
+	Login(context.Context, *Credentials) (*Session, error)
+	// Session of a throwaway account, nothing is stored on the server.
+	Guest(context.Context, *Empty) (*Session, error)
 	CommunicateState(grpc.BidiStreamingServer[Input, State]) error
-	NewGame(context.Context, *Empty) (*GameIDAndTeam, error)
-	JoinGame(context.Context, *GameID) (*GameIDAndTeam, error)
+	NewGame(context.Context, *NewGameRequest) (*GameIDAndTeam, error)
+	JoinGame(context.Context, *JoinGameRequest) (*GameIDAndTeam, error)
 	LeaveGame(context.Context, *GameIDAndTeam) (*Empty, error)
+	// Searches the position for the best columns, refused with RESOURCE_EXHAUSTED while the server
+	// runs as many analyses as it has CPUs.
+	Analyze(context.Context, *AnalyzeRequest) (*Analysis, error)
+	Spectate(*GameID, grpc.ServerStreamingServer[State]) error
+	ListGames(context.Context, *ListGamesRequest) (*ListGamesResponse, error)
+	// Sends the games matching the filter as added events, then their changes as they happen.
+	WatchLobby(*ListGamesRequest, grpc.ServerStreamingServer[LobbyEvent]) error
+	// Queues the player until a compatible opponent is found, then sends the game and seat assigned.
+	FindMatch(*FindMatchRequest, grpc.ServerStreamingServer[MatchUpdate]) error
+	// Every move of a game, live or finished.
+	GetGameHistory(context.Context, *GameID) (*GameHistory, error)
+	// Plays the moves of a game back as the states they led to, starting from the empty board.
+	Replay(*ReplayRequest, grpc.ServerStreamingServer[State]) error
+	// One board of a game in the text notation of the notation package.
+	ExportGame(context.Context, *ExportGameRequest) (*GameNotation, error)
+	// Starts a new game from the position reached by the moves of a game in the text notation.
+	ImportPosition(context.Context, *ImportPositionRequest) (*GameIDAndTeam, error)
+	// Rating and results of an account, the caller's when the username is empty.
+	GetPlayerProfile(context.Context, *PlayerProfileRequest) (*PlayerProfile, error)
+	// Players who finished a rated game, best rated first.
+	GetLeaderboard(context.Context, *LeaderboardRequest) (*Leaderboard, error)
 	mustEmbedUnimplementedConnect4Server()
 }
 
@@ -104,18 +350,60 @@ type Connect4Server interface {
 // pointer dereference when methods are called.
 type UnimplementedConnect4Server struct{}
 
+func (UnimplementedConnect4Server) Register(context.Context, *Credentials) (*Session, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Register not implemented")
+}
+func (UnimplementedConnect4Server) Login(context.Context, *Credentials) (*Session, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
+}
+func (UnimplementedConnect4Server) Guest(context.Context, *Empty) (*Session, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Guest not implemented")
+}
 func (UnimplementedConnect4Server) CommunicateState(grpc.BidiStreamingServer[Input, State]) error {
 	return status.Errorf(codes.Unimplemented, "method CommunicateState not implemented")
 }
-func (UnimplementedConnect4Server) NewGame(context.Context, *Empty) (*GameIDAndTeam, error) {
+func (UnimplementedConnect4Server) NewGame(context.Context, *NewGameRequest) (*GameIDAndTeam, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method NewGame not implemented")
 }
-func (UnimplementedConnect4Server) JoinGame(context.Context, *GameID) (*GameIDAndTeam, error) {
+func (UnimplementedConnect4Server) JoinGame(context.Context, *JoinGameRequest) (*GameIDAndTeam, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method JoinGame not implemented")
 }
 func (UnimplementedConnect4Server) LeaveGame(context.Context, *GameIDAndTeam) (*Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method LeaveGame not implemented")
 }
+func (UnimplementedConnect4Server) Analyze(context.Context, *AnalyzeRequest) (*Analysis, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Analyze not implemented")
+}
+func (UnimplementedConnect4Server) Spectate(*GameID, grpc.ServerStreamingServer[State]) error {
+	return status.Errorf(codes.Unimplemented, "method Spectate not implemented")
+}
+func (UnimplementedConnect4Server) ListGames(context.Context, *ListGamesRequest) (*ListGamesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGames not implemented")
+}
+func (UnimplementedConnect4Server) WatchLobby(*ListGamesRequest, grpc.ServerStreamingServer[LobbyEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchLobby not implemented")
+}
+func (UnimplementedConnect4Server) FindMatch(*FindMatchRequest, grpc.ServerStreamingServer[MatchUpdate]) error {
+	return status.Errorf(codes.Unimplemented, "method FindMatch not implemented")
+}
+func (UnimplementedConnect4Server) GetGameHistory(context.Context, *GameID) (*GameHistory, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGameHistory not implemented")
+}
+func (UnimplementedConnect4Server) Replay(*ReplayRequest, grpc.ServerStreamingServer[State]) error {
+	return status.Errorf(codes.Unimplemented, "method Replay not implemented")
+}
+func (UnimplementedConnect4Server) ExportGame(context.Context, *ExportGameRequest) (*GameNotation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportGame not implemented")
+}
+func (UnimplementedConnect4Server) ImportPosition(context.Context, *ImportPositionRequest) (*GameIDAndTeam, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportPosition not implemented")
+}
+func (UnimplementedConnect4Server) GetPlayerProfile(context.Context, *PlayerProfileRequest) (*PlayerProfile, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPlayerProfile not implemented")
+}
+func (UnimplementedConnect4Server) GetLeaderboard(context.Context, *LeaderboardRequest) (*Leaderboard, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLeaderboard not implemented")
+}
 func (UnimplementedConnect4Server) mustEmbedUnimplementedConnect4Server() {}
 func (UnimplementedConnect4Server) testEmbeddedByValue()                  {}
 
@@ -137,6 +425,60 @@ func RegisterConnect4Server(s grpc.ServiceRegistrar, srv Connect4Server) {
 	s.RegisterService(&Connect4_ServiceDesc, srv)
 }
 
+func _Connect4_Register_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Credentials)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Connect4Server).Register(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Connect4_Register_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Connect4Server).Register(ctx, req.(*Credentials))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Connect4_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Credentials)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Connect4Server).Login(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Connect4_Login_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Connect4Server).Login(ctx, req.(*Credentials))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Connect4_Guest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Connect4Server).Guest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Connect4_Guest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Connect4Server).Guest(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Connect4_CommunicateState_Handler(srv interface{}, stream grpc.ServerStream) error {
 	return srv.(Connect4Server).CommunicateState(&grpc.GenericServerStream[Input, State]{ServerStream: stream})
 }
@@ -145,7 +487,7 @@ func _Connect4_CommunicateState_Handler(srv interface{}, stream grpc.ServerStrea
 type Connect4_CommunicateStateServer = grpc.BidiStreamingServer[Input, State]
 
 func _Connect4_NewGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+	in := new(NewGameRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -157,13 +499,13 @@ func _Connect4_NewGame_Handler(srv interface{}, ctx context.Context, dec func(in
 		FullMethod: Connect4_NewGame_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(Connect4Server).NewGame(ctx, req.(*Empty))
+		return srv.(Connect4Server).NewGame(ctx, req.(*NewGameRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
 func _Connect4_JoinGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GameID)
+	in := new(JoinGameRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -175,7 +517,7 @@ func _Connect4_JoinGame_Handler(srv interface{}, ctx context.Context, dec func(i
 		FullMethod: Connect4_JoinGame_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(Connect4Server).JoinGame(ctx, req.(*GameID))
+		return srv.(Connect4Server).JoinGame(ctx, req.(*JoinGameRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -198,6 +540,176 @@ func _Connect4_LeaveGame_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Connect4_Analyze_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnalyzeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Connect4Server).Analyze(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Connect4_Analyze_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Connect4Server).Analyze(ctx, req.(*AnalyzeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Connect4_Spectate_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GameID)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(Connect4Server).Spectate(m, &grpc.GenericServerStream[GameID, State]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Connect4_SpectateServer = grpc.ServerStreamingServer[State]
+
+func _Connect4_ListGames_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGamesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Connect4Server).ListGames(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Connect4_ListGames_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Connect4Server).ListGames(ctx, req.(*ListGamesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Connect4_WatchLobby_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListGamesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(Connect4Server).WatchLobby(m, &grpc.GenericServerStream[ListGamesRequest, LobbyEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Connect4_WatchLobbyServer = grpc.ServerStreamingServer[LobbyEvent]
+
+func _Connect4_FindMatch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FindMatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(Connect4Server).FindMatch(m, &grpc.GenericServerStream[FindMatchRequest, MatchUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Connect4_FindMatchServer = grpc.ServerStreamingServer[MatchUpdate]
+
+func _Connect4_GetGameHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GameID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Connect4Server).GetGameHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Connect4_GetGameHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Connect4Server).GetGameHistory(ctx, req.(*GameID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Connect4_Replay_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReplayRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(Connect4Server).Replay(m, &grpc.GenericServerStream[ReplayRequest, State]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Connect4_ReplayServer = grpc.ServerStreamingServer[State]
+
+func _Connect4_ExportGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportGameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Connect4Server).ExportGame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Connect4_ExportGame_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Connect4Server).ExportGame(ctx, req.(*ExportGameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Connect4_ImportPosition_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportPositionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Connect4Server).ImportPosition(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Connect4_ImportPosition_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Connect4Server).ImportPosition(ctx, req.(*ImportPositionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Connect4_GetPlayerProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayerProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Connect4Server).GetPlayerProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Connect4_GetPlayerProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Connect4Server).GetPlayerProfile(ctx, req.(*PlayerProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Connect4_GetLeaderboard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaderboardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Connect4Server).GetLeaderboard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Connect4_GetLeaderboard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Connect4Server).GetLeaderboard(ctx, req.(*LeaderboardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Connect4_ServiceDesc is the grpc.ServiceDesc for Connect4 service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -205,6 +717,18 @@ var Connect4_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "connect4",
 	HandlerType: (*Connect4Server)(nil),
 	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Register",
+			Handler:    _Connect4_Register_Handler,
+		},
+		{
+			MethodName: "Login",
+			Handler:    _Connect4_Login_Handler,
+		},
+		{
+			MethodName: "Guest",
+			Handler:    _Connect4_Guest_Handler,
+		},
 		{
 			MethodName: "NewGame",
 			Handler:    _Connect4_NewGame_Handler,
@@ -217,6 +741,34 @@ var Connect4_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "LeaveGame",
 			Handler:    _Connect4_LeaveGame_Handler,
 		},
+		{
+			MethodName: "Analyze",
+			Handler:    _Connect4_Analyze_Handler,
+		},
+		{
+			MethodName: "ListGames",
+			Handler:    _Connect4_ListGames_Handler,
+		},
+		{
+			MethodName: "GetGameHistory",
+			Handler:    _Connect4_GetGameHistory_Handler,
+		},
+		{
+			MethodName: "ExportGame",
+			Handler:    _Connect4_ExportGame_Handler,
+		},
+		{
+			MethodName: "ImportPosition",
+			Handler:    _Connect4_ImportPosition_Handler,
+		},
+		{
+			MethodName: "GetPlayerProfile",
+			Handler:    _Connect4_GetPlayerProfile_Handler,
+		},
+		{
+			MethodName: "GetLeaderboard",
+			Handler:    _Connect4_GetLeaderboard_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -225,6 +777,26 @@ var Connect4_ServiceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "Spectate",
+			Handler:       _Connect4_Spectate_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchLobby",
+			Handler:       _Connect4_WatchLobby_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "FindMatch",
+			Handler:       _Connect4_FindMatch_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Replay",
+			Handler:       _Connect4_Replay_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "pb/moves.proto",
 }
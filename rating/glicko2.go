@@ -0,0 +1,104 @@
+// Package rating implements the Glicko-2 rating system of Mark Glickman, as described in
+// "Example of the Glicko-2 system" (http://www.glicko.net/glicko/glicko2.pdf), every game being a
+// rating period of its own.
+package rating
+
+import "math"
+
+const (
+	Initial           = 1500
+	InitialDeviation  = 350
+	InitialVolatility = 0.06
+
+	// scale converts between the Glicko and Glicko-2 scales.
+	scale = 173.7178
+	// tau constrains the change of volatility over time.
+	tau = 0.5
+	// epsilon is the convergence tolerance of the volatility iteration.
+	epsilon = 1e-6
+)
+
+// Rating is the strength of a player, Deviation being how uncertain it is and Volatility how
+// erratic their results are.
+type Rating struct {
+	Rating     float64 `json:"rating"`
+	Deviation  float64 `json:"deviation"`
+	Volatility float64 `json:"volatility"`
+}
+
+// Default is the rating of players who haven't played yet.
+func Default() Rating {
+	return Rating{Rating: Initial, Deviation: InitialDeviation, Volatility: InitialVolatility}
+}
+
+// Score is the outcome of a game for the rated player.
+type Score float64
+
+const (
+	Loss Score = 0
+	Draw Score = 0.5
+	Win  Score = 1
+)
+
+// Result is a game played in the rating period.
+type Result struct {
+	Opponent Rating
+	Score    Score
+}
+
+// Update returns r after the games of a rating period.
+func (r Rating) Update(results ...Result) Rating {
+	mu, phi := (r.Rating-Initial)/scale, r.Deviation/scale
+	if len(results) == 0 {
+		// only the uncertainty grows
+		return Rating{Rating: r.Rating, Deviation: math.Sqrt(phi*phi+r.Volatility*r.Volatility) * scale, Volatility: r.Volatility}
+	}
+	var invV, sum float64
+	for _, res := range results {
+		muJ, phiJ := (res.Opponent.Rating-Initial)/scale, res.Opponent.Deviation/scale
+		g := 1 / math.Sqrt(1+3*phiJ*phiJ/(math.Pi*math.Pi))
+		e := 1 / (1 + math.Exp(-g*(mu-muJ)))
+		invV += g * g * e * (1 - e)
+		sum += g * (float64(res.Score) - e)
+	}
+	v := 1 / invV
+	delta := v * sum
+	sigma := volatility(phi, v, delta, r.Volatility)
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	phi = 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	mu += phi * phi * sum
+	return Rating{Rating: mu*scale + Initial, Deviation: phi * scale, Volatility: sigma}
+}
+
+// volatility solves for the new volatility with the Illinois algorithm, step 5 of the paper.
+func volatility(phi, v, delta, sigma float64) float64 {
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2*d*d) - (x-a)/(tau*tau)
+	}
+	lo := a
+	var hi float64
+	if delta*delta > phi*phi+v {
+		hi = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.
+		for f(a-k*tau) < 0 {
+			k++
+		}
+		hi = a - k*tau
+	}
+	fLo, fHi := f(lo), f(hi)
+	for math.Abs(hi-lo) > epsilon {
+		c := lo + (lo-hi)*fLo/(fHi-fLo)
+		fC := f(c)
+		if fC*fHi <= 0 {
+			lo, fLo = hi, fHi
+		} else {
+			fLo /= 2
+		}
+		hi, fHi = c, fC
+	}
+	return math.Exp(lo / 2)
+}
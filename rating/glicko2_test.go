@@ -0,0 +1,40 @@
+package rating
+
+import (
+	"math"
+	"testing"
+)
+
+func near(a, b, tolerance float64) bool {
+	return math.Abs(a-b) <= tolerance
+}
+
+// TestPaperExample checks the worked example of the Glicko-2 paper.
+func TestPaperExample(t *testing.T) {
+	player := Rating{Rating: 1500, Deviation: 200, Volatility: 0.06}
+	got := player.Update(
+		Result{Opponent: Rating{Rating: 1400, Deviation: 30, Volatility: 0.06}, Score: Win},
+		Result{Opponent: Rating{Rating: 1550, Deviation: 100, Volatility: 0.06}, Score: Loss},
+		Result{Opponent: Rating{Rating: 1700, Deviation: 300, Volatility: 0.06}, Score: Loss},
+	)
+	if !near(got.Rating, 1464.06, 0.01) || !near(got.Deviation, 151.52, 0.01) || !near(got.Volatility, 0.05999, 0.00001) {
+		t.Fatalf("expected 1464.06, 151.52, 0.05999, got %+v", got)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	a, b := Default(), Default()
+	winner, loser := a.Update(Result{Opponent: b, Score: Win}), b.Update(Result{Opponent: a, Score: Loss})
+	if winner.Rating <= Initial || loser.Rating >= Initial || !near(winner.Rating-Initial, Initial-loser.Rating, 1e-9) {
+		t.Fatalf("expected symmetric changes, got %+v and %+v", winner, loser)
+	}
+	if winner.Deviation >= InitialDeviation {
+		t.Fatalf("expected a game to lower the deviation, got %v", winner.Deviation)
+	}
+	if drawn := a.Update(Result{Opponent: b, Score: Draw}); !near(drawn.Rating, Initial, 1e-9) {
+		t.Fatalf("expected a draw between equals to keep the rating, got %v", drawn.Rating)
+	}
+	if idle := winner.Update(); idle.Rating != winner.Rating || idle.Deviation <= winner.Deviation {
+		t.Fatalf("expected only the deviation to grow without games, got %+v", idle)
+	}
+}
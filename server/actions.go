@@ -0,0 +1,156 @@
+package server
+
+import (
+	"errors"
+	"time"
+
+	"github.com/geofpwhite/connect4-grpc/engine"
+	"github.com/geofpwhite/connect4-grpc/pb"
+)
+
+const (
+	// endResign ends a board given up by the mover of the record.
+	endResign = "resign"
+	// endDraw ends a board drawn by agreement, the mover of the record accepting the offer.
+	endDraw = "draw"
+)
+
+var (
+	errBoardOver     = errors.New("board is over")
+	errNoOffer       = errors.New("no offer from the opponent to answer")
+	errOfferPending  = errors.New("an offer already waits for an answer")
+	errNoTakeback    = errors.New("no move of yours to take back")
+	errUnknownAction = errors.New("input has no action")
+)
+
+// offer is a draw or takeback proposal waiting for the opponent.
+type offer struct {
+	kind pb.OfferKind
+	from pb.Team
+}
+
+// act carries out the action of input for team. Runs on the actor of g, without g.mut held.
+func (g *game) act(input *pb.Input, team pb.Team) error {
+	switch input.GetAction().(type) {
+	case *pb.Input_Column:
+		return g.modifyState(input.GetColumn(), team)
+	case *pb.Input_Resign:
+		return g.resign(team)
+	case *pb.Input_OfferDraw:
+		return g.propose(pb.OfferKind_draw_offer, team)
+	case *pb.Input_RequestTakeback:
+		return g.propose(pb.OfferKind_takeback, team)
+	case *pb.Input_Rematch:
+		return g.propose(pb.OfferKind_rematch, team)
+	case *pb.Input_Accept:
+		return g.answer(team, true)
+	case *pb.Input_Decline:
+		return g.answer(team, false)
+	}
+	return errUnknownAction
+}
+
+func (g *game) resign(team pb.Team) error {
+	g.mut.Lock()
+	defer g.mut.Unlock()
+	if g.matchWinner() != pb.Team_empty {
+		return errMatchOver
+	}
+	if g.over() {
+		return errBoardOver
+	}
+	return g.apply(MoveRecord{Mover: engine.Piece(team), At: time.Now(), End: endResign})
+}
+
+// propose makes an offer to the opponent, a draw or rematch offer answering the same offer of the
+// opponent being an agreement. The bot accepts takebacks and rematches and declines draws right
+// away.
+func (g *game) propose(kind pb.OfferKind, team pb.Team) error {
+	g.mut.Lock()
+	defer g.mut.Unlock()
+	if g.matchWinner() != pb.Team_empty {
+		return errMatchOver
+	}
+	switch {
+	case kind == pb.OfferKind_rematch && !g.over():
+		return errBoardInPlay
+	case kind == pb.OfferKind_rematch && !g.shutdownAt.IsZero():
+		return errRematchShutdown
+	case kind != pb.OfferKind_rematch && g.over():
+		return errBoardOver
+	}
+	if p := g.pending; p != nil {
+		if p.from != team && p.kind == kind && kind != pb.OfferKind_takeback {
+			return g.accept(team)
+		}
+		return errOfferPending
+	}
+	if kind == pb.OfferKind_takeback && g.takebackPlies(team) == 0 {
+		return errNoTakeback
+	}
+	g.pending = &offer{kind: kind, from: team}
+	if g.bot != nil && g.bot.team != team {
+		if kind != pb.OfferKind_draw_offer {
+			return g.accept(g.bot.team)
+		}
+		g.pending = nil
+	}
+	return nil
+}
+
+func (g *game) answer(team pb.Team, accepted bool) error {
+	g.mut.Lock()
+	defer g.mut.Unlock()
+	if g.pending == nil || g.pending.from == team {
+		return errNoOffer
+	}
+	if !accepted {
+		g.pending = nil
+		return nil
+	}
+	return g.accept(team)
+}
+
+// accept carries out the pending offer, team being the one accepting it. Called with g.mut held.
+func (g *game) accept(team pb.Team) error {
+	p := g.pending
+	g.pending = nil
+	now := time.Now()
+	switch p.kind {
+	case pb.OfferKind_draw_offer:
+		return g.apply(MoveRecord{Mover: engine.Piece(team), At: now, End: endDraw})
+	case pb.OfferKind_rematch:
+		g.startBoard(now)
+		return nil
+	case pb.OfferKind_takeback:
+	}
+	g.stopClock(now)
+	last := len(g.boards) - 1
+	for range g.takebackPlies(p.from) {
+		if err := g.board.Undo(); err != nil {
+			return err
+		}
+		g.boards[last] = g.boards[last][:len(g.boards[last])-1]
+	}
+	g.startClock(now)
+	return nil
+}
+
+func (g *game) offer() *pb.Offer {
+	if g.pending == nil {
+		return nil
+	}
+	return &pb.Offer{Kind: g.pending.kind.Enum(), From: g.pending.from.Enum()}
+}
+
+// takebackPlies is how many moves to undo for team to play its last move again, 0 if it didn't
+// play yet. Called with g.mut held.
+func (g *game) takebackPlies(team pb.Team) int {
+	moves := g.boards[len(g.boards)-1]
+	for i := len(moves) - 1; i >= 0 && i >= len(moves)-2; i-- {
+		if moves[i].Mover == engine.Piece(team) {
+			return len(moves) - i
+		}
+	}
+	return 0
+}
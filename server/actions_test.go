@@ -0,0 +1,138 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/geofpwhite/connect4-grpc/pb"
+	"google.golang.org/grpc"
+)
+
+// act sends the action of in for team and returns the state received back by stream.
+func act(t *testing.T, id *int32, team pb.Team, stream grpc.BidiStreamingClient[pb.Input, pb.State], in *pb.Input) *pb.State {
+	t.Helper()
+	in.GameId, in.InputTeam = id, team.Enum()
+	if err := stream.Send(in); err != nil {
+		t.Fatalf("Failed to send %v: %v", in, err)
+	}
+	state, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Failed to receive state: %v", err)
+	}
+	return state
+}
+
+// recv reads the state broadcast to the other player or a spectator.
+func recv(t *testing.T, stream interface{ Recv() (*pb.State, error) }) *pb.State {
+	t.Helper()
+	state, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Failed to receive state: %v", err)
+	}
+	return state
+}
+
+func TestActions(t *testing.T) {
+	client := startServer(t)
+	redCtx, yellowCtx := guest(t, client), guest(t, client)
+	resp, err := client.NewGame(redCtx, &pb.NewGameRequest{})
+	if err != nil {
+		t.Fatalf("Failed to start a new game: %v", err)
+	}
+	join, err := client.JoinGame(yellowCtx, &pb.JoinGameRequest{Id: resp.Id})
+	if err != nil {
+		t.Fatalf("Failed to join the game: %v", err)
+	}
+	red := register(redCtx, t, client, resp)
+	yellow := register(yellowCtx, t, client, join)
+	id := resp.Id
+	resign := &pb.Input{Action: &pb.Input_Resign{Resign: &pb.Empty{}}}
+	offerDraw := &pb.Input{Action: &pb.Input_OfferDraw{OfferDraw: &pb.Empty{}}}
+	accept := &pb.Input{Action: &pb.Input_Accept{Accept: &pb.Empty{}}}
+	decline := &pb.Input{Action: &pb.Input_Decline{Decline: &pb.Empty{}}}
+	takeback := &pb.Input{Action: &pb.Input_RequestTakeback{RequestTakeback: &pb.Empty{}}}
+
+	playMoves(t, id, red, yellow, 4, 4)
+	if state := act(t, id, pb.Team_red, red, &pb.Input{}); state.GetRejection().GetReason() != pb.RejectionReason_unknown_action {
+		t.Fatalf("expected an input without action to be refused, got %v", state.GetRejection())
+	}
+	state := act(t, id, pb.Team_yellow, yellow, resign)
+	recv(t, red)
+	if state.GetResult() != pb.Result_red_won || state.GetEndReason() != pb.EndReason_resigned || state.GetScore().GetRedWins() != 1 {
+		t.Fatalf("expected yellow to have resigned, got %v", state)
+	}
+	if state = act(t, id, pb.Team_red, red, resign); state.GetRejection().GetReason() != pb.RejectionReason_board_over {
+		t.Fatalf("expected resigning a finished board to be refused, got %v", state.GetRejection())
+	}
+
+	// draw offers
+	rematch(t, id, red, yellow)
+	state = act(t, id, pb.Team_red, red, offerDraw)
+	recv(t, yellow)
+	if state.GetOffer().GetKind() != pb.OfferKind_draw_offer || state.GetOffer().GetFrom() != pb.Team_red {
+		t.Fatalf("expected the draw offer of red, got %v", state.GetOffer())
+	}
+	if state = act(t, id, pb.Team_red, red, takeback); state.GetRejection().GetReason() != pb.RejectionReason_offer_pending {
+		t.Fatalf("expected a second offer to be refused, got %v", state.GetRejection())
+	}
+	state = act(t, id, pb.Team_yellow, yellow, decline)
+	recv(t, red)
+	if state.GetOffer() != nil || state.GetResult() != pb.Result_in_progress {
+		t.Fatalf("expected the offer to be declined, got %v", state)
+	}
+	if state = act(t, id, pb.Team_yellow, yellow, accept); state.GetRejection().GetReason() != pb.RejectionReason_no_offer {
+		t.Fatalf("expected accepting without an offer to be refused, got %v", state.GetRejection())
+	}
+	act(t, id, pb.Team_red, red, offerDraw)
+	recv(t, yellow)
+	state = act(t, id, pb.Team_yellow, yellow, offerDraw)
+	recv(t, red)
+	if state.GetResult() != pb.Result_draw || state.GetEndReason() != pb.EndReason_agreed_draw || state.GetScore().GetDraws() != 1 {
+		t.Fatalf("expected crossing draw offers to agree on a draw, got %v", state)
+	}
+
+	// takebacks
+	rematch(t, id, red, yellow)
+	if state = act(t, id, pb.Team_red, red, takeback); state.GetRejection().GetReason() != pb.RejectionReason_nothing_to_take_back {
+		t.Fatalf("expected a takeback before moving to be refused, got %v", state.GetRejection())
+	}
+	playMoves(t, id, red, yellow, 1, 2)
+	act(t, id, pb.Team_red, red, takeback)
+	recv(t, yellow)
+	state = act(t, id, pb.Team_yellow, yellow, accept)
+	recv(t, red)
+	if state.GetField().GetRows()[0].GetValues()[0] != pb.Team_empty || state.GetTurn() != pb.Team_red || state.GetOffer() != nil {
+		t.Fatalf("expected the two last moves to be taken back, got %v", state)
+	}
+	history, err := client.GetGameHistory(redCtx, &pb.GameID{Id: id})
+	if err != nil {
+		t.Fatalf("Failed to get the history: %v", err)
+	}
+	if boards := history.GetBoards(); len(boards) != 3 || len(boards[2].GetMoves()) != 0 ||
+		boards[0].GetEndReason() != pb.EndReason_resigned {
+		t.Fatalf("unexpected history %v", boards)
+	}
+}
+
+func TestBotTakeback(t *testing.T) {
+	client := startServer(t)
+	ctx := guest(t, client)
+	resp, err := client.NewGame(ctx, &pb.NewGameRequest{Opponent: pb.Opponent_bot.Enum()})
+	if err != nil {
+		t.Fatalf("Failed to start a bot game: %v", err)
+	}
+	red := register(ctx, t, client, resp)
+	act(t, resp.Id, pb.Team_red, red, &pb.Input{Action: &pb.Input_Column{Column: 4}})
+	recv(t, red) // the bot answer
+	state := act(t, resp.Id, pb.Team_red, red, &pb.Input{Action: &pb.Input_RequestTakeback{RequestTakeback: &pb.Empty{}}})
+	for _, row := range state.GetField().GetRows() {
+		for _, v := range row.GetValues() {
+			if v != pb.Team_empty {
+				t.Fatalf("expected the bot to accept the takeback, got %v", state.GetField())
+			}
+		}
+	}
+	state = act(t, resp.Id, pb.Team_red, red, &pb.Input{Action: &pb.Input_OfferDraw{OfferDraw: &pb.Empty{}}})
+	if state.GetOffer() != nil || state.GetResult() != pb.Result_in_progress {
+		t.Fatalf("expected the bot to decline the draw, got %v", state)
+	}
+}
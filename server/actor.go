@@ -0,0 +1,53 @@
+package server
+
+// Every live game is owned by an actor, a goroutine running the commands sent to it one at a time:
+// the inputs of the players, the RPCs taking and leaving seats, and the timers of the clock and of
+// the seats. A command thus never sees the game change between its checks and its updates, and
+// the streams of the game are only sent to by the actor. Other goroutines only read the game,
+// under g.mut, which commands lock while they write.
+
+// command is a function run by the actor, its error going back to the sender.
+type command struct {
+	run    func() error
+	result chan error
+}
+
+// loop runs the commands of g until g is deleted.
+func (g *game) loop() {
+	defer close(g.stopped)
+	for {
+		select {
+		case c := <-g.inbox:
+			select {
+			case <-g.done:
+				// deleted while the command was waiting
+				c.result <- errGameNotFound
+				return
+			default:
+			}
+			c.result <- c.run()
+		case <-g.done:
+			return
+		}
+	}
+}
+
+// do runs f on the actor of g and returns its error, or errGameNotFound once g is deleted. f must
+// not call do itself, the actor waiting for it.
+func (g *game) do(f func() error) error {
+	c := command{run: f, result: make(chan error, 1)}
+	select {
+	case g.inbox <- c:
+	case <-g.stopped:
+		return errGameNotFound
+	}
+	return <-c.result
+}
+
+// exec is do for the timers of g, which have nothing to report.
+func (g *game) exec(f func()) {
+	_ = g.do(func() error {
+		f()
+		return nil
+	})
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/geofpwhite/connect4-grpc/engine"
+	"github.com/geofpwhite/connect4-grpc/pb"
+	"github.com/geofpwhite/connect4-grpc/solver"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultAnalyzeTime = 2 * time.Second
+	maxAnalyzeTime     = 10 * time.Second
+)
+
+var errBusyAnalyzing = status.Error(codes.ResourceExhausted, "too many analyses running, try again later")
+
+var outcomes = map[solver.Outcome]pb.Outcome{
+	solver.Unknown: pb.Outcome_unsolved,
+	solver.Win:     pb.Outcome_forced_win,
+	solver.Loss:    pb.Outcome_forced_loss,
+	solver.Draw:    pb.Outcome_forced_draw,
+}
+
+// Analyze searches the position of a game or of the request. The search takes a CPU for up to
+// maxAnalyzeTime, so there are at most as many running at once as there are CPUs.
+func (cs *connect4Server) Analyze(ctx context.Context, req *pb.AnalyzeRequest) (*pb.Analysis, error) {
+	var board *engine.Board
+	if req.GameId != nil {
+		game, exists := cs.games.get(req.GetGameId())
+		if !exists {
+			return nil, errGameNotFound
+		}
+		game.mut.RLock()
+		board = game.board.Clone()
+		game.mut.RUnlock()
+	} else {
+		var err error
+		if board, err = req.GetField().Board(int(req.GetConnect())); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	if req.GetMaxDepth() < 0 || req.GetTimeLimitMs() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "max_depth and time_limit_ms must not be negative")
+	}
+	timeLimit := defaultAnalyzeTime
+	if req.GetTimeLimitMs() > 0 {
+		timeLimit = min(time.Duration(req.GetTimeLimitMs())*time.Millisecond, maxAnalyzeTime)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		timeLimit = min(timeLimit, time.Until(deadline))
+		if timeLimit <= 0 {
+			return nil, status.Error(codes.DeadlineExceeded, "no time left to analyze")
+		}
+	}
+	select {
+	case cs.analyses <- struct{}{}:
+		defer func() { <-cs.analyses }()
+	default:
+		return nil, errBusyAnalyzing
+	}
+	analysis := solver.Analyze(board, solver.Options{MaxDepth: int(req.GetMaxDepth()), TimeLimit: timeLimit})
+
+	//nolint:gosec // depth and plies are bounded by the board size
+	depth, complete, nodes := int32(analysis.Depth), analysis.Complete, analysis.Nodes
+	res := &pb.Analysis{Turn: pb.Team(board.Turn()).Enum(), Depth: &depth, Complete: &complete, Nodes: &nodes}
+	for _, c := range analysis.Columns {
+		//nolint:gosec // depth and plies are bounded by the board size
+		column, plies, score, legal := int32(c.Column+1), int32(c.Plies), int32(c.Score), c.Legal
+		res.Columns = append(res.Columns, &pb.ColumnAnalysis{
+			Column:  &column,
+			Legal:   &legal,
+			Outcome: outcomes[c.Outcome].Enum(),
+			Plies:   &plies,
+			Score:   &score,
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/geofpwhite/connect4-grpc/engine"
+	"github.com/geofpwhite/connect4-grpc/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAnalyzeField(t *testing.T) {
+	client := startServer(t)
+	b, err := engine.New(engine.Variant{Rows: 4, Cols: 4, Connect: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []int{0, 3, 1} {
+		_, _ = b.Drop(c)
+	}
+	connect := int32(3)
+	analysis, err := client.Analyze(guest(t, client), &pb.AnalyzeRequest{Field: pb.NewField(b), Connect: &connect})
+	if err != nil {
+		t.Fatalf("Failed to analyze: %v", err)
+	}
+	if !analysis.GetComplete() || analysis.GetTurn() != pb.Team_yellow {
+		t.Fatalf("expected a complete analysis for yellow, got %v", analysis)
+	}
+	if len(analysis.GetColumns()) != 4 {
+		t.Fatalf("expected 4 columns, got %d", len(analysis.GetColumns()))
+	}
+	// red threatens 3 in a row on the bottom row, yellow must block column 3
+	block := analysis.GetColumns()[2]
+	if block.GetColumn() != 3 || (block.GetOutcome() == pb.Outcome_forced_loss && block.GetPlies() <= 2) {
+		t.Fatalf("expected blocking in column 3 not to lose right away, got %v", block)
+	}
+	other := analysis.GetColumns()[1]
+	if other.GetOutcome() != pb.Outcome_forced_loss || other.GetPlies() != 2 {
+		t.Fatalf("expected column 2 to lose in 2 plies, got %v", other)
+	}
+}
+
+func TestAnalyzeGame(t *testing.T) {
+	cs := newServer()
+	client := serve(t, cs)
+	ctx := guest(t, client)
+	resp, err := client.NewGame(ctx, &pb.NewGameRequest{})
+	if err != nil {
+		t.Fatalf("Failed to start a new game: %v", err)
+	}
+	depth, limit := int32(4), int32(500)
+	analysis, err := client.Analyze(ctx, &pb.AnalyzeRequest{GameId: resp.Id, MaxDepth: &depth, TimeLimitMs: &limit})
+	if err != nil {
+		t.Fatalf("Failed to analyze: %v", err)
+	}
+	if analysis.GetComplete() || analysis.GetDepth() > depth {
+		t.Fatalf("an empty 8x8 board can't be solved in %d plies, got %v", depth, analysis)
+	}
+	for _, c := range analysis.GetColumns() {
+		if !c.GetLegal() || c.GetOutcome() != pb.Outcome_unsolved {
+			t.Fatalf("expected unsolved legal columns, got %v", c)
+		}
+	}
+	unknown := resp.GetId() + 1
+	if _, err := client.Analyze(ctx, &pb.AnalyzeRequest{GameId: &unknown}); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound for an unknown game, got %v", err)
+	}
+	if _, err := client.Analyze(ctx, &pb.AnalyzeRequest{Field: &pb.Field{}}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for an empty field, got %v", err)
+	}
+	if _, err := client.Analyze(context.Background(), &pb.AnalyzeRequest{GameId: resp.Id}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated without a session, got %v", err)
+	}
+	for range cap(cs.analyses) {
+		cs.analyses <- struct{}{}
+	}
+	if _, err := client.Analyze(ctx, &pb.AnalyzeRequest{GameId: resp.Id}); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted while every CPU analyzes, got %v", err)
+	}
+}
@@ -0,0 +1,216 @@
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/geofpwhite/connect4-grpc/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// sessionTTL is how long a session token stays valid.
+	sessionTTL = 30 * 24 * time.Hour
+	// sessionKeyLength is the size in bytes of the key signing the tokens.
+	sessionKeyLength = 32
+)
+
+var (
+	errNoSession     = status.Error(codes.Unauthenticated, `missing "authorization: Bearer <token>" metadata`)
+	errBadSession    = status.Error(codes.Unauthenticated, "invalid or expired session token")
+	errNotSeatOwner  = status.Error(codes.PermissionDenied, "seat belongs to another player")
+	errInvalidFormat = errors.New("malformed token")
+)
+
+// open lists the calls that work without a session.
+var open = map[string]bool{
+	pb.Connect4_Register_FullMethodName:         true,
+	pb.Connect4_Login_FullMethodName:            true,
+	pb.Connect4_Guest_FullMethodName:            true,
+	pb.Connect4_Spectate_FullMethodName:         true,
+	pb.Connect4_ListGames_FullMethodName:        true,
+	pb.Connect4_WatchLobby_FullMethodName:       true,
+	pb.Connect4_GetGameHistory_FullMethodName:   true,
+	pb.Connect4_Replay_FullMethodName:           true,
+	pb.Connect4_ExportGame_FullMethodName:       true,
+	pb.Connect4_GetPlayerProfile_FullMethodName: true,
+	pb.Connect4_GetLeaderboard_FullMethodName:   true,
+}
+
+// sessions issues and checks tokens made of the username and expiry time signed with key, so no
+// session needs to be stored.
+type sessions struct {
+	key []byte
+}
+
+func newSessions() sessions {
+	key := make([]byte, sessionKeyLength)
+	_, _ = rand.Read(key) // never fails
+	return sessions{key: key}
+}
+
+// loadSessions returns the sessions signed with the key of path, written there first when there is
+// no such file, so tokens stay valid when the server restarts. Without path the key only lasts as
+// long as the process.
+func loadSessions(path string) (sessions, error) {
+	if path == "" {
+		return newSessions(), nil
+	}
+	key, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		s := newSessions()
+		return s, os.WriteFile(path, s.key, 0o600)
+	}
+	if err != nil {
+		return sessions{}, err
+	}
+	if len(key) < sessionKeyLength {
+		return sessions{}, fmt.Errorf("session key of %s must be at least %d bytes, got %d", path, sessionKeyLength, len(key))
+	}
+	return sessions{key: key}, nil
+}
+
+func (s sessions) issue(username string) *pb.Session {
+	expires := time.Now().Add(sessionTTL)
+	payload := username + "\n" + strconv.FormatInt(expires.Unix(), 10)
+	token := base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + base64.RawURLEncoding.EncodeToString(s.sign(payload))
+	return &pb.Session{Token: &token, Username: &username, ExpiresAt: timestamppb.New(expires)}
+}
+
+// verify returns the username the token was issued to.
+func (s sessions) verify(token string) (string, error) {
+	encoded, sig, found := strings.Cut(token, ".")
+	if !found {
+		return "", errInvalidFormat
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", errInvalidFormat
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, s.sign(string(payload))) {
+		return "", errInvalidFormat
+	}
+	username, expiry, _ := strings.Cut(string(payload), "\n")
+	unix, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil || time.Now().After(time.Unix(unix, 0)) {
+		return "", errInvalidFormat
+	}
+	return username, nil
+}
+
+func (s sessions) sign(payload string) []byte {
+	h := hmac.New(sha256.New, s.key)
+	h.Write([]byte(payload))
+	return h.Sum(nil)
+}
+
+type userKey struct{}
+
+// caller returns the username of the session of the call, empty for anonymous calls.
+func caller(ctx context.Context) string {
+	name, _ := ctx.Value(userKey{}).(string)
+	return name
+}
+
+// authenticate adds the user of the session token of the call to ctx, failing if the token is
+// invalid or if method needs a session and there is none.
+func (cs *connect4Server) authenticate(ctx context.Context, method string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		if open[method] {
+			return ctx, nil
+		}
+		return nil, errNoSession
+	}
+	token, found := strings.CutPrefix(values[0], "Bearer ")
+	if !found {
+		return nil, errBadSession
+	}
+	username, err := cs.sessions.verify(token)
+	if err != nil {
+		return nil, errBadSession
+	}
+	return context.WithValue(ctx, userKey{}, username), nil
+}
+
+func (cs *connect4Server) unaryAuth(
+	ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+) (any, error) {
+	ctx, err := cs.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (cs *connect4Server) streamAuth(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := cs.authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authedStream{ServerStream: ss, ctx: ctx})
+}
+
+// authedStream carries the context holding the user of the call to stream handlers.
+type authedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authedStream) Context() context.Context {
+	return s.ctx
+}
+
+// interceptors are the options every server needs for the sessions to be checked.
+func (cs *connect4Server) interceptors() []grpc.ServerOption {
+	return []grpc.ServerOption{grpc.UnaryInterceptor(cs.unaryAuth), grpc.StreamInterceptor(cs.streamAuth)}
+}
+
+func (cs *connect4Server) Register(_ context.Context, c *pb.Credentials) (*pb.Session, error) {
+	err := cs.users.add(c.GetUsername(), c.GetPassword())
+	switch {
+	case errors.Is(err, errUserExists):
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, errBadUsername), errors.Is(err, errWeakPassword):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "saving the account: %v", err)
+	}
+	return cs.sessions.issue(c.GetUsername()), nil
+}
+
+func (cs *connect4Server) Login(_ context.Context, c *pb.Credentials) (*pb.Session, error) {
+	if err := cs.users.check(c.GetUsername(), c.GetPassword()); err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return cs.sessions.issue(c.GetUsername()), nil
+}
+
+// Guest returns a session for a new random username that isn't registered, '~' keeping it apart
+// from account names.
+func (cs *connect4Server) Guest(context.Context, *pb.Empty) (*pb.Session, error) {
+	return cs.sessions.issue(guestPrefix + strings.ToLower(rand.Text()[:8])), nil
+}
+
+// playerName is the name shown for the caller, the one asked for or else their username.
+func playerName(ctx context.Context, asked string) string {
+	if asked == "" {
+		return caller(ctx)
+	}
+	return asked
+}
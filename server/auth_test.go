@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/geofpwhite/connect4-grpc/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAccounts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.json")
+	users, err := loadUsers(path)
+	if err != nil {
+		t.Fatalf("Failed to open the user store: %v", err)
+	}
+	cs := newServer()
+	cs.users = users
+	client := serve(t, cs)
+	ctx := context.Background()
+	name, password, wrong := "alice", "correct horse", "wrong password"
+	alice := &pb.Credentials{Username: &name, Password: &password}
+
+	if _, err := client.NewGame(ctx, &pb.NewGameRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated without a session, got %v", err)
+	}
+	session, err := client.Register(ctx, alice)
+	if err != nil {
+		t.Fatalf("Failed to register: %v", err)
+	}
+	if session.GetUsername() != "alice" || session.GetExpiresAt().AsTime().Before(time.Now()) {
+		t.Fatalf("unexpected session %v", session)
+	}
+	if _, err := client.Register(ctx, alice); status.Code(err) != codes.AlreadyExists {
+		t.Fatalf("expected AlreadyExists registering twice, got %v", err)
+	}
+	bob, weak := "bob", "short"
+	short := &pb.Credentials{Username: &bob, Password: &weak}
+	if _, err := client.Register(ctx, short); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for a short password, got %v", err)
+	}
+
+	// the account survives a restart
+	if cs.users, err = loadUsers(path); err != nil {
+		t.Fatalf("Failed to reload the user store: %v", err)
+	}
+	_, err = client.Login(ctx, &pb.Credentials{Username: alice.Username, Password: &wrong})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated for a wrong password, got %v", err)
+	}
+	session, err = client.Login(ctx, alice)
+	if err != nil {
+		t.Fatalf("Failed to log in: %v", err)
+	}
+	authed := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+session.GetToken())
+	resp, err := client.NewGame(authed, &pb.NewGameRequest{})
+	if err != nil {
+		t.Fatalf("Failed to start a game: %v", err)
+	}
+	register(authed, t, client, resp)
+	forged := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+session.GetToken()+"x")
+	if _, err := client.NewGame(forged, &pb.NewGameRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated with a forged token, got %v", err)
+	}
+}
+
+func TestSeatOwnership(t *testing.T) {
+	client := startServer(t)
+	redCtx, yellowCtx := guest(t, client), guest(t, client)
+	resp, err := client.NewGame(redCtx, &pb.NewGameRequest{})
+	if err != nil {
+		t.Fatalf("Failed to start a new game: %v", err)
+	}
+	join, err := client.JoinGame(yellowCtx, &pb.JoinGameRequest{Id: resp.Id})
+	if err != nil {
+		t.Fatalf("Failed to join the game: %v", err)
+	}
+	// even with the resume token, another player can't take the seat
+	if _, _, err := connect(yellowCtx, client, resp); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied on the seat of another player, got %v", err)
+	}
+	if _, err := client.LeaveGame(yellowCtx, resp); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied leaving the seat of another player, got %v", err)
+	}
+
+	register(redCtx, t, client, resp)
+	yellow := register(yellowCtx, t, client, join)
+	col := int32(1)
+	err = yellow.Send(&pb.Input{GameId: resp.Id, Action: &pb.Input_Column{Column: col}, InputTeam: pb.Team_red.Enum()})
+	if err != nil {
+		t.Fatalf("Failed to send move: %v", err)
+	}
+	state, err := yellow.Recv()
+	if err != nil {
+		t.Fatalf("Failed to receive state: %v", err)
+	}
+	if state.GetRejection().GetReason() != pb.RejectionReason_wrong_team {
+		t.Fatalf("expected a move for red on the yellow stream to be refused, got %v", state.GetRejection())
+	}
+}
+
+func TestSessionKeyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.key")
+	first, err := loadSessions(path)
+	if err != nil {
+		t.Fatalf("Failed to create the session key: %v", err)
+	}
+	second, err := loadSessions(path)
+	if err != nil {
+		t.Fatalf("Failed to load the session key: %v", err)
+	}
+	if name, err := second.verify(first.issue("ann").GetToken()); err != nil || name != "ann" {
+		t.Fatalf("expected a token to outlive a restart, got %q, %v", name, err)
+	}
+	if err := os.WriteFile(path, []byte("short"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadSessions(path); err == nil {
+		t.Fatal("expected a short session key to be refused")
+	}
+}
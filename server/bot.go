@@ -0,0 +1,84 @@
+package server
+
+import (
+	"slices"
+
+	"github.com/geofpwhite/connect4-grpc/bot"
+	"github.com/geofpwhite/connect4-grpc/engine"
+	"github.com/geofpwhite/connect4-grpc/pb"
+)
+
+// botSeat is a computer player filling one of the seats of a game.
+type botSeat struct {
+	team   pb.Team
+	player *bot.Player
+	wake   chan struct{} // asks the goroutine of the bot to look at the position
+}
+
+func newBotSeat(team pb.Team, player *bot.Player) *botSeat {
+	return &botSeat{team: team, player: player, wake: make(chan struct{}, 1)}
+}
+
+// startBot starts the goroutine of the bot of g, if it has one, until g is deleted. Only that
+// goroutine uses the player, so the bot never thinks about two positions at once.
+func (cs *connect4Server) startBot(id int32, g *game) {
+	if g.bot == nil {
+		return
+	}
+	go func() {
+		for {
+			select {
+			case <-g.bot.wake:
+				cs.playBot(id, g)
+			case <-g.done:
+				return
+			}
+		}
+	}()
+}
+
+// wakeBot has the bot of g play if it is its turn, without waiting for it.
+func (g *game) wakeBot() {
+	if g.bot == nil {
+		return
+	}
+	select {
+	case g.bot.wake <- struct{}{}:
+	default: // already woken, it will see the latest position
+	}
+}
+
+// playBot makes the bot move for as long as it is its turn, each move going through modifyState
+// and being broadcast like a human one. It runs on the goroutine of the bot, which thinks outside
+// of the actor of g so the players can still chat or resign, its move being dropped if the position
+// changed in the meantime.
+func (cs *connect4Server) playBot(id int32, g *game) {
+	for {
+		g.mut.RLock()
+		seat, board, boards := g.bot, g.board.Clone(), len(g.boards)
+		over := g.matchWinner() != pb.Team_empty || g.over()
+		g.mut.RUnlock()
+		if seat == nil || over || board.Turn() != engine.Piece(seat.team) {
+			return
+		}
+		column := int32(seat.player.Move(board) + 1) //nolint:gosec // board is at most engine.MaxSize wide
+		err := g.do(func() error {
+			g.mut.RLock()
+			changed := len(g.boards) != boards || !slices.Equal(g.board.Moves(), board.Moves())
+			g.mut.RUnlock()
+			if changed {
+				return errNotYourTurn
+			}
+			if err := g.modifyState(column, seat.team); err != nil {
+				return err
+			}
+			cs.rate(g)
+			cs.save(id, g)
+			g.update(g.pbState())
+			return nil
+		})
+		if err != nil {
+			return // the position changed under the bot or the game was deleted, the player moves next
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package server
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"fortio.org/log"
+	"github.com/geofpwhite/connect4-grpc/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// stateQueue is how many states a player or spectator may lag behind. Once the queue is full
+	// the states a later one supersedes are dropped, then the stream is evicted if that isn't enough.
+	stateQueue = 16
+	// flushTimeout bounds the wait for the last messages of a stream ending normally.
+	flushTimeout = time.Second
+)
+
+var (
+	errSlowSubscriber = status.Error(codes.ResourceExhausted, "stream fell too far behind")
+	errUnsubscribed   = errors.New("subscriber closed")
+)
+
+// queueStats are the metrics of the queues of the subscribers of a server.
+type queueStats struct {
+	subscribers atomic.Int64
+	queued      atomic.Int64 // messages waiting in all the queues
+	maxDepth    atomic.Int64 // deepest a queue has been
+	coalesced   atomic.Int64 // messages dropped for a later one
+	evicted     atomic.Int64
+}
+
+func (q *queueStats) depth(n int) {
+	for {
+		deepest := q.maxDepth.Load()
+		if int64(n) <= deepest || q.maxDepth.CompareAndSwap(deepest, int64(n)) {
+			return
+		}
+	}
+}
+
+// snapshot returns the metrics by name, for expvar.
+func (q *queueStats) snapshot() any {
+	return map[string]int64{
+		"subscribers": q.subscribers.Load(),
+		"queued":      q.queued.Load(),
+		"max_depth":   q.maxDepth.Load(),
+		"coalesced":   q.coalesced.Load(),
+		"evicted":     q.evicted.Load(),
+	}
+}
+
+// entry is a queued message, or a mark closing reached once the messages before it were sent.
+type entry[T any] struct {
+	msg     T
+	reached chan struct{}
+}
+
+// subscriber feeds a stream from a bounded queue on a goroutine of its own, so a slow client only
+// delays itself. It is used for the players, spectators and lobby watchers.
+type subscriber[T any] struct {
+	name       string // for the logs
+	send       func(T) error
+	size       int
+	superseded func(T) bool // whether any later message makes msg useless, nil if none does
+	stats      *queueStats
+	mut        sync.Mutex
+	queue      []entry[T]
+	wake       chan struct{} // holds a token while the queue may not be empty
+	gone       chan struct{} // closed once evicted, closed or failing to send
+	err        error         // why gone was closed
+}
+
+func subscribe[T any](stats *queueStats, name string, size int, send func(T) error, superseded func(T) bool) *subscriber[T] {
+	s := &subscriber[T]{
+		name:       name,
+		send:       send,
+		size:       size,
+		superseded: superseded,
+		stats:      stats,
+		wake:       make(chan struct{}, 1),
+		gone:       make(chan struct{}),
+	}
+	stats.subscribers.Add(1)
+	go s.run()
+	return s
+}
+
+// supersededState reports whether a later state makes s useless, states being snapshots of the
+// whole game unless they carry a chat line.
+func supersededState(s *pb.State) bool {
+	return s.GetChat() == nil
+}
+
+// push queues msg for the stream without blocking.
+func (s *subscriber[T]) push(msg T) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	if s.err != nil {
+		return
+	}
+	if len(s.queue) >= s.size && s.superseded != nil {
+		kept := s.queue[:0]
+		for _, e := range s.queue {
+			if e.reached != nil || !s.superseded(e.msg) {
+				kept = append(kept, e)
+			}
+		}
+		dropped := int64(len(s.queue) - len(kept))
+		clear(s.queue[len(kept):])
+		s.queue = kept
+		s.stats.coalesced.Add(dropped)
+		s.stats.queued.Add(-dropped)
+	}
+	if len(s.queue) >= s.size {
+		s.stats.evicted.Add(1)
+		s.stop(errSlowSubscriber)
+		return
+	}
+	s.enqueue(entry[T]{msg: msg})
+	s.stats.queued.Add(1)
+	s.stats.depth(len(s.queue))
+}
+
+// enqueue appends e to the queue. Called with s.mut held.
+func (s *subscriber[T]) enqueue(e entry[T]) {
+	s.queue = append(s.queue, e)
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// stop drops the queue and makes the stream end with err. Called with s.mut held.
+func (s *subscriber[T]) stop(err error) {
+	if s.err != nil {
+		return
+	}
+	s.err = err
+	close(s.gone)
+	for _, e := range s.queue {
+		if e.reached == nil {
+			s.stats.queued.Add(-1)
+		}
+	}
+	s.queue = nil
+	s.stats.subscribers.Add(-1)
+}
+
+func (s *subscriber[T]) run() {
+	for {
+		select {
+		case <-s.wake:
+		case <-s.gone:
+			return
+		}
+		for {
+			e, ok := s.pop()
+			if !ok {
+				break
+			}
+			if e.reached != nil {
+				close(e.reached)
+				continue
+			}
+			if err := s.send(e.msg); err != nil {
+				log.Warnf("%s stream can't send: %v", s.name, err)
+				s.mut.Lock()
+				s.stop(err)
+				s.mut.Unlock()
+				return
+			}
+		}
+	}
+}
+
+func (s *subscriber[T]) pop() (entry[T], bool) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	if len(s.queue) == 0 {
+		return entry[T]{}, false
+	}
+	e := s.queue[0]
+	s.queue[0] = entry[T]{}
+	s.queue = s.queue[1:]
+	if e.reached == nil {
+		s.stats.queued.Add(-1)
+	}
+	return e, true
+}
+
+// done is closed once the stream should end, Err telling why.
+func (s *subscriber[T]) done() <-chan struct{} {
+	return s.gone
+}
+
+// Err is the send error or eviction that closed done.
+func (s *subscriber[T]) Err() error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return s.err
+}
+
+// close waits up to flushTimeout for the queued messages to be sent, then stops the subscriber.
+func (s *subscriber[T]) close() {
+	s.end(errUnsubscribed)
+}
+
+// end is close making the stream end with err.
+func (s *subscriber[T]) end(err error) {
+	s.mut.Lock()
+	if s.err != nil {
+		s.mut.Unlock()
+		return
+	}
+	reached := make(chan struct{})
+	s.enqueue(entry[T]{reached: reached})
+	s.mut.Unlock()
+	select {
+	case <-reached:
+	case <-s.gone:
+	case <-time.After(flushTimeout):
+	}
+	s.mut.Lock()
+	s.stop(err)
+	s.mut.Unlock()
+}
+
+// broadcast queues s for every subscriber.
+func broadcast(subscribers []*subscriber[*pb.State], s *pb.State) {
+	for _, sub := range subscribers {
+		sub.push(s)
+	}
+}
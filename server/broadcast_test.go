@@ -0,0 +1,105 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/geofpwhite/connect4-grpc/pb"
+)
+
+// stuckClient is a stream whose sends block until released, the sent states going to got.
+type stuckClient struct {
+	started chan struct{} // gets a token when a send starts
+	release chan struct{}
+	got     chan *pb.State
+}
+
+func newStuckClient() *stuckClient {
+	return &stuckClient{started: make(chan struct{}, 1), release: make(chan struct{}), got: make(chan *pb.State, 2*stateQueue)}
+}
+
+func (c *stuckClient) Send(s *pb.State) error {
+	select {
+	case c.started <- struct{}{}:
+	default:
+	}
+	<-c.release
+	c.got <- s
+	return nil
+}
+
+func numbered(n int32) *pb.State {
+	return &pb.State{Score: &pb.Score{RedWins: &n}}
+}
+
+func TestSubscriberCoalesces(t *testing.T) {
+	var stats queueStats
+	client := newStuckClient()
+	sub := subscribe(&stats, "slow", stateQueue, client.Send, supersededState)
+	sub.push(numbered(0))
+	<-client.started // the first state is being sent, the others queue up
+	text := "hi"
+	chat := numbered(1)
+	chat.Chat = &pb.ChatLine{Text: &text}
+	sub.push(chat)
+	for i := range int32(2 * stateQueue) {
+		sub.push(numbered(2 + i))
+	}
+	if stats.coalesced.Load() == 0 || stats.evicted.Load() != 0 || stats.maxDepth.Load() != stateQueue {
+		t.Fatalf("expected full queues to drop old states, got %v", stats.snapshot())
+	}
+	close(client.release)
+	sub.close()
+	var got []*pb.State
+	for len(client.got) > 0 {
+		got = append(got, <-client.got)
+	}
+	if len(got) >= 2+2*stateQueue || got[1].GetChat().GetText() != "hi" {
+		t.Fatalf("expected the chat line to be kept and old states dropped, got %d states", len(got))
+	}
+	if last := got[len(got)-1].GetScore().GetRedWins(); last != 1+2*stateQueue {
+		t.Fatalf("expected the latest state last, got state %d", last)
+	}
+	if stats.queued.Load() != 0 || stats.subscribers.Load() != 0 {
+		t.Fatalf("expected nothing left queued, got %v", stats.snapshot())
+	}
+}
+
+func TestSubscriberEviction(t *testing.T) {
+	var stats queueStats
+	client := newStuckClient()
+	defer close(client.release)
+	sub := subscribe(&stats, "slow", stateQueue, client.Send, supersededState)
+	text := "hi"
+	for range stateQueue + 2 {
+		sub.push(&pb.State{Chat: &pb.ChatLine{Text: &text}})
+	}
+	select {
+	case <-sub.done():
+	case <-time.After(time.Second):
+		t.Fatal("expected a client lagging behind on chat lines to be evicted")
+	}
+	if sub.Err() != errSlowSubscriber || stats.evicted.Load() != 1 || stats.queued.Load() != 0 { //nolint:errorlint // returned as is
+		t.Fatalf("expected an eviction, got %v and %v", sub.Err(), stats.snapshot())
+	}
+}
+
+func TestSubscriberFlush(t *testing.T) {
+	var stats queueStats
+	got := make(chan *pb.State, 3)
+	sub := subscribe(&stats, "fast", stateQueue, func(s *pb.State) error {
+		got <- s
+		return nil
+	}, supersededState)
+	for i := range int32(3) {
+		sub.push(numbered(i))
+	}
+	sub.close()
+	if len(got) != 3 {
+		t.Fatalf("expected the queued states to be sent before closing, got %d", len(got))
+	}
+	sub.push(numbered(3))
+	if len(got) != 3 || stats.queued.Load() != 0 {
+		t.Fatal("expected a closed subscriber to ignore new states")
+	}
+}
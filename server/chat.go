@@ -0,0 +1,84 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/geofpwhite/connect4-grpc/pb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const maxChatLength = 200
+
+var errInvalidChat = errors.New("invalid chat message")
+
+// isChat reports whether input is for the chat rather than the game.
+func isChat(input *pb.Input) bool {
+	switch input.GetAction().(type) {
+	case *pb.Input_Chat, *pb.Input_MuteChat:
+		return true
+	}
+	return false
+}
+
+// chat sends the message of input from team to the players and spectators, or mutes the chat of
+// the opponent for team. Runs on the actor of g, without g.mut held.
+func (g *game) chat(team pb.Team, input *pb.Input) error {
+	if mute, ok := input.GetAction().(*pb.Input_MuteChat); ok {
+		g.mut.Lock()
+		if team == pb.Team_red {
+			g.redMuted = mute.MuteChat
+		} else {
+			g.yellowMuted = mute.MuteChat
+		}
+		g.mut.Unlock()
+		return nil
+	}
+	line, err := chatLine(input.GetChat(), team)
+	if err != nil {
+		return err
+	}
+	g.mut.RLock()
+	subscribers := g.receivers()
+	var muting *subscriber[*pb.State] // the opponent, if they muted the chat
+	name := g.redName                 // a copy, as the line may be sent once g changed
+	if team == pb.Team_red {
+		if g.yellowMuted {
+			muting = g.yellowStream
+		}
+	} else {
+		name = g.yellowName
+		if g.redMuted {
+			muting = g.redStream
+		}
+	}
+	g.mut.RUnlock()
+	line.Name = &name
+	s := g.pbState()
+	s.Chat = line
+	broadcast(slices.DeleteFunc(subscribers, func(sub *subscriber[*pb.State]) bool { return sub == muting }), s)
+	return nil
+}
+
+func chatLine(m *pb.ChatMessage, team pb.Team) (*pb.ChatLine, error) {
+	text := strings.TrimSpace(m.GetText())
+	switch {
+	case text == "" && m.GetEmote() == pb.Emote_no_emote:
+		return nil, fmt.Errorf("%w: neither text nor emote", errInvalidChat)
+	case !utf8.ValidString(text):
+		return nil, fmt.Errorf("%w: text isn't valid UTF-8", errInvalidChat)
+	case utf8.RuneCountInString(text) > maxChatLength:
+		return nil, fmt.Errorf("%w: text is longer than %d characters", errInvalidChat, maxChatLength)
+	case strings.ContainsFunc(text, unicode.IsControl):
+		return nil, fmt.Errorf("%w: text holds control characters", errInvalidChat)
+	}
+	line := &pb.ChatLine{From: team.Enum(), Emote: m.GetEmote().Enum(), SentAt: timestamppb.Now()}
+	if text != "" {
+		line.Text = &text
+	}
+	return line, nil
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/geofpwhite/connect4-grpc/pb"
+)
+
+func say(text string, emote pb.Emote) *pb.Input {
+	return &pb.Input{Action: &pb.Input_Chat{Chat: &pb.ChatMessage{Text: &text, Emote: emote.Enum()}}}
+}
+
+func TestChat(t *testing.T) {
+	client := startServer(t)
+	alice := "alice"
+	redCtx, yellowCtx := guest(t, client), guest(t, client)
+	resp, err := client.NewGame(redCtx, &pb.NewGameRequest{PlayerName: &alice})
+	if err != nil {
+		t.Fatalf("Failed to start a new game: %v", err)
+	}
+	join, err := client.JoinGame(yellowCtx, &pb.JoinGameRequest{Id: resp.Id})
+	if err != nil {
+		t.Fatalf("Failed to join the game: %v", err)
+	}
+	red := register(redCtx, t, client, resp)
+	yellow := register(yellowCtx, t, client, join)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	watch, err := client.Spectate(ctx, &pb.GameID{Id: resp.Id})
+	if err != nil {
+		t.Fatalf("Failed to spectate: %v", err)
+	}
+	if _, err := watch.Recv(); err != nil {
+		t.Fatalf("Failed to receive the initial state: %v", err)
+	}
+	id := resp.Id
+
+	state := act(t, id, pb.Team_red, red, say(" good luck ", pb.Emote_hello))
+	for name, s := range map[string]*pb.State{"red": state, "yellow": recv(t, yellow), "spectator": recv(t, watch)} {
+		line := s.GetChat()
+		if line.GetText() != "good luck" || line.GetEmote() != pb.Emote_hello || line.GetName() != alice ||
+			line.GetFrom() != pb.Team_red {
+			t.Fatalf("expected %s to get the message of alice, got %v", name, line)
+		}
+	}
+	for name, in := range map[string]*pb.Input{
+		"empty":    say("  ", pb.Emote_no_emote),
+		"too long": say(strings.Repeat("a", maxChatLength+1), pb.Emote_no_emote),
+		"control":  say("\x1b[2J", pb.Emote_no_emote),
+	} {
+		if state := act(t, id, pb.Team_red, red, in); state.GetRejection().GetReason() != pb.RejectionReason_invalid_chat {
+			t.Errorf("%s: expected the message to be refused, got %v", name, state.GetRejection())
+		}
+	}
+
+	mute := &pb.Input{GameId: id, InputTeam: pb.Team_yellow.Enum(), Action: &pb.Input_MuteChat{MuteChat: true}}
+	if err := yellow.Send(mute); err != nil {
+		t.Fatalf("Failed to mute: %v", err)
+	}
+	// the stream handles inputs in order, the rejection coming once yellow muted red
+	act(t, id, pb.Team_yellow, yellow, say("", pb.Emote_no_emote))
+	act(t, id, pb.Team_red, red, say("", pb.Emote_good_game))
+	if line := recv(t, watch).GetChat(); line.GetEmote() != pb.Emote_good_game {
+		t.Fatalf("expected spectators to get the emote, got %v", line)
+	}
+	act(t, id, pb.Team_red, red, &pb.Input{Action: &pb.Input_Column{Column: 3}})
+	if state := recv(t, yellow); state.GetChat() != nil {
+		t.Fatalf("expected yellow to have muted red, got %v", state.GetChat())
+	}
+}
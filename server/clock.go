@@ -0,0 +1,203 @@
+package server
+
+import (
+	"math"
+	"time"
+
+	"github.com/geofpwhite/connect4-grpc/engine"
+	"github.com/geofpwhite/connect4-grpc/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	maxInitialTime        = 24 * time.Hour
+	maxCorrespondenceDays = 30
+	// endTimeout ends a board lost on time by the mover of the record.
+	endTimeout = "timeout"
+)
+
+// TimeControl is how long players have to move, the zero value for untimed games.
+type TimeControl struct {
+	Initial   time.Duration `json:"initial"`
+	Increment time.Duration `json:"increment,omitempty"`
+	PerMove   bool          `json:"per_move,omitempty"` // Initial is given again for every move
+	Days      int           `json:"days,omitempty"`     // correspondence games, Initial being that many days
+}
+
+func timeControlOf(tc *pb.TimeControl) (TimeControl, error) {
+	var c TimeControl
+	switch kind := tc.GetKind().(type) {
+	case nil:
+		return c, nil
+	case *pb.TimeControl_Fischer:
+		c.Initial, c.Increment = kind.Fischer.GetInitial().AsDuration(), kind.Fischer.GetIncrement().AsDuration()
+		if c.Increment < 0 || c.Increment > c.Initial {
+			return c, status.Errorf(codes.InvalidArgument, "increment must be between 0 and the initial time, got %v", c.Increment)
+		}
+	case *pb.TimeControl_PerMove:
+		c.Initial, c.PerMove = kind.PerMove.AsDuration(), true
+	case *pb.TimeControl_CorrespondenceDays:
+		c.Days, c.PerMove = int(kind.CorrespondenceDays), true
+		if c.Days < 1 || c.Days > maxCorrespondenceDays {
+			return c, status.Errorf(codes.InvalidArgument, "correspondence days must be between 1 and %d, got %d",
+				maxCorrespondenceDays, c.Days)
+		}
+		c.Initial = time.Duration(c.Days) * 24 * time.Hour
+		return c, nil
+	}
+	if c.Initial <= 0 || c.Initial > maxInitialTime {
+		return c, status.Errorf(codes.InvalidArgument, "time must be positive and at most %v, got %v", maxInitialTime, c.Initial)
+	}
+	return c, nil
+}
+
+func (c TimeControl) message() *pb.TimeControl {
+	switch {
+	case c == TimeControl{}:
+		return nil
+	case c.Days > 0:
+		days := int32(c.Days) //nolint:gosec // at most maxCorrespondenceDays
+		return &pb.TimeControl{Kind: &pb.TimeControl_CorrespondenceDays{CorrespondenceDays: days}}
+	case c.PerMove:
+		return &pb.TimeControl{Kind: &pb.TimeControl_PerMove{PerMove: durationpb.New(c.Initial)}}
+	}
+	return &pb.TimeControl{Kind: &pb.TimeControl_Fischer{Fischer: &pb.Fischer{
+		Initial:   durationpb.New(c.Initial),
+		Increment: durationpb.New(c.Increment),
+	}}}
+}
+
+// clock is the time left to the players of a timed game.
+type clock struct {
+	control     TimeControl
+	red, yellow time.Duration // left when the side to move started thinking
+	since       time.Time     // when the side to move started thinking, zero while stopped
+	timer       *time.Timer   // ends the board once the side to move runs out of time
+}
+
+func newClock(control TimeControl) *clock {
+	if control == (TimeControl{}) {
+		return nil
+	}
+	return &clock{control: control, red: control.Initial, yellow: control.Initial}
+}
+
+func (c *clock) of(p engine.Piece) *time.Duration {
+	if p == engine.Yellow {
+		return &c.yellow
+	}
+	return &c.red
+}
+
+// armClock creates the timer of the clock of the game registered as id.
+func (cs *connect4Server) armClock(id int32, g *game) {
+	if g.clock == nil {
+		return
+	}
+	g.clock.timer = time.AfterFunc(math.MaxInt64, func() { g.exec(func() { cs.flag(id, g) }) })
+	g.clock.timer.Stop()
+}
+
+// flag ends the board when the side to move ran out of time. Runs on the actor of g.
+func (cs *connect4Server) flag(id int32, g *game) {
+	g.mut.Lock()
+	now := time.Now()
+	flagged := g.outOfTime(now)
+	if flagged {
+		g.forfeit(now)
+	} else if !g.clock.since.IsZero() {
+		// the timer went off for a previous move
+		g.clock.timer.Reset(g.timeLeft(g.board.Turn(), now))
+	}
+	g.mut.Unlock()
+	if flagged {
+		cs.rate(g)
+		cs.save(id, g)
+		g.update(g.pbState())
+	}
+}
+
+// The following methods are called with g.mut held.
+
+// timeLeft is the time p has at now.
+func (g *game) timeLeft(p engine.Piece, now time.Time) time.Duration {
+	left := *g.clock.of(p)
+	if !g.clock.since.IsZero() && p == g.board.Turn() {
+		left -= now.Sub(g.clock.since)
+	}
+	return max(left, 0)
+}
+
+// startClock runs the clock of the side to move once both players are seated.
+func (g *game) startClock(now time.Time) {
+	if g.clock == nil || g.clock.timer == nil || !g.clock.since.IsZero() || !g.red || !g.yellow || g.over() {
+		return
+	}
+	g.clock.since = now
+	g.clock.timer.Reset(*g.clock.of(g.board.Turn()))
+}
+
+// stopClock pauses the clock, while a seat is free.
+func (g *game) stopClock(now time.Time) {
+	if g.clock == nil || g.clock.since.IsZero() {
+		return
+	}
+	*g.clock.of(g.board.Turn()) = g.timeLeft(g.board.Turn(), now)
+	g.clock.since = time.Time{}
+	g.clock.timer.Stop()
+}
+
+// resetClock gives both players their initial time for a new board.
+func (g *game) resetClock() {
+	if g.clock == nil {
+		return
+	}
+	g.stopClock(time.Now())
+	g.clock.red, g.clock.yellow = g.clock.control.Initial, g.clock.control.Initial
+}
+
+func (g *game) outOfTime(now time.Time) bool {
+	return g.clock != nil && !g.clock.since.IsZero() && g.timeLeft(g.board.Turn(), now) <= 0
+}
+
+// forfeit ends the board as lost on time by the side to move.
+func (g *game) forfeit(now time.Time) {
+	_ = g.apply(MoveRecord{Mover: g.board.Turn(), At: now, End: endTimeout}) // ends never fail
+}
+
+// tick charges the mover of m for the time taken and hands the clock to the opponent.
+func (g *game) tick(m MoveRecord) {
+	if g.clock == nil || g.clock.since.IsZero() {
+		return
+	}
+	left := g.clock.of(m.Mover)
+	switch {
+	case m.End != "":
+		*left = 0
+	case g.clock.control.PerMove:
+		*left = g.clock.control.Initial
+	default:
+		*left -= m.At.Sub(g.clock.since)
+		*left += g.clock.control.Increment
+	}
+	g.clock.since = time.Time{}
+	g.clock.timer.Stop()
+	if !g.over() {
+		g.clock.since = m.At
+		g.clock.timer.Reset(*g.clock.of(g.board.Turn()))
+	}
+}
+
+func (g *game) clocks(now time.Time) *pb.Clocks {
+	if g.clock == nil {
+		return nil
+	}
+	running := !g.clock.since.IsZero()
+	return &pb.Clocks{
+		Red:     durationpb.New(g.timeLeft(engine.Red, now)),
+		Yellow:  durationpb.New(g.timeLeft(engine.Yellow, now)),
+		Running: &running,
+	}
+}
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/geofpwhite/connect4-grpc/engine"
+	"github.com/geofpwhite/connect4-grpc/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func fischer(initial, increment time.Duration) *pb.TimeControl {
+	return &pb.TimeControl{Kind: &pb.TimeControl_Fischer{Fischer: &pb.Fischer{
+		Initial:   durationpb.New(initial),
+		Increment: durationpb.New(increment),
+	}}}
+}
+
+func TestTimeControls(t *testing.T) {
+	for name, tc := range map[string]*pb.TimeControl{
+		"no time":         fischer(0, 0),
+		"large increment": fischer(time.Second, time.Minute),
+		"too long":        fischer(48*time.Hour, 0),
+		"negative move":   {Kind: &pb.TimeControl_PerMove{PerMove: durationpb.New(-time.Second)}},
+		"no days":         {Kind: &pb.TimeControl_CorrespondenceDays{CorrespondenceDays: 0}},
+		"many days":       {Kind: &pb.TimeControl_CorrespondenceDays{CorrespondenceDays: 365}},
+	} {
+		if _, err := timeControlOf(tc); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: expected InvalidArgument, got %v", name, err)
+		}
+	}
+	c, err := timeControlOf(&pb.TimeControl{Kind: &pb.TimeControl_CorrespondenceDays{CorrespondenceDays: 3}})
+	if err != nil || c.Initial != 72*time.Hour || !c.PerMove {
+		t.Fatalf("expected 3 days per move, got %+v, %v", c, err)
+	}
+	if back, _ := timeControlOf(c.message()); back != c {
+		t.Fatalf("expected %+v after a round trip, got %+v", c, back)
+	}
+}
+
+func TestClockTicks(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		control TimeControl
+		left    time.Duration // red's after moving 3s in
+	}{
+		{"fischer", TimeControl{Initial: 10 * time.Second, Increment: 2 * time.Second}, 9 * time.Second},
+		{"per move", TimeControl{Initial: 10 * time.Second, PerMove: true}, 10 * time.Second},
+	} {
+		board, _ := engine.New(engine.Classic)
+		g := &game{variant: engine.Classic, board: board, red: true, yellow: true, clock: newClock(test.control),
+			boards: [][]MoveRecord{nil}}
+		g.setup()
+		newServer().armClock(1, g)
+		start := time.Now()
+		g.startClock(start)
+		if err := g.play(3, start.Add(3*time.Second)); err != nil {
+			t.Fatalf("%s: failed to play: %v", test.name, err)
+		}
+		if left := g.timeLeft(engine.Red, start.Add(4*time.Second)); left != test.left {
+			t.Errorf("%s: expected red to have %v left, got %v", test.name, test.left, left)
+		}
+		if left := g.timeLeft(engine.Yellow, start.Add(4*time.Second)); left != 9*time.Second {
+			t.Errorf("%s: expected yellow's clock to run since the move, got %v left", test.name, left)
+		}
+		g.stopClock(start.Add(5 * time.Second))
+		if left := g.timeLeft(engine.Yellow, start.Add(time.Hour)); left != 8*time.Second {
+			t.Errorf("%s: expected the clock to be stopped with 8s left, got %v", test.name, left)
+		}
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	cs := newServer()
+	client := serve(t, cs)
+	redCtx, yellowCtx := guest(t, client), guest(t, client)
+	resp, err := client.NewGame(redCtx, &pb.NewGameRequest{TimeControl: fischer(200*time.Millisecond, 0)})
+	if err != nil {
+		t.Fatalf("Failed to start a new game: %v", err)
+	}
+	red := register(redCtx, t, client, resp)
+	join, err := client.JoinGame(yellowCtx, &pb.JoinGameRequest{Id: resp.Id})
+	if err != nil {
+		t.Fatalf("Failed to join the game: %v", err)
+	}
+	g, _ := cs.games.get(resp.GetId())
+	g.mut.RLock()
+	running := !g.clock.since.IsZero()
+	g.mut.RUnlock()
+	if running {
+		t.Fatal("expected the clock to wait for yellow to attach")
+	}
+	yellow := register(yellowCtx, t, client, join)
+	state := playMoves(t, resp.Id, red, yellow, 4)
+	if clocks := state.GetClocks(); !clocks.GetRunning() || clocks.GetYellow().AsDuration() > 200*time.Millisecond {
+		t.Fatalf("expected yellow's clock to run, got %v", clocks)
+	}
+
+	// yellow doesn't move
+	if state, err = red.Recv(); err != nil {
+		t.Fatalf("Failed to receive state: %v", err)
+	}
+	recv(t, yellow)
+	if state.GetResult() != pb.Result_red_won || state.GetEndReason() != pb.EndReason_timeout ||
+		state.GetScore().GetRedWins() != 1 || state.GetClocks().GetRunning() || state.GetClocks().GetYellow().AsDuration() != 0 {
+		t.Fatalf("expected yellow to lose on time, got %v", state)
+	}
+	history, err := client.GetGameHistory(redCtx, &pb.GameID{Id: resp.Id})
+	if err != nil {
+		t.Fatalf("Failed to get the history: %v", err)
+	}
+	if b := history.GetBoards()[0]; len(b.GetMoves()) != 1 || b.GetEndReason() != pb.EndReason_timeout {
+		t.Fatalf("expected the timeout in the history, got %v", b)
+	}
+
+	// the next board starts with fresh clocks
+	state = rematch(t, resp.Id, red, yellow)
+	if state.GetResult() != pb.Result_in_progress || state.GetClocks().GetRed().AsDuration() <= 100*time.Millisecond {
+		t.Fatalf("expected a new board with fresh clocks, got %v", state)
+	}
+}
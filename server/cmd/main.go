@@ -1,7 +1,25 @@
 package main
 
-import "github.com/geofpwhite/connect4-grpc/server"
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"fortio.org/log"
+	"github.com/geofpwhite/connect4-grpc/server"
+)
 
 func main() {
-	server.Serve()
+	cfg, err := server.LoadConfig(os.Args[1:], os.Getenv)
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		return
+	case err != nil:
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := server.Serve(cfg); err != nil {
+		log.Fatalf("server failed: %v", err)
+	}
 }
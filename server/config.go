@@ -0,0 +1,216 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"fortio.org/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
+)
+
+// envPrefix starts the environment variables setting the flags, -tls-cert being
+// CONNECT4_TLS_CERT for instance.
+const envPrefix = "CONNECT4_"
+
+// Duration is a time.Duration written like "30s" in config files.
+type Duration time.Duration
+
+func (d Duration) String() string { return time.Duration(d).String() }
+
+func (d Duration) MarshalText() ([]byte, error) { return []byte(d.String()), nil }
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	*d = Duration(parsed)
+	return err
+}
+
+// Set makes Duration a flag.Value.
+func (d *Duration) Set(s string) error { return d.UnmarshalText([]byte(s)) }
+
+// Config is how the server runs. It is read from a JSON file, then the environment, then the
+// flags, each overriding the previous ones.
+type Config struct {
+	Listen   string `json:"listen"`
+	TLSCert  string `json:"tls_cert,omitempty"`
+	TLSKey   string `json:"tls_key,omitempty"`
+	ClientCA string `json:"client_ca,omitempty"` // clients must present a certificate it signed
+	MaxGames int    `json:"max_games,omitempty"` // live games at once, 0 for no limit
+	// the server pings clients idle for KeepaliveTime, closing the connection without an answer
+	// within KeepaliveTimeout, and closes the ones pinging more often than KeepaliveMinPing
+	KeepaliveTime    Duration `json:"keepalive_time"`
+	KeepaliveTimeout Duration `json:"keepalive_timeout"`
+	KeepaliveMinPing Duration `json:"keepalive_min_ping"`
+	LogLevel         string   `json:"log_level"`
+	Grace            Duration `json:"grace"`         // how long the seat of a disconnected player is kept
+	DrainTimeout     Duration `json:"drain_timeout"` // how long a shutdown waits for the boards in play
+	UsersFile        string   `json:"users_file"`
+	GamesFile        string   `json:"games_file"`
+	SessionKeyFile   string   `json:"session_key_file"`    // created on the first start
+	RateBots         bool     `json:"rate_bots,omitempty"` // bot games may be rated
+}
+
+// DefaultConfig is the configuration without file, environment or flags.
+func DefaultConfig() Config {
+	return Config{
+		Listen:           "0.0.0.0:50051",
+		KeepaliveTime:    Duration(time.Minute),
+		KeepaliveTimeout: Duration(20 * time.Second),
+		KeepaliveMinPing: Duration(10 * time.Second),
+		LogLevel:         "Info",
+		Grace:            Duration(defaultGrace),
+		DrainTimeout:     Duration(time.Minute),
+		UsersFile:        usersFile,
+		GamesFile:        gamesFile,
+		SessionKeyFile:   sessionKeyFile,
+	}
+}
+
+// LoadConfig reads the configuration from the file given by -config, the environment read with
+// getenv and the command line args, and validates it.
+func LoadConfig(args []string, getenv func(string) string) (Config, error) {
+	cfg := DefaultConfig()
+	fs := flag.NewFlagSet("connect4-server", flag.ContinueOnError)
+	path := fs.String("config", getenv(envPrefix+"CONFIG"), "JSON file of settings, overridden by $"+envPrefix+"* variables and flags")
+	fs.StringVar(&cfg.Listen, "listen", cfg.Listen, "address to listen on")
+	fs.StringVar(&cfg.TLSCert, "tls-cert", cfg.TLSCert, "certificate file to serve TLS with, plaintext without")
+	fs.StringVar(&cfg.TLSKey, "tls-key", cfg.TLSKey, "private key file of -tls-cert")
+	fs.StringVar(&cfg.ClientCA, "client-ca", cfg.ClientCA,
+		"CA file the certificates clients must present are checked against (mutual TLS)")
+	fs.IntVar(&cfg.MaxGames, "max-games", cfg.MaxGames, "most games played at once, 0 for no limit")
+	fs.Var(&cfg.KeepaliveTime, "keepalive-time", "ping clients idle for that long")
+	fs.Var(&cfg.KeepaliveTimeout, "keepalive-timeout", "close connections not answering a ping within that time")
+	fs.Var(&cfg.KeepaliveMinPing, "keepalive-min-ping", "close connections of clients pinging more often than that")
+	fs.StringVar(&cfg.LogLevel, "log-level", cfg.LogLevel,
+		"least severe messages logged: Debug, Verbose, Info, Warning, Error, Critical or Fatal")
+	fs.Var(&cfg.Grace, "grace", "how long the seat of a disconnected player is kept for them to resume")
+	fs.Var(&cfg.DrainTimeout, "drain-timeout",
+		"how long SIGINT or SIGTERM waits for the boards in play to end before saving the games and stopping")
+	fs.StringVar(&cfg.UsersFile, "users-file", cfg.UsersFile, "file of the accounts")
+	fs.StringVar(&cfg.GamesFile, "games-file", cfg.GamesFile, "database of the games")
+	fs.StringVar(&cfg.SessionKeyFile, "session-key-file", cfg.SessionKeyFile,
+		"key signing the session tokens, created if missing, so they outlive restarts")
+	fs.BoolVar(&cfg.RateBots, "rate-bots", cfg.RateBots, "let bot games be rated, the bots having ratings of their own")
+	// the flags are parsed once to find the file, then again to override it
+	if err := fs.Parse(args); err != nil {
+		return cfg, err
+	}
+	if *path != "" {
+		if err := cfg.read(*path); err != nil {
+			return cfg, err
+		}
+	}
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		name := envPrefix + strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_"))
+		if v := getenv(name); v != "" && f.Name != "config" && err == nil {
+			if setErr := fs.Set(f.Name, v); setErr != nil {
+				err = fmt.Errorf("invalid $%s: %w", name, setErr)
+			}
+		}
+	})
+	if err != nil {
+		return cfg, err
+	}
+	if err := fs.Parse(args); err != nil {
+		return cfg, err
+	}
+	return cfg, cfg.validate()
+}
+
+func (c *Config) read(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(c); err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	return nil
+}
+
+func (c Config) validate() error {
+	var errs []error
+	if _, _, err := net.SplitHostPort(c.Listen); err != nil {
+		errs = append(errs, fmt.Errorf("listen: %w", err))
+	}
+	if (c.TLSCert == "") != (c.TLSKey == "") {
+		errs = append(errs, errors.New("tls-cert and tls-key go together"))
+	}
+	if c.ClientCA != "" && c.TLSCert == "" {
+		errs = append(errs, errors.New("client-ca needs tls-cert and tls-key"))
+	}
+	if c.MaxGames < 0 {
+		errs = append(errs, fmt.Errorf("max-games must not be negative, got %d", c.MaxGames))
+	}
+	positive := map[string]Duration{"keepalive-time": c.KeepaliveTime, "keepalive-timeout": c.KeepaliveTimeout, "grace": c.Grace}
+	for name, d := range positive {
+		if d <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive, got %v", name, d))
+		}
+	}
+	for name, d := range map[string]Duration{"keepalive-min-ping": c.KeepaliveMinPing, "drain-timeout": c.DrainTimeout} {
+		if d < 0 {
+			errs = append(errs, fmt.Errorf("%s must not be negative, got %v", name, d))
+		}
+	}
+	if _, err := log.ValidateLevel(c.LogLevel); err != nil {
+		errs = append(errs, fmt.Errorf("log-level %q %w", c.LogLevel, err))
+	}
+	if c.UsersFile == "" || c.GamesFile == "" || c.SessionKeyFile == "" {
+		errs = append(errs, errors.New("users-file, games-file and session-key-file must not be empty"))
+	}
+	return errors.Join(errs...)
+}
+
+func (c Config) String() string {
+	data, _ := json.Marshal(c) // only plain fields
+	return string(data)
+}
+
+// serverOptions returns the transport options of c, loading the TLS files.
+func (c Config) serverOptions() ([]grpc.ServerOption, error) {
+	opts := []grpc.ServerOption{
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    time.Duration(c.KeepaliveTime),
+			Timeout: time.Duration(c.KeepaliveTimeout),
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             time.Duration(c.KeepaliveMinPing),
+			PermitWithoutStream: true, // clients keep their connection while in the lobby
+		}),
+	}
+	if c.TLSCert == "" {
+		return opts, nil
+	}
+	cert, err := tls.LoadX509KeyPair(c.TLSCert, c.TLSKey)
+	if err != nil {
+		return nil, fmt.Errorf("loading the TLS certificate: %w", err)
+	}
+	conf := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if c.ClientCA != "" {
+		pem, err := os.ReadFile(c.ClientCA)
+		if err != nil {
+			return nil, fmt.Errorf("reading the client CA: %w", err)
+		}
+		conf.ClientCAs = x509.NewCertPool()
+		if !conf.ClientCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in %s", c.ClientCA)
+		}
+		conf.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return append(opts, grpc.Creds(credentials.NewTLS(conf))), nil
+}
@@ -0,0 +1,225 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/geofpwhite/connect4-grpc/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func env(vars map[string]string) func(string) string {
+	return func(name string) string { return vars[name] }
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.json")
+	file := `{"listen": "127.0.0.1:6000", "max_games": 10, "grace": "1m", "log_level": "Warning"}`
+	if err := os.WriteFile(path, []byte(file), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig([]string{"-grace", "5s"}, env(map[string]string{
+		"CONNECT4_CONFIG":    path,
+		"CONNECT4_MAX_GAMES": "20",
+		"CONNECT4_GRACE":     "2m",
+		"CONNECT4_RATE_BOTS": "true",
+	}))
+	if err != nil {
+		t.Fatalf("Failed to load the config: %v", err)
+	}
+	want := DefaultConfig()
+	want.Listen, want.LogLevel = "127.0.0.1:6000", "Warning" // from the file
+	want.MaxGames, want.RateBots = 20, true                  // the environment overriding the file
+	want.Grace = Duration(5 * time.Second)                   // the flags overriding both
+	if cfg != want {
+		t.Fatalf("expected\n%v\ngot\n%v", want, cfg)
+	}
+}
+
+func TestConfigRefusals(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.json")
+	if err := os.WriteFile(path, []byte(`{"listen_on": ":6000"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		args []string
+		env  map[string]string
+		want string
+	}{
+		{args: []string{"-listen", "50051"}, want: "listen"},
+		{args: []string{"-tls-cert", "cert.pem"}, want: "tls-cert and tls-key"},
+		{args: []string{"-client-ca", "ca.pem"}, want: "client-ca needs"},
+		{args: []string{"-max-games", "-1"}, want: "max-games"},
+		{args: []string{"-keepalive-time", "0s"}, want: "keepalive-time"},
+		{args: []string{"-drain-timeout", "-1s"}, want: "drain-timeout"},
+		{args: []string{"-grace", "soon"}, want: "invalid value"},
+		{args: []string{"-log-level", "loud"}, want: "log-level"},
+		{env: map[string]string{"CONNECT4_MAX_GAMES": "many"}, want: "CONNECT4_MAX_GAMES"},
+		{args: []string{"-config", path}, want: "unknown field"},
+		{args: []string{"-config", filepath.Join(t.TempDir(), "missing.json")}, want: "no such file"},
+	} {
+		_, err := LoadConfig(tc.args, env(tc.env))
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%v %v: expected an error about %q, got %v", tc.args, tc.env, tc.want, err)
+		}
+	}
+}
+
+// authority is a CA issuing certificates for the tests.
+type authority struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newAuthority(t *testing.T) *authority {
+	t.Helper()
+	ca := &authority{cert: &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}}
+	_, ca.key = ca.issue(t, ca.cert)
+	return ca
+}
+
+// issue signs template, returning the certificate as PEM and its key. The CA signs itself when
+// template is its own certificate.
+func (ca *authority) issue(t *testing.T, template *x509.Certificate) ([]byte, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent, signer := ca.cert, ca.key
+	if template == ca.cert {
+		signer = key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if template == ca.cert {
+		ca.cert, _ = x509.ParseCertificate(der)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), key
+}
+
+// leaf writes a certificate of ca for name and its key to dir, returning their paths.
+func (ca *authority) leaf(t *testing.T, dir, name string, usage x509.ExtKeyUsage) (string, string) {
+	t.Helper()
+	certPEM, key := ca.issue(t, &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	})
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile, keyFile := filepath.Join(dir, name+".pem"), filepath.Join(dir, name+"-key.pem")
+	if err := os.WriteFile(certFile, certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newAuthority(t)
+	caFile := filepath.Join(dir, "ca.pem")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := DefaultConfig()
+	cfg.TLSCert, cfg.TLSKey = ca.leaf(t, dir, "connect4.test", x509.ExtKeyUsageServerAuth)
+	cfg.ClientCA = caFile
+	opts, err := cfg.serverOptions()
+	if err != nil {
+		t.Fatalf("Failed to build the server options: %v", err)
+	}
+	cs := newServer()
+	lis := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer(append(opts, cs.interceptors()...)...)
+	pb.RegisterConnect4Server(grpcServer, cs)
+	go func() {
+		_ = grpcServer.Serve(lis)
+	}()
+	t.Cleanup(grpcServer.Stop)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	dial := func(certs []tls.Certificate) error {
+		creds := credentials.NewTLS(&tls.Config{
+			RootCAs: roots, Certificates: certs, ServerName: "connect4.test", MinVersion: tls.VersionTLS12,
+		})
+		conn, err := grpc.NewClient("passthrough:///bufnet",
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+			grpc.WithTransportCredentials(creds))
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err = pb.NewConnect4Client(conn).Guest(ctx, &pb.Empty{})
+		return err
+	}
+	if err := dial(nil); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected a client without certificate to be refused, got %v", err)
+	}
+	certFile, keyFile := ca.leaf(t, dir, "player", x509.ExtKeyUsageClientAuth)
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dial([]tls.Certificate{cert}); err != nil {
+		t.Fatalf("expected a client with a certificate of the CA to be served, got %v", err)
+	}
+}
+
+func TestMaxGames(t *testing.T) {
+	cs := newServer()
+	cs.games.limit = 1
+	client := serve(t, cs)
+	ctx := guest(t, client)
+	resp, err := client.NewGame(ctx, &pb.NewGameRequest{})
+	if err != nil {
+		t.Fatalf("Failed to start a new game: %v", err)
+	}
+	if _, err := client.NewGame(ctx, &pb.NewGameRequest{}); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted past the limit, got %v", err)
+	}
+	if _, err := client.LeaveGame(ctx, resp); err != nil {
+		t.Fatalf("Failed to leave the game: %v", err)
+	}
+	if _, err := client.NewGame(ctx, &pb.NewGameRequest{}); err != nil {
+		t.Fatalf("expected a game to be allowed once the other ended, got %v", err)
+	}
+}
@@ -3,106 +3,243 @@ package server
 import (
 	"context"
 	"errors"
+	"expvar"
 	"fmt"
 	"io"
-	"log"
-	"math/rand/v2"
 	"net"
 	"os"
+	"os/signal"
+	"runtime"
 	"sync"
+	"syscall"
+	"time"
 
+	"fortio.org/log"
+	"github.com/geofpwhite/connect4-grpc/bot"
+	"github.com/geofpwhite/connect4-grpc/engine"
 	"github.com/geofpwhite/connect4-grpc/pb"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
-type field [8][8]pb.Team
+var (
+	errGameNotFound = status.Error(codes.NotFound, "game does not exist")
+	errGameFull     = status.Error(codes.FailedPrecondition, "game is full")
+	errNotYourTurn  = errors.New("not your turn")
+	errWrongGame    = errors.New("input is for another game")
+	errWrongTeam    = errors.New("input is for the seat of another team")
+	errMatchOver    = errors.New("match is over")
+	errBotSeat      = status.Error(codes.FailedPrecondition, "seat is played by the bot")
+	errNameTooLong  = status.Errorf(codes.InvalidArgument, "player name is longer than %d characters", maxNameLength)
+	errBadToken     = status.Error(codes.PermissionDenied, "invalid resume token for this seat")
+	errSeatResumed  = status.Error(codes.Aborted, "seat resumed by another connection")
+)
+
+const (
+	maxNameLength = 32
+	// defaultGrace is how long the seat of a disconnected player is kept for them to resume.
+	defaultGrace   = 30 * time.Second
+	usersFile      = "users.json"
+	gamesFile      = "games.db"
+	sessionKeyFile = "session.key"
+)
 
 type game struct {
-	turn                    pb.Team
-	state                   field
-	mut                     *sync.RWMutex
-	red                     bool // true if player1 is connect
-	yellow                  bool // true if player2 is connected
+	variant                 engine.Variant
+	board                   *engine.Board
+	boards                  [][]MoveRecord // moves of each board of the series, the last one in play
+	starts                  []engine.Piece // who moved first on each board
+	next                    engine.Piece   // who moves first on the next board, empty while the board is in play
+	firstPlayer             pb.FirstPlayer
+	mut                     *sync.RWMutex // held by the actor while it writes, by other goroutines to read
+	red                     bool          // true if player1 is connect
+	yellow                  bool          // true if player2 is connected
 	redWins, yellowWins     int
-	redStream, yellowStream grpc.BidiStreamingServer[pb.Input, pb.State]
+	draws                   int
+	firstTo                 int // match length, 0 for an endless series
+	bot                     *botSeat
+	redStream, yellowStream *subscriber[*pb.State]
+	redName, yellowName     string
+	redToken, yellowToken   string      // resume tokens, empty while the seat is free
+	redUser, yellowUser     string      // usernames of the sessions holding the seats
+	redMuted, yellowMuted   bool        // the seat doesn't get the chat of the opponent
+	redGrace, yellowGrace   *time.Timer // releases the seat of a disconnected player
+	spectators              map[*subscriber[*pb.State]]struct{}
+	inbox                   chan command  // commands for the actor
+	done                    chan struct{} // closed once the game is deleted
+	stopped                 chan struct{} // closed once the actor returned
+	host                    string        // name of the player who created the game
+	createdAt               time.Time
+	private                 bool   // hidden from the lobby
+	clock                   *clock // nil for untimed games
+	pending                 *offer // waiting for an answer of the opponent
+	rated                   bool
+	ratedBoards             int               // boards whose result changed the ratings, or was dropped
+	ratingChanges           *pb.RatingChanges // of the last board, while it is shown
+	shutdownAt              time.Time         // when the server stops, zero unless it is shutting down
 }
 
 type connect4Server struct {
-	games map[int32]*game
+	games    *registry
+	lobbyMut sync.Mutex
+	watchers map[*lobbyWatcher]struct{}
+	queueMut sync.Mutex
+	queue    []*ticket // players waiting for a match, oldest first
+	grace    time.Duration
+	users    *userStore
+	sessions sessions
+	store    GameStore
+	saveMut  sync.Mutex
+	rateBots bool // bot games may be rated, the bots having ratings of their own
+	stats    queueStats
+	draining chan struct{} // closed once the server is shutting down
+	analyses chan struct{} // holds a token per analysis running
 	pb.UnimplementedConnect4Server
 }
 
+// newServer returns a server keeping its accounts and games in memory.
 func newServer() *connect4Server {
+	users, _ := loadUsers("") // no file to read
 	return &connect4Server{
-		games: make(map[int32]*game),
+		games:    newRegistry(0),
+		watchers: make(map[*lobbyWatcher]struct{}),
+		grace:    defaultGrace,
+		users:    users,
+		sessions: newSessions(),
+		store:    newMemoryStore(),
+		draining: make(chan struct{}),
+		analyses: make(chan struct{}, runtime.GOMAXPROCS(0)),
 	}
 }
 
-func (cs *connect4Server) update(id int32, s *pb.State) error {
-	g, exists := cs.games[id]
-	if !exists {
-		return errors.New("game does not exist")
-	}
-	wg := &sync.WaitGroup{}
-	wg.Add(2)
-	go func() {
-		if g.yellowStream != nil {
-			if err := g.yellowStream.Send(s); err != nil {
-				fmt.Fprintln(os.Stderr, "yellow stream can't send")
-			}
-		}
-		wg.Done()
-	}()
-	go func() {
-		if g.redStream != nil {
-			if err := g.redStream.Send(s); err != nil {
-				fmt.Fprintln(os.Stderr, "red stream can't send")
-			}
+// update sends s to the players and spectators of g. Runs on the actor of g.
+func (g *game) update(s *pb.State) {
+	g.mut.RLock()
+	subscribers := g.receivers()
+	g.mut.RUnlock()
+	broadcast(subscribers, s)
+}
+
+// receivers returns the subscribers of the players and spectators of g. Called with g.mut held.
+func (g *game) receivers() []*subscriber[*pb.State] {
+	subscribers := make([]*subscriber[*pb.State], 0, 2+len(g.spectators))
+	for _, sub := range []*subscriber[*pb.State]{g.redStream, g.yellowStream} {
+		if sub != nil {
+			subscribers = append(subscribers, sub)
 		}
-		wg.Done()
-	}()
-	wg.Wait()
-	return nil
+	}
+	for spectator := range g.spectators {
+		subscribers = append(subscribers, spectator)
+	}
+	return subscribers
 }
 
-func (cs *connect4Server) JoinGame(_ context.Context, id *pb.GameID) (*pb.GameIDAndTeam, error) {
-	if game, exists := cs.games[id.GetId()]; exists {
-		game.mut.RLock()
-		if game.red && game.yellow {
-			game.mut.RUnlock()
-			return nil, errors.New("game is full")
+func (cs *connect4Server) JoinGame(ctx context.Context, id *pb.JoinGameRequest) (*pb.GameIDAndTeam, error) {
+	if len(id.GetPlayerName()) > maxNameLength {
+		return nil, errNameTooLong
+	}
+	game, exists := cs.games.get(id.GetId())
+	if !exists {
+		return nil, errGameNotFound
+	}
+	var seat *pb.GameIDAndTeam
+	err := game.do(func() error {
+		var err error
+		if seat, err = game.join(ctx, id); err != nil {
+			return err
 		}
-		red, yellow := game.red, game.yellow
-		game.mut.RUnlock()
 		game.mut.Lock()
-		defer game.mut.Unlock()
-		if !red {
-			game.red = true
-			return &pb.GameIDAndTeam{Id: id.Id, Team: pb.Team_red.Enum()}, nil
-		} else if !yellow {
-			game.yellow = true
-			return &pb.GameIDAndTeam{Id: id.Id, Team: pb.Team_yellow.Enum()}, nil
-		}
+		cs.holdSeat(id.GetId(), game, seat.GetTeam())
+		game.mut.Unlock()
+		cs.save(id.GetId(), game)
+		cs.lobbyChanged(id.GetId())
+		return nil
+	})
+	return seat, err
+}
+
+// join gives a free seat of g to the caller. Runs on the actor of g.
+func (g *game) join(ctx context.Context, id *pb.JoinGameRequest) (*pb.GameIDAndTeam, error) {
+	g.mut.Lock()
+	defer g.mut.Unlock()
+	if g.red && g.yellow {
+		return nil, errGameFull
 	}
-	return nil, errors.New("game does not exist")
+	if g.rated && isGuest(caller(ctx)) {
+		return nil, errGuestRated
+	}
+	token := newToken()
+	team := pb.Team_red
+	if !g.red {
+		g.red = true
+		g.redName = playerName(ctx, id.GetPlayerName())
+		g.redToken, g.redUser = token, caller(ctx)
+	} else {
+		team = pb.Team_yellow
+		g.yellow = true
+		g.yellowName = playerName(ctx, id.GetPlayerName())
+		g.yellowToken, g.yellowUser = token, caller(ctx)
+	}
+	return &pb.GameIDAndTeam{Id: id.Id, Team: team.Enum(), Variant: pb.NewVariant(g.variant), ResumeToken: &token}, nil
 }
 
-func (cs *connect4Server) LeaveGame(_ context.Context, idAndTeam *pb.GameIDAndTeam) (*pb.Empty, error) {
-	if game, exists := cs.games[idAndTeam.GetId()]; exists {
-		game.mut.Lock()
-		if idAndTeam.GetTeam().Enum() == pb.Team_yellow.Enum() {
-			game.yellowStream = nil
-			game.yellow = false
-		} else {
-			game.redStream = nil
-			game.red = false
+func (cs *connect4Server) LeaveGame(ctx context.Context, idAndTeam *pb.GameIDAndTeam) (*pb.Empty, error) {
+	game, exists := cs.games.get(idAndTeam.GetId())
+	if !exists {
+		return nil, errGameNotFound
+	}
+	if err := game.do(func() error { return cs.leave(ctx, idAndTeam, game) }); err != nil {
+		return nil, err
+	}
+	return &pb.Empty{}, nil
+}
+
+// leave frees the seat of idAndTeam, deleting game once no player is left. Runs on the actor of
+// game.
+func (cs *connect4Server) leave(ctx context.Context, idAndTeam *pb.GameIDAndTeam, game *game) error {
+	game.mut.Lock()
+	if idAndTeam.GetTeam() == pb.Team_yellow {
+		if !game.yellow || idAndTeam.GetResumeToken() != game.yellowToken {
+			game.mut.Unlock()
+			return errBadToken
 		}
-		game.mut.Unlock()
-		if !game.yellow && !game.red {
-			delete(cs.games, idAndTeam.GetId())
+		if game.yellowUser != caller(ctx) {
+			game.mut.Unlock()
+			return errNotSeatOwner
+		}
+		stopGrace(game.yellowGrace)
+		game.yellowStream = nil
+		game.yellow = false
+		game.yellowName, game.yellowToken, game.yellowUser, game.yellowMuted = "", "", "", false
+	} else {
+		if !game.red || idAndTeam.GetResumeToken() != game.redToken {
+			game.mut.Unlock()
+			return errBadToken
 		}
+		if game.redUser != caller(ctx) {
+			game.mut.Unlock()
+			return errNotSeatOwner
+		}
+		stopGrace(game.redGrace)
+		game.redStream = nil
+		game.red = false
+		game.redName, game.redToken, game.redUser, game.redMuted = "", "", "", false
 	}
-	return &pb.Empty{}, nil
+	game.stopClock(time.Now())
+	abandoned := game.abandoned()
+	if abandoned {
+		close(game.done)
+	}
+	game.mut.Unlock()
+	if abandoned {
+		cs.games.remove(idAndTeam.GetId(), game)
+		cs.finish(idAndTeam.GetId(), game)
+	} else {
+		cs.save(idAndTeam.GetId(), game)
+	}
+	cs.lobbyChanged(idAndTeam.GetId())
+	return nil
 }
 
 func (cs *connect4Server) CommunicateState(stream grpc.BidiStreamingServer[pb.Input, pb.State]) error {
@@ -113,184 +250,476 @@ func (cs *connect4Server) CommunicateState(stream grpc.BidiStreamingServer[pb.In
 	if err != nil {
 		return err
 	}
-	game, exists := cs.games[input.GetGameId()]
+	id := input.GetGameId()
+	game, exists := cs.games.get(id)
 	if !exists {
-		return nil
+		return errGameNotFound
 	}
-	if *input.GetInputTeam().Enum() == *pb.Team_yellow.Enum() {
-		game.mut.Lock()
-		game.yellowStream = stream
-
-		game.mut.Unlock()
-		defer func() {
-			game.mut.Lock()
-			game.yellowStream = nil
-			game.yellow = false
-			game.mut.Unlock()
-		}()
-	} else {
-		game.mut.Lock()
-		game.redStream = stream
-		game.mut.Unlock()
-		defer func() {
-			game.mut.Lock()
-			game.redStream = nil
-			game.red = false
-			game.mut.Unlock()
-		}()
+	if input.GetInputTeam() != pb.Team_red && input.GetInputTeam() != pb.Team_yellow {
+		return status.Errorf(codes.InvalidArgument, "can't play as %v", input.GetInputTeam())
+	}
+	if game.bot != nil && game.bot.team == input.GetInputTeam() {
+		return errBotSeat
+	}
+	team, user, token := input.GetInputTeam(), caller(stream.Context()), input.GetResumeToken()
+	sub := subscribe(&cs.stats, team.String(), stateQueue, stream.Send, supersededState)
+	defer sub.close()
+	defer game.exec(func() { cs.detach(id, game, team, sub) }) // does nothing unless attached
+	err = game.do(func() error {
+		if err := game.attach(team, user, token, sub); err != nil {
+			return err
+		}
+		// send the current board right away so a player joining mid game or resuming sees it
+		sub.push(game.pbState())
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
+	inputs := receive(stream)
 	for {
-		input, err := stream.Recv()
-		if errors.Is(err, io.EOF) {
+		var r received
+		select {
+		case r = <-inputs:
+		case <-sub.done():
+			return sub.Err()
+		}
+		if errors.Is(r.err, io.EOF) {
 			return nil
 		}
-		fmt.Println(input)
-		if err != nil {
-			return err
+		if r.err != nil {
+			return r.err
 		}
-		game, exists := cs.games[input.GetGameId()]
-		if !exists {
-			return nil
+		log.Debugf("game %d, %v: %v", id, team, r.input)
+		if err := game.do(func() error { cs.handle(id, game, team, sub, r.input); return nil }); err != nil {
+			return err
 		}
-		game.modifyState(input.GetColumn(), input.GetInputTeam())
-		s := &pb.State{}
-		s.Turn = &game.turn
-		field := pb.Field{Rows: []*pb.Row{}}
-
-		for i := range game.state {
-			field.Rows = append(field.Rows, &pb.Row{Values: make([]pb.Team, 8)})
-			for j := range game.state {
-				field.Rows[i].Values[j] = game.state[i][j]
+		game.wakeBot()
+	}
+}
+
+// received is an input of a stream, or why there are no more.
+type received struct {
+	input *pb.Input
+	err   error
+}
+
+// receive reads the inputs of stream on a goroutine of its own, so the handler can also wait for
+// its subscriber to be evicted.
+func receive(stream grpc.BidiStreamingServer[pb.Input, pb.State]) <-chan received {
+	inputs := make(chan received)
+	go func() {
+		for {
+			input, err := stream.Recv()
+			select {
+			case inputs <- received{input, err}:
+			case <-stream.Context().Done():
+				return
+			}
+			if err != nil {
+				return
 			}
 		}
-		s.Field = &field
-		err = cs.update(input.GetGameId(), s)
-		if err != nil {
-			return err
+	}()
+	return inputs
+}
+
+// handle carries out input of the player of team, sending the new state to everyone or the refusal
+// of input to sub only. Runs on the actor of g.
+func (cs *connect4Server) handle(id int32, g *game, team pb.Team, sub *subscriber[*pb.State], input *pb.Input) {
+	g.mut.RLock()
+	current := g.seatStream(team)
+	g.mut.RUnlock()
+	if sub != current {
+		// the player resumed the seat on another connection, which alone speaks for them now
+		go sub.end(errSeatResumed)
+		return
+	}
+	var err error
+	switch {
+	case input.GetGameId() != id:
+		err = errWrongGame
+	case input.GetInputTeam() != team:
+		err = errWrongTeam
+	case isChat(input):
+		if err = g.chat(team, input); err == nil {
+			return
+		}
+	default:
+		if err = g.act(input, team); err == nil {
+			cs.rate(g)
 		}
 	}
+	if err != nil {
+		s := g.pbState()
+		s.Rejection = rejectionOf(err, input.GetColumn())
+		sub.push(s)
+		return
+	}
+	cs.save(id, g)
+	g.update(g.pbState())
 }
 
-func (cs *connect4Server) NewGame(_ context.Context, _ *pb.Empty) (*pb.GameIDAndTeam, error) {
-	id := rand.Int32() //nolint: gosec // it's just the game id
-	_, exists := cs.games[id]
-	for exists {
-		id = rand.Int32() //nolint: gosec // it's just the game id
-		_, exists = cs.games[id]
+func (cs *connect4Server) NewGame(ctx context.Context, req *pb.NewGameRequest) (*pb.GameIDAndTeam, error) {
+	g, err := cs.newGame(ctx, req)
+	if err != nil {
+		return nil, err
 	}
-	cs.games[id] = &game{
-		mut:   &sync.RWMutex{},
-		state: field{},
-		red:   true,
-		turn:  1,
+	token := g.redToken
+	id, err := cs.addGame(g)
+	if err != nil {
+		return nil, err
 	}
-	return &pb.GameIDAndTeam{Id: &id, Team: pb.Team_red.Enum()}, nil
+	return &pb.GameIDAndTeam{Id: &id, Team: pb.Team_red.Enum(), Variant: pb.NewVariant(g.variant), ResumeToken: &token}, nil
 }
 
-func (g *game) modifyState(column int32, inputTeam pb.Team) {
-	g.mut.RLock()
-	if g.state[7][int(column-1)] != 0 || inputTeam != g.turn {
-		g.mut.RUnlock()
-		return
+// newGame validates req and returns the game it asks for, the caller holding the red seat.
+func (cs *connect4Server) newGame(ctx context.Context, req *pb.NewGameRequest) (*game, error) {
+	if len(req.GetPlayerName()) > maxNameLength {
+		return nil, errNameTooLong
 	}
-	g.mut.RUnlock()
+	switch {
+	case req.GetRated() && isGuest(caller(ctx)):
+		return nil, errGuestRated
+	case req.GetRated() && req.GetOpponent() == pb.Opponent_bot && !cs.rateBots:
+		return nil, errBotRated
+	}
+	variant := req.GetVariant().Engine()
+	board, err := engine.New(variant)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.GetFirstTo() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "first_to must not be negative, got %d", req.GetFirstTo())
+	}
+	control, err := timeControlOf(req.GetTimeControl())
+	if err != nil {
+		return nil, err
+	}
+	var seat *botSeat
+	if req.GetOpponent() == pb.Opponent_bot {
+		level := int(req.GetBotLevel())
+		if level == 0 {
+			level = bot.DefaultLevel
+		}
+		player, err := bot.New(level)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		seat = newBotSeat(pb.Team_yellow, player)
+	}
+	yellowName := ""
+	if seat != nil {
+		yellowName = fmt.Sprintf("bot level %d", seat.player.Level())
+	}
+	name := playerName(ctx, req.GetPlayerName())
+	return &game{
+		variant:     variant,
+		board:       board,
+		red:         true,
+		yellow:      seat != nil,
+		firstTo:     int(req.GetFirstTo()),
+		bot:         seat,
+		redName:     name,
+		yellowName:  yellowName,
+		redToken:    newToken(),
+		redUser:     caller(ctx),
+		host:        name,
+		private:     req.GetPrivate(),
+		clock:       newClock(control),
+		firstPlayer: req.GetFirstPlayer(),
+		rated:       req.GetRated(),
+	}, nil
+}
+
+// addGame registers g under a new random id and returns it, unless the server has too many games.
+// Its players have the grace period to attach.
+func (cs *connect4Server) addGame(g *game) (int32, error) {
+	id, err := cs.games.reserve(cs.stored)
+	if err != nil {
+		return 0, err
+	}
+	g.setup()
+	g.createdAt = time.Now()
+	if g.boards == nil {
+		g.boards, g.starts = [][]MoveRecord{nil}, []engine.Piece{engine.Red}
+	}
+	cs.armClock(id, g) // started once a player attaches
+	cs.holdSeats(id, g)
+	cs.save(id, g)
+	cs.games.put(id, g)
+	cs.startBot(id, g)
+	cs.lobbyChanged(id)
+	return id, nil
+}
+
+// stored reports whether id is used by a game of the store, finished ones included.
+func (cs *connect4Server) stored(id int32) bool {
+	_, err := cs.store.Load(id)
+	return !errors.Is(err, ErrNotStored)
+}
+
+// setup initializes the fields every game needs and starts its actor.
+func (g *game) setup() {
+	g.mut = &sync.RWMutex{}
+	g.spectators = make(map[*subscriber[*pb.State]]struct{})
+	g.inbox = make(chan command)
+	g.done = make(chan struct{})
+	g.stopped = make(chan struct{})
+	go g.loop()
+}
+
+// modifyState drops a disc of inputTeam in the 1 based column. Runs on the actor of g.
+func (g *game) modifyState(column int32, inputTeam pb.Team) error {
 	g.mut.Lock()
-	g.state[7][column-1] = inputTeam
-	g.state = fall(g.state, column-1)
-	g.turn = (g.turn % 2) + 1
-	g.mut.Unlock()
-	g.mut.RLock()
-	ended := scan(g.state, inputTeam)
+	defer g.mut.Unlock()
+	if g.matchWinner() != pb.Team_empty {
+		return errMatchOver
+	}
+	if g.over() {
+		// the finished board stays up until the players agree on a rematch
+		return errBoardOver
+	}
+	now := time.Now()
+	if g.outOfTime(now) {
+		g.forfeit(now)
+		return nil
+	}
+	if engine.Piece(inputTeam) != g.board.Turn() {
+		return errNotYourTurn
+	}
+	return g.play(int(column)-1, now)
+}
 
-	g.mut.RUnlock()
+// play drops a disc of the side to move in the 0 based column. Called with g.mut held.
+func (g *game) play(column int, at time.Time) error {
+	return g.apply(MoveRecord{Column: column, Mover: g.board.Turn(), At: at})
+}
 
-	if !ended {
-		return
+// apply records m on the board in play, counting the result if it ends the board. Called with
+// g.mut held.
+func (g *game) apply(m MoveRecord) error {
+	if m.End == "" {
+		if _, err := g.board.Drop(m.Column); err != nil {
+			return err
+		}
 	}
-	g.mut.Lock()
-	g.state = field{}
-	if inputTeam == *pb.Team_yellow.Enum() {
-		g.yellowWins++
-	} else {
+	last := len(g.boards) - 1
+	g.boards[last] = append(g.boards[last], m)
+	g.pending = nil // moving withdraws the offers
+	g.tick(m)
+	switch g.result() {
+	case pb.Result_red_won:
 		g.redWins++
+	case pb.Result_yellow_won:
+		g.yellowWins++
+	case pb.Result_draw:
+		g.draws++
+	case pb.Result_in_progress:
+		return nil
 	}
-	g.turn = pb.Team_red
-	g.mut.Unlock()
+	g.next = g.nextFirst()
+	return nil
+}
 
+// ending returns the record that ended the board in play early, nil if there is none.
+func (g *game) ending() *MoveRecord {
+	moves := g.boards[len(g.boards)-1]
+	if len(moves) > 0 && moves[len(moves)-1].End != "" {
+		return &moves[len(moves)-1]
+	}
+	return nil
 }
 
-func fall(state field, column int32) field {
-	for i := range 8 {
-		if state[i][column] != 0 {
-			for j := i; j > 0 && state[j-1][column] == 0; j-- {
-				state[j-1][column], state[j][column] = state[j][column], 0
-			}
+func (g *game) over() bool {
+	return g.board.IsOver() || g.ending() != nil
+}
+
+func (g *game) result() pb.Result {
+	if e := g.ending(); e != nil {
+		if e.End == endDraw {
+			return pb.Result_draw
 		}
+		if e.Mover == engine.Red {
+			return pb.Result_yellow_won
+		}
+		return pb.Result_red_won
 	}
+	return resultOf(g.board)
+}
 
-	return state
+func (g *game) endReason() pb.EndReason {
+	e := g.ending()
+	switch {
+	case e != nil && e.End == endResign:
+		return pb.EndReason_resigned
+	case e != nil && e.End == endDraw:
+		return pb.EndReason_agreed_draw
+	case e != nil:
+		return pb.EndReason_timeout
+	case g.board.Winner() != engine.Empty:
+		return pb.EndReason_aligned
+	case g.board.IsDraw():
+		return pb.EndReason_board_full
+	}
+	return pb.EndReason_not_over
 }
 
-type (
-	coords struct{ x, y int }
-	qNode  struct {
-		directionStreak int
-		streak          int
-		coords          coords
+func rejectionOf(err error, column int32) *pb.Rejection {
+	reason := pb.RejectionReason_not_rejected
+	switch {
+	case errors.Is(err, errNotYourTurn):
+		reason = pb.RejectionReason_not_your_turn
+	case errors.Is(err, engine.ErrColumnFull):
+		reason = pb.RejectionReason_column_full
+	case errors.Is(err, engine.ErrColumnOutOfRange):
+		reason = pb.RejectionReason_column_out_of_range
+	case errors.Is(err, errWrongGame):
+		reason = pb.RejectionReason_wrong_game
+	case errors.Is(err, errMatchOver):
+		reason = pb.RejectionReason_match_over
+	case errors.Is(err, errWrongTeam):
+		reason = pb.RejectionReason_wrong_team
+	case errors.Is(err, errBoardOver):
+		reason = pb.RejectionReason_board_over
+	case errors.Is(err, errNoOffer):
+		reason = pb.RejectionReason_no_offer
+	case errors.Is(err, errOfferPending):
+		reason = pb.RejectionReason_offer_pending
+	case errors.Is(err, errNoTakeback):
+		reason = pb.RejectionReason_nothing_to_take_back
+	case errors.Is(err, errBoardInPlay):
+		reason = pb.RejectionReason_board_in_play
+	case errors.Is(err, errInvalidChat):
+		reason = pb.RejectionReason_invalid_chat
+	case errors.Is(err, errRematchShutdown):
+		reason = pb.RejectionReason_shutting_down
+	case errors.Is(err, errUnknownAction):
+		reason = pb.RejectionReason_unknown_action
 	}
-)
+	msg := err.Error()
+	return &pb.Rejection{Reason: &reason, Message: &msg, Column: &column}
+}
 
-func scan(state field, team pb.Team) bool {
-	visited := make(map[coords]map[int]int)
-	queue := make([]qNode, 0)
-	for i := range state {
-		if state[0][i] == team {
-			queue = append(queue, qNode{-1, 1, coords{0, i}})
-		}
+func (g *game) pbState() *pb.State {
+	g.mut.RLock()
+	defer g.mut.RUnlock()
+	return &pb.State{
+		Field:         pb.NewField(g.board),
+		Turn:          pb.Team(g.board.Turn()).Enum(),
+		Variant:       pb.NewVariant(g.variant),
+		Result:        g.result().Enum(),
+		WinningLine:   pb.NewCells(g.board.WinningLine()),
+		Score:         g.score(),
+		Players:       g.players(),
+		Clocks:        g.clocks(time.Now()),
+		EndReason:     g.endReason().Enum(),
+		Offer:         g.offer(),
+		NextFirst:     g.nextFirstTeam(),
+		RatingChanges: g.ratingChanges,
+		ShutdownAt:    g.shutdownTimestamp(),
 	}
-	for len(queue) > 0 {
-		cur := queue[0]
-		m, ok := visited[cur.coords]
-		if !ok {
-			visited[cur.coords] = make(map[int]int)
-			m = visited[cur.coords]
-		}
-		m[cur.directionStreak] = cur.streak
-		queue = queue[1:]
-		fmt.Println(cur)
-		if cur.streak >= 4 {
-			return true
-		}
-		toCheck := []coords{
-			{cur.coords.x, cur.coords.y + 1},
-			{cur.coords.x + 1, cur.coords.y + 1},
-			{cur.coords.x + 1, cur.coords.y},
-			{cur.coords.x + 1, cur.coords.y - 1},
-		}
-		for i, coords := range toCheck {
-			if coords.x >= 0 && coords.x < 8 && coords.y >= 0 &&
-				coords.y < 8 && state[coords.x][coords.y] == team && visited[coords][i] < cur.streak+1 {
-				qn := qNode{i, 2, coords}
-				if cur.directionStreak == i {
-					qn = qNode{cur.directionStreak, cur.streak + 1, coords}
-				}
-				queue = append(queue, qn)
-			}
-		}
+}
+
+func (g *game) players() *pb.Players {
+	spectators := int32(len(g.spectators)) //nolint:gosec // can't have that many connections
+	// copies, as the state may be sent once g changed
+	red, yellow := g.redName, g.yellowName
+	return &pb.Players{Red: &red, Yellow: &yellow, Spectators: &spectators}
+}
+
+// abandoned reports whether no human holds a seat anymore.
+func (g *game) abandoned() bool {
+	red := g.red && (g.bot == nil || g.bot.team != pb.Team_red)
+	yellow := g.yellow && (g.bot == nil || g.bot.team != pb.Team_yellow)
+	return !red && !yellow
+}
+
+// matchWinner returns the team that reached firstTo wins, empty while the match goes on.
+func (g *game) matchWinner() pb.Team {
+	switch {
+	case g.firstTo == 0:
+	case g.redWins >= g.firstTo:
+		return pb.Team_red
+	case g.yellowWins >= g.firstTo:
+		return pb.Team_yellow
+	}
+	return pb.Team_empty
+}
+
+//nolint:gosec // counters stay far below int32 limits
+func (g *game) score() *pb.Score {
+	red, yellow, draws := int32(g.redWins), int32(g.yellowWins), int32(g.draws)
+	played, firstTo := red+yellow+draws, int32(g.firstTo)
+	s := &pb.Score{RedWins: &red, YellowWins: &yellow, Draws: &draws, GamesPlayed: &played, FirstTo: &firstTo}
+	if winner := g.matchWinner(); winner != pb.Team_empty {
+		s.MatchWinner = winner.Enum()
+	}
+	return s
+}
+
+func resultOf(b *engine.Board) pb.Result {
+	switch {
+	case b.Winner() == engine.Red:
+		return pb.Result_red_won
+	case b.Winner() == engine.Yellow:
+		return pb.Result_yellow_won
+	case b.IsDraw():
+		return pb.Result_draw
 	}
-	return false
+	return pb.Result_in_progress
 }
 
-func Serve() {
-	lis, err := net.Listen("tcp", "0.0.0.0:50051")
+// Serve runs the server as cfg says until it fails, or until SIGINT or SIGTERM shut it down.
+func Serve(cfg Config) error {
+	level, _ := log.ValidateLevel(cfg.LogLevel) // validated by LoadConfig
+	log.SetLogLevel(level)
+	log.Infof("effective config: %v", cfg)
+	opts, err := cfg.serverOptions()
 	if err != nil {
-		log.Fatalf("failed to listen: %v", err)
+		return err
+	}
+	cs := newServer()
+	cs.games.limit = cfg.MaxGames
+	cs.grace = time.Duration(cfg.Grace)
+	cs.rateBots = cfg.RateBots
+	expvar.Publish("broadcast", expvar.Func(cs.stats.snapshot))
+	if cs.users, err = loadUsers(cfg.UsersFile); err != nil {
+		return fmt.Errorf("loading users: %w", err)
+	}
+	if cs.sessions, err = loadSessions(cfg.SessionKeyFile); err != nil {
+		return fmt.Errorf("loading the session key: %w", err)
 	}
-	var opts []grpc.ServerOption
-	grpcServer := grpc.NewServer(opts...)
-	pb.RegisterConnect4Server(grpcServer, newServer())
-	if err := grpcServer.Serve(lis); err != nil {
-		log.Fatal("server failed")
+	if cs.store, err = OpenBoltStore(cfg.GamesFile); err != nil {
+		return fmt.Errorf("opening the game store: %w", err)
+	}
+	defer cs.store.Close()
+	if err := cs.restore(); err != nil {
+		return fmt.Errorf("restoring games: %w", err)
+	}
+	lis, err := net.Listen("tcp", cfg.Listen)
+	if err != nil {
+		return err
+	}
+	grpcServer := grpc.NewServer(append(opts, cs.interceptors()...)...)
+	pb.RegisterConnect4Server(grpcServer, cs)
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	served := make(chan error, 1)
+	go func() {
+		served <- grpcServer.Serve(lis)
+	}()
+	log.Infof("serving on %v", lis.Addr())
+	select {
+	case err := <-served:
+		return err
+	case <-ctx.Done():
 	}
+	cancel() // a second signal kills the server
+	log.Infof("shutting down, waiting up to %v for the boards in play", cfg.DrainTimeout)
+	cs.shutdown(time.Duration(cfg.DrainTimeout))
+	stop(grpcServer)
+	log.Infof("games saved, server stopped")
+	return <-served
 }
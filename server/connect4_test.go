@@ -3,42 +3,85 @@ package server
 import (
 	"context"
 	"log"
+	"net"
 	"testing"
 
 	"github.com/geofpwhite/connect4-grpc/pb"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
 )
 
-func TestConnect4GameFlow(t *testing.T) {
-	// Connect to the gRPC server
-	conn, err := grpc.Dial("localhost:4040", grpc.WithInsecure())
+func startServer(t *testing.T) pb.Connect4Client {
+	t.Helper()
+	return serve(t, newServer())
+}
+
+// serve runs cs in process, for tests that need to look at its internals.
+func serve(t *testing.T, cs *connect4Server) pb.Connect4Client {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer(cs.interceptors()...)
+	pb.RegisterConnect4Server(grpcServer, cs)
+	go func() {
+		_ = grpcServer.Serve(lis)
+	}()
+	t.Cleanup(grpcServer.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
 		t.Fatalf("Failed to connect to server: %v", err)
 	}
-	defer conn.Close()
-	client := pb.NewConnect4Client(conn)
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewConnect4Client(conn)
+}
+
+// guest returns a context carrying the session of a new guest.
+func guest(t *testing.T, client pb.Connect4Client) context.Context {
+	t.Helper()
+	session, err := client.Guest(context.Background(), &pb.Empty{})
+	if err != nil {
+		t.Fatalf("Failed to get a guest session: %v", err)
+	}
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+session.GetToken())
+}
+
+func TestConnect4GameFlow(t *testing.T) {
+	client := startServer(t)
+	redCtx, yellowCtx := guest(t, client), guest(t, client)
 
 	// Start a new game
-	newGameResp, err := client.NewGame(context.Background(), &pb.Empty{})
+	newGameResp, err := client.NewGame(redCtx, &pb.NewGameRequest{})
 	if err != nil {
 		t.Fatalf("Failed to start a new game: %v", err)
 	}
-	log.Printf("New game started with ID: %d, Team: %v", newGameResp.Id, newGameResp.Team)
+	log.Printf("New game started with ID: %d, Team: %v", newGameResp.GetId(), newGameResp.GetTeam())
 
-	// Make a move
+	stream, err := client.CommunicateState(redCtx)
+	if err != nil {
+		t.Fatalf("Failed to communicate state: %v", err)
+	}
+	register := int32(-1)
+	if err := stream.Send(&pb.Input{GameId: newGameResp.Id, Action: &pb.Input_Column{Column: register},
+		InputTeam: newGameResp.Team, ResumeToken: newGameResp.ResumeToken}); err != nil {
+		t.Fatalf("Failed to register stream: %v", err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("Failed to receive initial state: %v", err)
+	}
 
+	// Make a move
 	col := int32(3)
 	move := &pb.Input{
 		GameId:    newGameResp.Id,
-		Column:    &col,
+		Action:    &pb.Input_Column{Column: col},
 		InputTeam: newGameResp.Team,
 	}
-	stream, err := client.CommunicateState(context.Background())
-	if err != nil {
-		t.Fatalf("Failed to communicate state: %v", err)
-	}
-
 	if err := stream.Send(move); err != nil {
 		t.Fatalf("Failed to send move: %v", err)
 	}
@@ -49,13 +92,291 @@ func TestConnect4GameFlow(t *testing.T) {
 		t.Fatalf("Failed to receive state: %v", err)
 	}
 	log.Printf("Received state: %+v", state)
-	// client2 := pb.NewConnect4Client(conn)
+	if state.GetTurn() != pb.Team_yellow {
+		t.Fatalf("expected yellow to move, got %v", state.GetTurn())
+	}
+	if got := state.GetField().GetRows()[0].GetValues()[col-1]; got != pb.Team_red {
+		t.Fatalf("expected a red disc at the bottom of column %d, got %v", col, got)
+	}
 
 	// Join the game with another client
-	joinResp, err := client.JoinGame(context.Background(), &pb.GameID{Id: newGameResp.Id})
+	joinResp, err := client.JoinGame(yellowCtx, &pb.JoinGameRequest{Id: newGameResp.Id})
+	if err != nil {
+		t.Fatalf("Failed to join the game: %v", err)
+	}
+
+	log.Printf("Joined game with ID: %d, Team: %v", joinResp.GetId(), joinResp.GetTeam())
+}
+
+func TestNewGameVariant(t *testing.T) {
+	client := startServer(t)
+	redCtx, yellowCtx := guest(t, client), guest(t, client)
+	rows, cols, connect := int32(6), int32(7), int32(4)
+	resp, err := client.NewGame(redCtx, &pb.NewGameRequest{
+		Variant: &pb.Variant{Rows: &rows, Columns: &cols},
+	})
+	if err != nil {
+		t.Fatalf("Failed to start a 7x6 game: %v", err)
+	}
+	if v := resp.GetVariant(); v.GetRows() != rows || v.GetColumns() != cols || v.GetConnect() != connect {
+		t.Fatalf("expected a 7x6 connect 4 variant, got %v", v)
+	}
+	join, err := client.JoinGame(yellowCtx, &pb.JoinGameRequest{Id: resp.Id})
+	if err != nil {
+		t.Fatalf("Failed to join the game: %v", err)
+	}
+	if join.GetVariant().GetColumns() != cols {
+		t.Fatalf("expected the joiner to get the game variant, got %v", join.GetVariant())
+	}
+	tooLong := int32(9)
+	if _, err := client.NewGame(redCtx, &pb.NewGameRequest{
+		Variant: &pb.Variant{Rows: &rows, Columns: &cols, Connect: &tooLong},
+	}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for connect 9 on a 7x6 board, got %v", err)
+	}
+}
+
+// register opens the stream of seat for the player of ctx and consumes the initial state.
+func register(
+	ctx context.Context, t *testing.T, client pb.Connect4Client, seat *pb.GameIDAndTeam,
+) grpc.BidiStreamingClient[pb.Input, pb.State] {
+	t.Helper()
+	stream, err := client.CommunicateState(ctx)
+	if err != nil {
+		t.Fatalf("Failed to communicate state: %v", err)
+	}
+	register := int32(-1)
+	if err := stream.Send(&pb.Input{GameId: seat.Id, Action: &pb.Input_Column{Column: register}, InputTeam: seat.Team,
+		ResumeToken: seat.ResumeToken}); err != nil {
+		t.Fatalf("Failed to register %v stream: %v", seat.GetTeam(), err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("Failed to receive initial state: %v", err)
+	}
+	return stream
+}
+
+// playMoves alternates red and yellow moves on the given 1 based columns and returns the last state red received.
+func playMoves(t *testing.T, id *int32, red, yellow grpc.BidiStreamingClient[pb.Input, pb.State], columns ...int32) *pb.State {
+	t.Helper()
+	return playFrom(t, id, pb.Team_red, red, yellow, columns...)
+}
+
+// playFrom is playMoves with first moving first.
+func playFrom(
+	t *testing.T, id *int32, first pb.Team, red, yellow grpc.BidiStreamingClient[pb.Input, pb.State], columns ...int32,
+) *pb.State {
+	t.Helper()
+	var state *pb.State
+	for i, col := range columns {
+		stream, team := red, pb.Team_red
+		if (i%2 == 1) == (first == pb.Team_red) {
+			stream, team = yellow, pb.Team_yellow
+		}
+		if err := stream.Send(&pb.Input{GameId: id, Action: &pb.Input_Column{Column: col}, InputTeam: team.Enum()}); err != nil {
+			t.Fatalf("Failed to send move %d: %v", i, err)
+		}
+		var err error
+		if state, err = red.Recv(); err != nil {
+			t.Fatalf("Failed to receive state: %v", err)
+		}
+		if _, err = yellow.Recv(); err != nil {
+			t.Fatalf("Failed to receive state: %v", err)
+		}
+	}
+	return state
+}
+
+func TestGameResult(t *testing.T) {
+	client := startServer(t)
+	redCtx, yellowCtx := guest(t, client), guest(t, client)
+	resp, err := client.NewGame(redCtx, &pb.NewGameRequest{})
+	if err != nil {
+		t.Fatalf("Failed to start a new game: %v", err)
+	}
+	join, err := client.JoinGame(yellowCtx, &pb.JoinGameRequest{Id: resp.Id})
+	if err != nil {
+		t.Fatalf("Failed to join the game: %v", err)
+	}
+	red := register(redCtx, t, client, resp)
+	yellow := register(yellowCtx, t, client, join)
+
+	state := playMoves(t, resp.Id, red, yellow, 1, 1, 2, 2, 3, 3, 4)
+	if state.GetResult() != pb.Result_red_won {
+		t.Fatalf("expected red to win, got %v", state.GetResult())
+	}
+	if len(state.GetWinningLine()) != 4 {
+		t.Fatalf("expected a winning line of 4, got %v", state.GetWinningLine())
+	}
+	for _, cell := range state.GetWinningLine() {
+		if cell.GetRow() != 0 || state.GetField().GetRows()[0].GetValues()[cell.GetColumn()] != pb.Team_red {
+			t.Fatalf("unexpected winning cell %v", cell)
+		}
+	}
+	// a rematch starts a fresh board, opened by the other player
+	state = rematch(t, resp.Id, red, yellow)
+	if state.GetResult() != pb.Result_in_progress || state.GetTurn() != pb.Team_yellow {
+		t.Fatalf("expected a new game with yellow to move, got %v", state)
+	}
+}
+
+func TestGameDraw(t *testing.T) {
+	client := startServer(t)
+	redCtx, yellowCtx := guest(t, client), guest(t, client)
+	size := int32(4)
+	resp, err := client.NewGame(redCtx, &pb.NewGameRequest{
+		Variant: &pb.Variant{Rows: &size, Columns: &size, Connect: &size},
+	})
+	if err != nil {
+		t.Fatalf("Failed to start a new game: %v", err)
+	}
+	join, err := client.JoinGame(yellowCtx, &pb.JoinGameRequest{Id: resp.Id})
 	if err != nil {
 		t.Fatalf("Failed to join the game: %v", err)
 	}
+	red := register(redCtx, t, client, resp)
+	yellow := register(yellowCtx, t, client, join)
 
-	log.Printf("Joined game with ID: %d, Team: %v", joinResp.Id, joinResp.Team)
+	state := playMoves(t, resp.Id, red, yellow, 1, 1, 1, 1, 2, 2, 2, 2, 3, 3, 3, 4, 4, 4, 3, 4)
+	if state.GetResult() != pb.Result_draw {
+		t.Fatalf("expected a draw, got %v", state.GetResult())
+	}
+	if len(state.GetWinningLine()) != 0 {
+		t.Fatalf("expected no winning line, got %v", state.GetWinningLine())
+	}
+}
+
+func TestRejections(t *testing.T) {
+	client := startServer(t)
+	redCtx, yellowCtx := guest(t, client), guest(t, client)
+	resp, err := client.NewGame(redCtx, &pb.NewGameRequest{})
+	if err != nil {
+		t.Fatalf("Failed to start a new game: %v", err)
+	}
+	join, err := client.JoinGame(yellowCtx, &pb.JoinGameRequest{Id: resp.Id})
+	if err != nil {
+		t.Fatalf("Failed to join the game: %v", err)
+	}
+	if _, err = client.JoinGame(yellowCtx, &pb.JoinGameRequest{Id: resp.Id}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition joining a full game, got %v", err)
+	}
+	unknown := resp.GetId() + 1
+	if _, err = client.JoinGame(yellowCtx, &pb.JoinGameRequest{Id: &unknown}); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound joining an unknown game, got %v", err)
+	}
+	red := register(redCtx, t, client, resp)
+	yellow := register(yellowCtx, t, client, join)
+
+	reject := func(
+		stream grpc.BidiStreamingClient[pb.Input, pb.State], gameID *int32, team pb.Team, col int32, want pb.RejectionReason,
+	) {
+		t.Helper()
+		if err := stream.Send(&pb.Input{GameId: gameID, Action: &pb.Input_Column{Column: col}, InputTeam: team.Enum()}); err != nil {
+			t.Fatalf("Failed to send move: %v", err)
+		}
+		state, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Failed to receive state: %v", err)
+		}
+		if got := state.GetRejection().GetReason(); got != want {
+			t.Fatalf("column %d: expected rejection %v, got %v", col, want, got)
+		}
+	}
+	reject(yellow, resp.Id, pb.Team_yellow, 1, pb.RejectionReason_not_your_turn)
+	reject(red, resp.Id, pb.Team_red, 0, pb.RejectionReason_column_out_of_range)
+	reject(red, resp.Id, pb.Team_red, 9, pb.RejectionReason_column_out_of_range)
+	reject(red, &unknown, pb.Team_red, 1, pb.RejectionReason_wrong_game)
+
+	playMoves(t, resp.Id, red, yellow, 1, 1, 1, 1, 1, 1, 1, 1)
+	reject(red, resp.Id, pb.Team_red, 1, pb.RejectionReason_column_full)
+}
+
+func TestMatchScore(t *testing.T) {
+	client := startServer(t)
+	redCtx, yellowCtx := guest(t, client), guest(t, client)
+	firstTo := int32(2)
+	resp, err := client.NewGame(redCtx, &pb.NewGameRequest{FirstTo: &firstTo})
+	if err != nil {
+		t.Fatalf("Failed to start a new game: %v", err)
+	}
+	join, err := client.JoinGame(yellowCtx, &pb.JoinGameRequest{Id: resp.Id})
+	if err != nil {
+		t.Fatalf("Failed to join the game: %v", err)
+	}
+	red := register(redCtx, t, client, resp)
+	yellow := register(yellowCtx, t, client, join)
+
+	state := playMoves(t, resp.Id, red, yellow, 1, 1, 2, 2, 3, 3, 4)
+	if score := state.GetScore(); score.GetRedWins() != 1 || score.GetGamesPlayed() != 1 || score.MatchWinner != nil {
+		t.Fatalf("unexpected score after the first game: %v", score)
+	}
+	rematch(t, resp.Id, red, yellow)
+	state = playFrom(t, resp.Id, pb.Team_yellow, red, yellow, 8, 1, 1, 2, 2, 3, 3, 4)
+	score := state.GetScore()
+	if score.GetRedWins() != 2 || score.GetYellowWins() != 0 || score.GetGamesPlayed() != 2 || score.GetFirstTo() != firstTo {
+		t.Fatalf("unexpected score after the second game: %v", score)
+	}
+	if score.GetMatchWinner() != pb.Team_red {
+		t.Fatalf("expected red to win the match, got %v", score.GetMatchWinner())
+	}
+	col := int32(1)
+	if err := red.Send(&pb.Input{GameId: resp.Id, Action: &pb.Input_Column{Column: col}, InputTeam: pb.Team_red.Enum()}); err != nil {
+		t.Fatalf("Failed to send move: %v", err)
+	}
+	if state, err = red.Recv(); err != nil {
+		t.Fatalf("Failed to receive state: %v", err)
+	}
+	if state.GetRejection().GetReason() != pb.RejectionReason_match_over {
+		t.Fatalf("expected the match to be over, got %v", state.GetRejection())
+	}
+}
+
+func TestBotOpponent(t *testing.T) {
+	client := startServer(t)
+	redCtx, yellowCtx := guest(t, client), guest(t, client)
+	level := int32(4)
+	resp, err := client.NewGame(redCtx, &pb.NewGameRequest{
+		Opponent: pb.Opponent_bot.Enum(),
+		BotLevel: &level,
+	})
+	if err != nil {
+		t.Fatalf("Failed to start a bot game: %v", err)
+	}
+	if _, err = client.JoinGame(yellowCtx, &pb.JoinGameRequest{Id: resp.Id}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected the bot seat to be taken, got %v", err)
+	}
+	red := register(redCtx, t, client, resp)
+	col := int32(4)
+	if err := red.Send(&pb.Input{GameId: resp.Id, Action: &pb.Input_Column{Column: col}, InputTeam: pb.Team_red.Enum()}); err != nil {
+		t.Fatalf("Failed to send move: %v", err)
+	}
+	if _, err := red.Recv(); err != nil {
+		t.Fatalf("Failed to receive own move: %v", err)
+	}
+	state, err := red.Recv()
+	if err != nil {
+		t.Fatalf("Failed to receive the bot move: %v", err)
+	}
+	if state.GetTurn() != pb.Team_red {
+		t.Fatalf("expected red to move after the bot, got %v", state.GetTurn())
+	}
+	yellows := 0
+	for _, row := range state.GetField().GetRows() {
+		for _, v := range row.GetValues() {
+			if v == pb.Team_yellow {
+				yellows++
+			}
+		}
+	}
+	if yellows != 1 {
+		t.Fatalf("expected the bot to have played once, found %d yellow discs", yellows)
+	}
+
+	badLevel := int32(9)
+	if _, err := client.NewGame(redCtx, &pb.NewGameRequest{
+		Opponent: pb.Opponent_bot.Enum(),
+		BotLevel: &badLevel,
+	}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for bot level 9, got %v", err)
+	}
 }
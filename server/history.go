@@ -0,0 +1,176 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/geofpwhite/connect4-grpc/engine"
+	"github.com/geofpwhite/connect4-grpc/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// replayPause is the time between moves of a replay without a speed.
+	replayPause = time.Second
+	// maxReplayPause caps the time waited on a move, whatever the players took.
+	maxReplayPause = 5 * time.Second
+)
+
+var errNoSuchBoard = status.Error(codes.OutOfRange, "game has no such board")
+
+// lookup returns the record of a live game or else the stored one.
+func (cs *connect4Server) lookup(id int32) (*GameRecord, error) {
+	if g, exists := cs.games.get(id); exists {
+		g.mut.RLock()
+		defer g.mut.RUnlock()
+		return g.record(id), nil
+	}
+	rec, err := cs.store.Load(id)
+	if errors.Is(err, ErrNotStored) {
+		return nil, errGameNotFound
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "loading game %d: %v", id, err)
+	}
+	return rec, nil
+}
+
+func (cs *connect4Server) GetGameHistory(_ context.Context, id *pb.GameID) (*pb.GameHistory, error) {
+	rec, err := cs.lookup(id.GetId())
+	if err != nil {
+		return nil, err
+	}
+	g := replayOf(rec)
+	history := &pb.GameHistory{
+		Id:        id.Id,
+		Variant:   pb.NewVariant(rec.Variant),
+		CreatedAt: timestamppb.New(rec.CreatedAt),
+		Players:   g.players(),
+		Boards:    make([]*pb.BoardHistory, len(rec.Boards)),
+	}
+	if !rec.FinishedAt.IsZero() {
+		history.FinishedAt = timestamppb.New(rec.FinishedAt)
+	}
+	for i, moves := range rec.Boards {
+		if err := g.replayBoard(rec.start(i), moves, func(*MoveRecord) error { return nil }); err != nil {
+			return nil, err
+		}
+		b := &pb.BoardHistory{Result: g.result().Enum(), EndReason: g.endReason().Enum(), First: pb.Team(rec.start(i)).Enum()}
+		for _, m := range moves {
+			if m.End != "" {
+				continue
+			}
+			column := int32(m.Column + 1) //nolint:gosec // board is at most engine.MaxSize wide
+			b.Moves = append(b.Moves, &pb.Move{Column: &column, Team: pb.Team(m.Mover).Enum(), PlayedAt: timestamppb.New(m.At)})
+		}
+		history.Boards[i] = b
+	}
+	history.Score = g.score()
+	return history, nil
+}
+
+// Replay sends the state after each move of the boards asked for, waiting between moves for the
+// time the players took divided by the speed.
+func (cs *connect4Server) Replay(req *pb.ReplayRequest, stream grpc.ServerStreamingServer[pb.State]) error {
+	rec, err := cs.lookup(req.GetId())
+	if err != nil {
+		return err
+	}
+	if req.Board != nil && (req.GetBoard() < 0 || int(req.GetBoard()) >= len(rec.Boards)) {
+		return errNoSuchBoard
+	}
+	g := replayOf(rec)
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for i, moves := range rec.Boards {
+		if req.Board != nil && i > int(req.GetBoard()) {
+			break
+		}
+		if req.Board != nil && i < int(req.GetBoard()) {
+			// earlier boards still count in the score
+			if err := g.replayBoard(rec.start(i), moves, func(*MoveRecord) error { return nil }); err != nil {
+				return err
+			}
+			continue
+		}
+		previous := time.Time{} // the first move of a board waits the default pause
+		err := g.replayBoard(rec.start(i), moves, func(m *MoveRecord) error {
+			if err := stream.Send(g.pbState()); err != nil {
+				return err
+			}
+			timer.Reset(replayDelay(previous, m.At, req.GetSpeed()))
+			previous = m.At
+			select {
+			case <-stream.Context().Done():
+				return stream.Context().Err()
+			case <-cs.draining:
+				return errShuttingDown
+			case <-timer.C:
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if g.over() {
+			// random picks aren't drawn again
+			g.next = rec.Next
+			if i+1 < len(rec.Boards) {
+				g.next = rec.start(i + 1)
+			}
+		}
+		if err := stream.Send(g.pbState()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replayOf is a game holding the seats and settings of rec, its boards being played back from the
+// start.
+func replayOf(rec *GameRecord) *game {
+	g := &game{
+		variant:     rec.Variant,
+		firstTo:     rec.FirstTo,
+		firstPlayer: rec.FirstPlayer,
+		redName:     rec.Red.Name,
+		yellowName:  rec.Yellow.Name,
+		mut:         &sync.RWMutex{},
+	}
+	g.board, _ = engine.New(rec.Variant) // stored games have a valid variant
+	return g
+}
+
+// replayBoard plays moves on a new board first moves on, calling each before every move.
+func (g *game) replayBoard(first engine.Piece, moves []MoveRecord, each func(*MoveRecord) error) error {
+	var err error
+	if g.board, err = engine.NewStarting(g.variant, first); err != nil {
+		return status.Errorf(codes.DataLoss, "invalid first player of the board: %v", err)
+	}
+	g.boards, g.starts = [][]MoveRecord{nil}, []engine.Piece{first}
+	for i := range moves {
+		if err := each(&moves[i]); err != nil {
+			return err
+		}
+		if err := g.apply(moves[i]); err != nil {
+			return status.Errorf(codes.DataLoss, "invalid move %d of the board: %v", i+1, err)
+		}
+	}
+	return nil
+}
+
+func replayDelay(previous, at time.Time, speed float64) time.Duration {
+	if speed <= 0 {
+		return replayPause
+	}
+	took := replayPause
+	if !previous.IsZero() {
+		took = at.Sub(previous)
+	}
+	return min(max(time.Duration(float64(took)/speed), 0), maxReplayPause)
+}
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/geofpwhite/connect4-grpc/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGameHistory(t *testing.T) {
+	client := startServer(t)
+	redCtx, yellowCtx, ctx := guest(t, client), guest(t, client), context.Background()
+	resp, err := client.NewGame(redCtx, &pb.NewGameRequest{})
+	if err != nil {
+		t.Fatalf("Failed to start a new game: %v", err)
+	}
+	join, err := client.JoinGame(yellowCtx, &pb.JoinGameRequest{Id: resp.Id})
+	if err != nil {
+		t.Fatalf("Failed to join the game: %v", err)
+	}
+	red := register(redCtx, t, client, resp)
+	yellow := register(yellowCtx, t, client, join)
+	playMoves(t, resp.Id, red, yellow, 1, 1, 2, 2, 3, 3, 4)
+	rematch(t, resp.Id, red, yellow)
+	act(t, resp.Id, pb.Team_yellow, yellow, &pb.Input{Action: &pb.Input_Column{Column: 5}})
+	recv(t, red)
+
+	history, err := client.GetGameHistory(ctx, &pb.GameID{Id: resp.Id})
+	if err != nil {
+		t.Fatalf("Failed to get the history: %v", err)
+	}
+	if len(history.GetBoards()) != 2 || history.GetFinishedAt() != nil || history.GetScore().GetRedWins() != 1 {
+		t.Fatalf("expected 2 boards of a live game won once by red, got %v", history)
+	}
+	first := history.GetBoards()[0]
+	if first.GetResult() != pb.Result_red_won || len(first.GetMoves()) != 7 {
+		t.Fatalf("expected the 7 moves of a red win, got %v", first)
+	}
+	for i, m := range first.GetMoves() {
+		team := pb.Team_red
+		if i%2 == 1 {
+			team = pb.Team_yellow
+		}
+		if m.GetTeam() != team || m.GetColumn() != []int32{1, 1, 2, 2, 3, 3, 4}[i] || m.GetPlayedAt() == nil {
+			t.Fatalf("unexpected move %d: %v", i, m)
+		}
+	}
+	if moves := history.GetBoards()[1].GetMoves(); len(moves) != 1 || moves[0].GetColumn() != 5 ||
+		moves[0].GetTeam() != pb.Team_yellow {
+		t.Fatalf("expected the board in play to have the move of yellow in column 5, got %v", moves)
+	}
+	if a, b := first.GetFirst(), history.GetBoards()[1].GetFirst(); a != pb.Team_red || b != pb.Team_yellow {
+		t.Fatalf("expected red then yellow to open the boards, got %v and %v", a, b)
+	}
+
+	// finished games are replayed from the store
+	for _, seat := range []struct {
+		ctx  context.Context
+		seat *pb.GameIDAndTeam
+	}{{redCtx, resp}, {yellowCtx, join}} {
+		if _, err := client.LeaveGame(seat.ctx, seat.seat); err != nil {
+			t.Fatalf("Failed to leave: %v", err)
+		}
+	}
+	board, speed := int32(0), 1000.
+	replay, err := client.Replay(ctx, &pb.ReplayRequest{Id: resp.Id, Board: &board, Speed: &speed})
+	if err != nil {
+		t.Fatalf("Failed to replay: %v", err)
+	}
+	var states []*pb.State
+	for {
+		state, err := replay.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to receive the replay: %v", err)
+		}
+		states = append(states, state)
+	}
+	if len(states) != 8 {
+		t.Fatalf("expected the empty board and the 7 moves, got %d states", len(states))
+	}
+	if states[0].GetField().GetRows()[0].GetValues()[0] != pb.Team_empty ||
+		states[1].GetField().GetRows()[0].GetValues()[0] != pb.Team_red {
+		t.Fatalf("expected the replay to start from the empty board, got %v then %v", states[0].GetField(), states[1].GetField())
+	}
+	last := states[7]
+	if last.GetResult() != pb.Result_red_won || len(last.GetWinningLine()) != 4 || last.GetScore().GetRedWins() != 1 {
+		t.Fatalf("expected the replay to end on the red win, got %v", last)
+	}
+
+	board = 2
+	replay, err = client.Replay(ctx, &pb.ReplayRequest{Id: resp.Id, Board: &board})
+	if err == nil {
+		_, err = replay.Recv()
+	}
+	if status.Code(err) != codes.OutOfRange {
+		t.Fatalf("expected OutOfRange replaying a missing board, got %v", err)
+	}
+	missing := int32(-1)
+	if _, err := client.GetGameHistory(ctx, &pb.GameID{Id: &missing}); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound for an unknown game, got %v", err)
+	}
+}
@@ -0,0 +1,216 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/geofpwhite/connect4-grpc/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+	// lobbyBuffer is how many events a watcher may lag behind before it gets disconnected.
+	lobbyBuffer = 64
+)
+
+var errLobbyOverflow = status.Error(codes.ResourceExhausted, "lobby watcher fell too far behind")
+
+type lobbyWatcher struct {
+	filter  *pb.ListGamesRequest
+	visible map[int32]bool // games the watcher was told about and not removed since
+	sub     *subscriber[*pb.LobbyEvent]
+}
+
+// ListGames returns the public games matching the request, oldest first.
+func (cs *connect4Server) ListGames(_ context.Context, req *pb.ListGamesRequest) (*pb.ListGamesResponse, error) {
+	if _, err := timeControlOf(req.GetTimeControl()); err != nil {
+		return nil, err
+	}
+	size := int(req.GetPageSize())
+	switch {
+	case size < 0:
+		return nil, status.Errorf(codes.InvalidArgument, "page_size must not be negative, got %d", size)
+	case size == 0:
+		size = defaultPageSize
+	case size > maxPageSize:
+		size = maxPageSize
+	}
+	games := cs.listed(req)
+	if token := req.GetPageToken(); token != "" {
+		var after cursor
+		if _, err := fmt.Sscanf(token, "%d.%d", &after.created, &after.id); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page_token %q", token)
+		}
+		start, _ := slices.BinarySearchFunc(games, after, func(s *pb.GameSummary, c cursor) int {
+			return cursorOf(s).compare(c)
+		})
+		if start < len(games) && cursorOf(games[start]) == after {
+			start++
+		}
+		games = games[start:]
+	}
+	resp := &pb.ListGamesResponse{}
+	if len(games) > size {
+		games = games[:size]
+		last := cursorOf(games[size-1])
+		next := fmt.Sprintf("%d.%d", last.created, last.id)
+		resp.NextPageToken = &next
+	}
+	resp.Games = games
+	return resp, nil
+}
+
+// WatchLobby sends the public games matching the filter, then every change to them until the client
+// disconnects. Page fields of the request are ignored.
+func (cs *connect4Server) WatchLobby(req *pb.ListGamesRequest, stream grpc.ServerStreamingServer[pb.LobbyEvent]) error {
+	if _, err := timeControlOf(req.GetTimeControl()); err != nil {
+		return err
+	}
+	w := &lobbyWatcher{filter: req, visible: make(map[int32]bool)}
+	cs.lobbyMut.Lock()
+	games := cs.listed(req)
+	// the games listed first don't count against lobbyBuffer
+	w.sub = subscribe(&cs.stats, "lobby", lobbyBuffer+len(games), stream.Send, nil)
+	for _, s := range games {
+		w.visible[s.GetId()] = true
+		w.sub.push(&pb.LobbyEvent{Kind: pb.LobbyEventKind_added.Enum(), Game: s})
+	}
+	cs.watchers[w] = struct{}{}
+	cs.lobbyMut.Unlock()
+	defer w.sub.close()
+	defer func() {
+		cs.lobbyMut.Lock()
+		delete(cs.watchers, w)
+		cs.lobbyMut.Unlock()
+	}()
+	select {
+	case <-stream.Context().Done():
+		return nil
+	case <-w.sub.done():
+		if errors.Is(w.sub.Err(), errSlowSubscriber) {
+			return errLobbyOverflow
+		}
+		return w.sub.Err()
+	}
+}
+
+// lobbyChanged tells the lobby watchers about a game that was created, deleted or changed seats.
+func (cs *connect4Server) lobbyChanged(id int32) {
+	var summary *pb.GameSummary
+	if g, exists := cs.games.get(id); exists && !g.private {
+		summary = g.summary(id)
+	}
+	cs.lobbyMut.Lock()
+	defer cs.lobbyMut.Unlock()
+	for w := range cs.watchers {
+		was, is := w.visible[id], summary != nil && matches(summary, w.filter)
+		e := &pb.LobbyEvent{Game: summary}
+		switch {
+		case is && was:
+			e.Kind = pb.LobbyEventKind_updated.Enum()
+		case is:
+			e.Kind = pb.LobbyEventKind_added.Enum()
+		case was:
+			e.Kind = pb.LobbyEventKind_removed.Enum()
+			if summary == nil {
+				e.Game = &pb.GameSummary{Id: &id}
+			}
+		default:
+			continue
+		}
+		w.sub.push(e)
+		select {
+		case <-w.sub.done():
+			delete(cs.watchers, w)
+			continue
+		default:
+		}
+		if is {
+			w.visible[id] = true
+		} else {
+			delete(w.visible, id)
+		}
+	}
+}
+
+// listed returns the summaries of the public games matching the filter of req, oldest first.
+func (cs *connect4Server) listed(req *pb.ListGamesRequest) []*pb.GameSummary {
+	var games []*pb.GameSummary
+	for id, g := range cs.games.all() {
+		if g.private {
+			continue
+		}
+		if s := g.summary(id); matches(s, req) {
+			games = append(games, s)
+		}
+	}
+	slices.SortFunc(games, func(a, b *pb.GameSummary) int { return cursorOf(a).compare(cursorOf(b)) })
+	return games
+}
+
+func matches(s *pb.GameSummary, req *pb.ListGamesRequest) bool {
+	v, want := s.GetVariant(), req.GetVariant()
+	switch {
+	case want.GetRows() != 0 && want.GetRows() != v.GetRows(),
+		want.GetColumns() != 0 && want.GetColumns() != v.GetColumns(),
+		want.GetConnect() != 0 && want.GetConnect() != v.GetConnect():
+		return false
+	}
+	if req.GetTimeControl() != nil {
+		// both were validated, by ListGames or WatchLobby and by NewGame
+		want, _ := timeControlOf(req.GetTimeControl())
+		got, _ := timeControlOf(s.GetTimeControl())
+		if want != got {
+			return false
+		}
+	}
+	return req.GetIncludeFull() || s.GetSeatsFree() > 0
+}
+
+func (g *game) summary(id int32) *pb.GameSummary {
+	g.mut.RLock()
+	defer g.mut.RUnlock()
+	free := int32(0)
+	for _, taken := range []bool{g.red, g.yellow} {
+		if !taken {
+			free++
+		}
+	}
+	host, firstTo, rated := g.host, int32(g.firstTo), g.rated //nolint:gosec // validated in NewGame
+	s := &pb.GameSummary{
+		Id:         &id,
+		HostName:   &host,
+		Variant:    pb.NewVariant(g.variant),
+		CreatedAt:  timestamppb.New(g.createdAt),
+		SeatsFree:  &free,
+		FirstTo:    &firstTo,
+		Spectators: g.players().Spectators,
+		Rated:      &rated,
+	}
+	if g.clock != nil {
+		s.TimeControl = g.clock.control.message()
+	}
+	return s
+}
+
+// cursor orders the games of the lobby and marks where a page ends.
+type cursor struct {
+	created int64 // unix nanoseconds
+	id      int32
+}
+
+func cursorOf(s *pb.GameSummary) cursor {
+	return cursor{created: s.GetCreatedAt().AsTime().UnixNano(), id: s.GetId()}
+}
+
+func (c cursor) compare(o cursor) int {
+	return cmp.Or(cmp.Compare(c.created, o.created), cmp.Compare(c.id, o.id))
+}
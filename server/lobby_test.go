@@ -0,0 +1,163 @@
+package server
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/geofpwhite/connect4-grpc/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestListGames(t *testing.T) {
+	client := startServer(t)
+	ctx := guest(t, client)
+	var ids []int32
+	for i, rows := range []int32{6, 8, 6, 6} {
+		name := string(rune('a' + i))
+		resp, err := client.NewGame(ctx, &pb.NewGameRequest{Variant: &pb.Variant{Rows: &rows}, PlayerName: &name})
+		if err != nil {
+			t.Fatalf("Failed to start a new game: %v", err)
+		}
+		ids = append(ids, resp.GetId())
+	}
+	private := true
+	if _, err := client.NewGame(ctx, &pb.NewGameRequest{Private: &private}); err != nil {
+		t.Fatalf("Failed to start a private game: %v", err)
+	}
+	if _, err := client.JoinGame(ctx, &pb.JoinGameRequest{Id: &ids[3]}); err != nil {
+		t.Fatalf("Failed to join: %v", err)
+	}
+
+	resp, err := client.ListGames(ctx, &pb.ListGamesRequest{})
+	if err != nil {
+		t.Fatalf("Failed to list games: %v", err)
+	}
+	if got := summaryIDs(resp.GetGames()); !slices.Equal(got, ids[:3]) {
+		t.Fatalf("expected the open public games %v oldest first, got %v", ids[:3], got)
+	}
+	if g := resp.GetGames()[0]; g.GetHostName() != "a" || g.GetSeatsFree() != 1 || g.GetCreatedAt() == nil {
+		t.Fatalf("unexpected summary %v", g)
+	}
+
+	six, pageSize := int32(6), int32(2)
+	includeFull := true
+	var got []int32
+	req := &pb.ListGamesRequest{Variant: &pb.Variant{Rows: &six}, IncludeFull: &includeFull, PageSize: &pageSize}
+	for {
+		resp, err := client.ListGames(ctx, req)
+		if err != nil {
+			t.Fatalf("Failed to list games: %v", err)
+		}
+		if len(resp.GetGames()) > 2 {
+			t.Fatalf("expected at most 2 games per page, got %d", len(resp.GetGames()))
+		}
+		got = append(got, summaryIDs(resp.GetGames())...)
+		if resp.GetNextPageToken() == "" {
+			break
+		}
+		req.PageToken = resp.NextPageToken
+	}
+	if want := []int32{ids[0], ids[2], ids[3]}; !slices.Equal(got, want) {
+		t.Fatalf("expected the 6 row games %v, got %v", want, got)
+	}
+
+	bogus := "bogus"
+	_, err = client.ListGames(ctx, &pb.ListGamesRequest{PageToken: &bogus})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for a bad token, got %v", err)
+	}
+}
+
+func TestListGamesByTimeControl(t *testing.T) {
+	client := startServer(t)
+	ctx := guest(t, client)
+	var ids []int32
+	for _, control := range []*pb.TimeControl{nil, fischer(5*time.Minute, 3*time.Second), fischer(time.Minute, 0)} {
+		resp, err := client.NewGame(ctx, &pb.NewGameRequest{TimeControl: control})
+		if err != nil {
+			t.Fatalf("Failed to start a new game: %v", err)
+		}
+		ids = append(ids, resp.GetId())
+	}
+	for _, tc := range []struct {
+		control *pb.TimeControl
+		want    []int32
+	}{
+		{nil, ids},
+		{&pb.TimeControl{}, ids[:1]},
+		{fischer(5*time.Minute, 3*time.Second), ids[1:2]},
+		{fischer(5*time.Minute, 0), nil},
+	} {
+		resp, err := client.ListGames(ctx, &pb.ListGamesRequest{TimeControl: tc.control})
+		if err != nil {
+			t.Fatalf("Failed to list games: %v", err)
+		}
+		if got := summaryIDs(resp.GetGames()); !slices.Equal(got, tc.want) {
+			t.Errorf("%v: expected %v, got %v", tc.control, tc.want, got)
+		}
+	}
+	_, err := client.ListGames(ctx, &pb.ListGamesRequest{TimeControl: fischer(time.Second, time.Minute)})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for a bad time control, got %v", err)
+	}
+}
+
+func TestWatchLobby(t *testing.T) {
+	client := startServer(t)
+	ctx, cancel := context.WithCancel(guest(t, client))
+	defer cancel()
+	first, err := client.NewGame(ctx, &pb.NewGameRequest{})
+	if err != nil {
+		t.Fatalf("Failed to start a new game: %v", err)
+	}
+	watch, err := client.WatchLobby(ctx, &pb.ListGamesRequest{})
+	if err != nil {
+		t.Fatalf("Failed to watch the lobby: %v", err)
+	}
+	expect := func(kind pb.LobbyEventKind, id int32) *pb.GameSummary {
+		t.Helper()
+		e, err := watch.Recv()
+		if err != nil {
+			t.Fatalf("Failed to receive a lobby event: %v", err)
+		}
+		if e.GetKind() != kind || e.GetGame().GetId() != id {
+			t.Fatalf("expected %v of game %d, got %v", kind, id, e)
+		}
+		return e.GetGame()
+	}
+	expect(pb.LobbyEventKind_added, first.GetId())
+
+	second, err := client.NewGame(ctx, &pb.NewGameRequest{})
+	if err != nil {
+		t.Fatalf("Failed to start a new game: %v", err)
+	}
+	expect(pb.LobbyEventKind_added, second.GetId())
+
+	// a full game leaves the lobby and comes back once a seat frees up
+	join, err := client.JoinGame(ctx, &pb.JoinGameRequest{Id: first.Id})
+	if err != nil {
+		t.Fatalf("Failed to join: %v", err)
+	}
+	expect(pb.LobbyEventKind_removed, first.GetId())
+	if _, err := client.LeaveGame(ctx, join); err != nil {
+		t.Fatalf("Failed to leave: %v", err)
+	}
+	if g := expect(pb.LobbyEventKind_added, first.GetId()); g.GetSeatsFree() != 1 {
+		t.Fatalf("expected 1 free seat, got %d", g.GetSeatsFree())
+	}
+	if _, err := client.LeaveGame(ctx, second); err != nil {
+		t.Fatalf("Failed to leave: %v", err)
+	}
+	expect(pb.LobbyEventKind_removed, second.GetId())
+}
+
+func summaryIDs(games []*pb.GameSummary) []int32 {
+	ids := make([]int32, len(games))
+	for i, g := range games {
+		ids[i] = g.GetId()
+	}
+	return ids
+}
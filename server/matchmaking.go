@@ -0,0 +1,165 @@
+package server
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/geofpwhite/connect4-grpc/engine"
+	"github.com/geofpwhite/connect4-grpc/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// a rated player is first paired within ratingWindow points, the window then widening by
+	// ratingWindowGrowth points per second spent in the queue up to maxRatingWindow.
+	ratingWindow       = 100
+	ratingWindowGrowth = 10
+	maxRatingWindow    = 500
+	// matchRetry is how often a waiting player looks for an opponent again as windows widen.
+	matchRetry = time.Second
+)
+
+// ticket is a player waiting in the matchmaking queue.
+type ticket struct {
+	variant engine.Variant
+	control TimeControl
+	rated   bool
+	rating  float64
+	name    string
+	user    string
+	since   time.Time
+	matched chan *pb.GameIDAndTeam // receives the seat once paired
+}
+
+// window is the largest rating difference the ticket accepts at now.
+func (t *ticket) window(now time.Time) float64 {
+	return min(ratingWindow+ratingWindowGrowth*now.Sub(t.since).Seconds(), maxRatingWindow)
+}
+
+func compatible(a, b *ticket, now time.Time) bool {
+	if a.user == b.user || a.variant != b.variant || a.control != b.control || a.rated != b.rated {
+		return false
+	}
+	diff := math.Abs(a.rating - b.rating)
+	return !a.rated || (diff <= a.window(now) && diff <= b.window(now))
+}
+
+// FindMatch queues the player until another one wants the same kind of game, then creates it with
+// random colors. Leaving the queue is done by canceling the call.
+func (cs *connect4Server) FindMatch(req *pb.FindMatchRequest, stream grpc.ServerStreamingServer[pb.MatchUpdate]) error {
+	if len(req.GetPlayerName()) > maxNameLength {
+		return errNameTooLong
+	}
+	variant := req.GetVariant().Engine()
+	if err := variant.Validate(); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	control, err := timeControlOf(req.GetTimeControl())
+	if err != nil {
+		return err
+	}
+	if req.GetRated() && isGuest(caller(stream.Context())) {
+		return errGuestRated
+	}
+	t := &ticket{
+		variant: variant,
+		control: control,
+		rated:   req.GetRated(),
+		rating:  cs.users.rating(caller(stream.Context())).Rating,
+		name:    playerName(stream.Context(), req.GetPlayerName()),
+		user:    caller(stream.Context()),
+		since:   time.Now(),
+		matched: make(chan *pb.GameIDAndTeam, 1),
+	}
+	cs.queueMut.Lock()
+	cs.queue = append(cs.queue, t)
+	queued := int32(len(cs.queue)) //nolint:gosec // can't have that many connections
+	cs.queueMut.Unlock()
+	if !cs.pair(t) {
+		if err := stream.Send(&pb.MatchUpdate{Queued: &queued}); err != nil {
+			cs.leaveQueue(t)
+			return err
+		}
+	}
+	retry := time.NewTicker(matchRetry)
+	defer retry.Stop()
+	for {
+		select {
+		case seat := <-t.matched:
+			return stream.Send(&pb.MatchUpdate{Match: seat})
+		case <-retry.C:
+			if len(t.matched) == 0 { // not paired by another ticket meanwhile
+				cs.pair(t)
+			}
+		case <-stream.Context().Done():
+			cs.leaveQueue(t)
+			return nil
+		case <-cs.draining:
+			cs.leaveQueue(t)
+			return errShuttingDown
+		}
+	}
+}
+
+// pair looks for an opponent for t in the queue, reporting whether a game was created for them. A t
+// no longer queued was paired already.
+func (cs *connect4Server) pair(t *ticket) bool {
+	cs.queueMut.Lock()
+	defer cs.queueMut.Unlock()
+	if !slices.Contains(cs.queue, t) {
+		return false // paired by another ticket, its seat waiting in t.matched
+	}
+	now := time.Now()
+	i := slices.IndexFunc(cs.queue, func(o *ticket) bool { return o != t && compatible(t, o, now) })
+	if i < 0 {
+		return false
+	}
+	opponent := cs.queue[i]
+	red, yellow := t, opponent
+	if rand.IntN(2) == 0 { //nolint:gosec // colors don't need a secure source
+		red, yellow = yellow, red
+	}
+	board, _ := engine.New(t.variant) // validated in FindMatch
+	redToken, yellowToken := newToken(), newToken()
+	g := &game{
+		variant:     t.variant,
+		board:       board,
+		red:         true,
+		yellow:      true,
+		redName:     red.name,
+		yellowName:  yellow.name,
+		redToken:    redToken,
+		yellowToken: yellowToken,
+		redUser:     red.user,
+		yellowUser:  yellow.user,
+		host:        red.name,
+		clock:       newClock(t.control),
+		rated:       t.rated,
+	}
+	id, err := cs.addGame(g)
+	if err != nil {
+		return false // both stay in the queue until a game ends
+	}
+	cs.queue = slices.DeleteFunc(cs.queue, func(o *ticket) bool { return o == t || o == opponent })
+	variant := pb.NewVariant(t.variant)
+	red.matched <- &pb.GameIDAndTeam{Id: &id, Team: pb.Team_red.Enum(), Variant: variant, ResumeToken: &redToken}
+	yellow.matched <- &pb.GameIDAndTeam{Id: &id, Team: pb.Team_yellow.Enum(), Variant: variant, ResumeToken: &yellowToken}
+	return true
+}
+
+// leaveQueue removes t from the queue, giving up the seat if it was paired in the meantime.
+func (cs *connect4Server) leaveQueue(t *ticket) {
+	cs.queueMut.Lock()
+	cs.queue = slices.DeleteFunc(cs.queue, func(o *ticket) bool { return o == t })
+	cs.queueMut.Unlock()
+	select {
+	case seat := <-t.matched:
+		_, _ = cs.LeaveGame(context.WithValue(context.Background(), userKey{}, t.user), seat)
+	default:
+	}
+}
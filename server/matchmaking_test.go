@@ -0,0 +1,188 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/geofpwhite/connect4-grpc/engine"
+	"github.com/geofpwhite/connect4-grpc/pb"
+	"google.golang.org/grpc"
+)
+
+func findMatch(
+	ctx context.Context, t *testing.T, client pb.Connect4Client, req *pb.FindMatchRequest,
+) grpc.ServerStreamingClient[pb.MatchUpdate] {
+	t.Helper()
+	stream, err := client.FindMatch(ctx, req)
+	if err != nil {
+		t.Fatalf("Failed to find a match: %v", err)
+	}
+	return stream
+}
+
+func TestFindMatch(t *testing.T) {
+	client := startServer(t)
+	aliceCtx, bobCtx, carolCtx := guest(t, client), guest(t, client), guest(t, client)
+	alice, bob, carol := "alice", "bob", "carol"
+	six := int32(6)
+
+	first := findMatch(aliceCtx, t, client, &pb.FindMatchRequest{PlayerName: &alice})
+	update, err := first.Recv()
+	if err != nil {
+		t.Fatalf("Failed to enter the queue: %v", err)
+	}
+	if update.GetQueued() != 1 || update.GetMatch() != nil {
+		t.Fatalf("expected to wait alone in the queue, got %v", update)
+	}
+	// a different board doesn't pair with alice
+	other := findMatch(carolCtx, t, client, &pb.FindMatchRequest{PlayerName: &carol, Variant: &pb.Variant{Rows: &six}})
+	if update, err = other.Recv(); err != nil || update.GetQueued() != 2 {
+		t.Fatalf("expected carol to wait in the queue, got %v, %v", update, err)
+	}
+
+	second := findMatch(bobCtx, t, client, &pb.FindMatchRequest{PlayerName: &bob})
+	seats := make(map[pb.Team]*pb.GameIDAndTeam)
+	owners := make(map[pb.Team]context.Context)
+	for ctx, stream := range map[context.Context]grpc.ServerStreamingClient[pb.MatchUpdate]{aliceCtx: first, bobCtx: second} {
+		update, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Failed to get matched: %v", err)
+		}
+		seat := update.GetMatch()
+		if seat == nil {
+			t.Fatalf("expected a match, got %v", update)
+		}
+		seats[seat.GetTeam()], owners[seat.GetTeam()] = seat, ctx
+	}
+	red, yellow := seats[pb.Team_red], seats[pb.Team_yellow]
+	if red == nil || yellow == nil || red.GetId() != yellow.GetId() {
+		t.Fatalf("expected both colors of one game, got %v", seats)
+	}
+
+	redStream := register(owners[pb.Team_red], t, client, red)
+	register(owners[pb.Team_yellow], t, client, yellow)
+	col := int32(1)
+	err = redStream.Send(&pb.Input{GameId: red.Id, Action: &pb.Input_Column{Column: col}, InputTeam: pb.Team_red.Enum()})
+	if err != nil {
+		t.Fatalf("Failed to send move: %v", err)
+	}
+	state, err := redStream.Recv()
+	if err != nil {
+		t.Fatalf("Failed to receive the move: %v", err)
+	}
+	if p := state.GetPlayers(); p.GetRed() == p.GetYellow() || (p.GetRed() != alice && p.GetRed() != bob) {
+		t.Fatalf("expected alice against bob, got %v", p)
+	}
+}
+
+func TestFindMatchCancel(t *testing.T) {
+	cs := newServer()
+	client := serve(t, cs)
+	ctx, cancel := context.WithCancel(guest(t, client))
+	stream := findMatch(ctx, t, client, &pb.FindMatchRequest{})
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("Failed to enter the queue: %v", err)
+	}
+	cancel()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		cs.queueMut.Lock()
+		queued := len(cs.queue)
+		cs.queueMut.Unlock()
+		if queued == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the canceled player to leave the queue")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if cs.games.len() != 0 {
+		t.Fatalf("expected no game to be created, got %d", cs.games.len())
+	}
+}
+
+func TestMatchUnattended(t *testing.T) {
+	cs := newServer()
+	cs.grace = 100 * time.Millisecond
+	client := serve(t, cs)
+	control := fischer(time.Minute, 0)
+	first := findMatch(guest(t, client), t, client, &pb.FindMatchRequest{TimeControl: control})
+	if _, err := first.Recv(); err != nil {
+		t.Fatalf("Failed to enter the queue: %v", err)
+	}
+	findMatch(guest(t, client), t, client, &pb.FindMatchRequest{TimeControl: control})
+	update, err := first.Recv()
+	if err != nil || update.GetMatch() == nil {
+		t.Fatalf("expected a match, got %v, %v", update, err)
+	}
+	g, _ := cs.games.get(update.GetMatch().GetId())
+	g.mut.RLock()
+	running := !g.clock.since.IsZero()
+	g.mut.RUnlock()
+	if running {
+		t.Fatal("expected the clock to wait for a player to attach")
+	}
+	// neither player comes, so the seats are released and the game ends
+	deadline := time.Now().Add(5 * time.Second)
+	for cs.games.len() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the unattended game to be removed")
+		}
+		time.Sleep(cs.grace / 4)
+	}
+}
+
+func TestRetryAfterMatch(t *testing.T) {
+	cs := newServer()
+	tickets := make([]*ticket, 3)
+	for i := range tickets {
+		user := string(rune('a' + i))
+		tickets[i] = &ticket{variant: engine.Classic, user: user, since: time.Now(), matched: make(chan *pb.GameIDAndTeam, 1)}
+	}
+	cs.queue = []*ticket{tickets[0], tickets[1]}
+	if !cs.pair(tickets[1]) {
+		t.Fatal("expected the first two tickets to be paired")
+	}
+	cs.queue = append(cs.queue, tickets[2])
+	// the retry of the first ticket fires before it reads its seat
+	paired := make(chan bool)
+	go func() { paired <- cs.pair(tickets[0]) }()
+	select {
+	case ok := <-paired:
+		if ok {
+			t.Fatal("expected a ticket already matched not to be paired again")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("pair blocked holding queueMut")
+	}
+	if len(cs.queue) != 1 || len(tickets[2].matched) != 0 {
+		t.Fatalf("expected the third ticket to keep waiting, the queue has %d tickets", len(cs.queue))
+	}
+}
+
+func TestRatingWindow(t *testing.T) {
+	now := time.Now()
+	a := &ticket{variant: engine.DefaultVariant, user: "a", rated: true, rating: 1500, since: now}
+	b := &ticket{variant: engine.DefaultVariant, user: "b", rated: true, rating: 1800, since: now}
+	if compatible(a, b, now) {
+		t.Fatal("expected a 300 points gap to be too wide at first")
+	}
+	if !compatible(a, b, now.Add(30*time.Second)) {
+		t.Fatal("expected the window to widen over time")
+	}
+	b.rated = false
+	if compatible(a, b, now.Add(time.Hour)) {
+		t.Fatal("expected rated and unrated players not to be paired")
+	}
+	a.rated = false
+	a.rating = 3000
+	if !compatible(a, b, now) {
+		t.Fatal("expected unrated players to be paired regardless of rating")
+	}
+	b.user = a.user
+	if compatible(a, b, now) {
+		t.Fatal("expected a player not to be paired with themselves")
+	}
+}
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/geofpwhite/connect4-grpc/engine"
+	"github.com/geofpwhite/connect4-grpc/notation"
+	"github.com/geofpwhite/connect4-grpc/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+var errPositionOver = status.Error(codes.InvalidArgument, "the game of the position is over")
+
+func (cs *connect4Server) ExportGame(_ context.Context, req *pb.ExportGameRequest) (*pb.GameNotation, error) {
+	rec, err := cs.lookup(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	i := len(rec.Boards) - 1
+	if req.Board != nil {
+		i = int(req.GetBoard())
+	}
+	if i < 0 || i >= len(rec.Boards) {
+		return nil, errNoSuchBoard
+	}
+	g := replayOf(rec)
+	if err := g.replayBoard(rec.start(i), rec.Boards[i], func(*MoveRecord) error { return nil }); err != nil {
+		return nil, err
+	}
+	date := rec.CreatedAt
+	if moves := rec.Boards[i]; len(moves) > 0 {
+		date = moves[0].At
+	}
+	game := &notation.Game{
+		Tags: []notation.Tag{
+			{Name: "Game", Value: strconv.Itoa(int(rec.ID))},
+			{Name: "Board", Value: strconv.Itoa(i + 1)},
+			{Name: "Red", Value: rec.Red.Name},
+			{Name: "Yellow", Value: rec.Yellow.Name},
+			{Name: "Date", Value: date.UTC().Format("2006.01.02")},
+			{Name: "Variant", Value: rec.Variant.String()},
+			{Name: "Result", Value: resultToken(g.result())},
+		},
+		Moves: g.board.Moves(),
+	}
+	if rec.start(i) == engine.Yellow {
+		game.Tags = append(game.Tags, notation.Tag{Name: "First", Value: "Yellow"})
+	}
+	if g.endReason() == pb.EndReason_timeout {
+		game.Tags = append(game.Tags, notation.Tag{Name: "Termination", Value: "time forfeit"})
+	}
+	text := game.String()
+	return &pb.GameNotation{Notation: &text}, nil
+}
+
+func resultToken(r pb.Result) string {
+	switch r {
+	case pb.Result_red_won:
+		return notation.RedWon
+	case pb.Result_yellow_won:
+		return notation.YellowWon
+	case pb.Result_draw:
+		return notation.Draw
+	case pb.Result_in_progress:
+	}
+	return notation.InProgress
+}
+
+func (cs *connect4Server) ImportPosition(ctx context.Context, req *pb.ImportPositionRequest) (*pb.GameIDAndTeam, error) {
+	position, err := notation.Parse(req.GetNotation())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	variant, _ := position.Variant() // checked by Parse
+	first, _ := position.First()
+	settings := &pb.NewGameRequest{}
+	if req.GetGame() != nil {
+		settings = proto.CloneOf(req.GetGame())
+	}
+	settings.Variant = pb.NewVariant(variant)
+	g, err := cs.newGame(ctx, settings)
+	if err != nil {
+		return nil, err
+	}
+	g.boards, g.starts = [][]MoveRecord{nil}, []engine.Piece{first}
+	g.board, _ = engine.NewStarting(variant, first)
+	now := time.Now()
+	for _, col := range position.Moves {
+		if err := g.play(col, now); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	if g.over() {
+		return nil, errPositionOver
+	}
+	token := g.redToken
+	id, err := cs.addGame(g)
+	if err != nil {
+		return nil, err
+	}
+	g.wakeBot()
+	return &pb.GameIDAndTeam{Id: &id, Team: pb.Team_red.Enum(), Variant: pb.NewVariant(variant), ResumeToken: &token}, nil
+}
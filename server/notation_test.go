@@ -0,0 +1,108 @@
+package server
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/geofpwhite/connect4-grpc/notation"
+	"github.com/geofpwhite/connect4-grpc/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestExportGame(t *testing.T) {
+	client := startServer(t)
+	redCtx, yellowCtx := guest(t, client), guest(t, client)
+	name := "alice"
+	resp, err := client.NewGame(redCtx, &pb.NewGameRequest{PlayerName: &name})
+	if err != nil {
+		t.Fatalf("Failed to start a new game: %v", err)
+	}
+	join, err := client.JoinGame(yellowCtx, &pb.JoinGameRequest{Id: resp.Id})
+	if err != nil {
+		t.Fatalf("Failed to join the game: %v", err)
+	}
+	red := register(redCtx, t, client, resp)
+	yellow := register(yellowCtx, t, client, join)
+	playMoves(t, resp.Id, red, yellow, 1, 1, 2, 2, 3, 3, 4)
+	rematch(t, resp.Id, red, yellow)
+	act(t, resp.Id, pb.Team_yellow, yellow, &pb.Input{Action: &pb.Input_Column{Column: 5}})
+	recv(t, red)
+
+	board := int32(0)
+	exported, err := client.ExportGame(redCtx, &pb.ExportGameRequest{Id: resp.Id, Board: &board})
+	if err != nil {
+		t.Fatalf("Failed to export: %v", err)
+	}
+	game, err := notation.Parse(exported.GetNotation())
+	if err != nil {
+		t.Fatalf("Failed to parse the export %q: %v", exported.GetNotation(), err)
+	}
+	if game.Tag("Red") != "alice" || game.Tag("Result") != notation.RedWon || game.Tag("Board") != "1" ||
+		!slices.Equal(game.Moves, []int{0, 0, 1, 1, 2, 2, 3}) {
+		t.Fatalf("unexpected export %q", exported.GetNotation())
+	}
+	exported, err = client.ExportGame(redCtx, &pb.ExportGameRequest{Id: resp.Id})
+	if err != nil {
+		t.Fatalf("Failed to export: %v", err)
+	}
+	if game, err = notation.Parse(exported.GetNotation()); err != nil || game.Tag("Result") != notation.InProgress ||
+		game.Tag("First") != "Yellow" || !slices.Equal(game.Moves, []int{4}) {
+		t.Fatalf("expected the board in play by default, got %q, %v", exported.GetNotation(), err)
+	}
+	board = 2
+	if _, err := client.ExportGame(redCtx, &pb.ExportGameRequest{Id: resp.Id, Board: &board}); status.Code(err) != codes.OutOfRange {
+		t.Fatalf("expected OutOfRange exporting a missing board, got %v", err)
+	}
+}
+
+func TestImportPosition(t *testing.T) {
+	client := startServer(t)
+	ctx := guest(t, client)
+	position := "[Variant \"7x6 connect 4\"]\n\n1. 4 4 2. 3"
+	seat, err := client.ImportPosition(ctx, &pb.ImportPositionRequest{
+		Notation: &position,
+		Game:     &pb.NewGameRequest{Opponent: pb.Opponent_bot.Enum()},
+	})
+	if err != nil {
+		t.Fatalf("Failed to import: %v", err)
+	}
+	if v := seat.GetVariant(); v.GetColumns() != 7 || v.GetRows() != 6 {
+		t.Fatalf("expected the variant of the notation, got %v", v)
+	}
+	// the bot answered the position, yellow being to move
+	stream := register(ctx, t, client, seat)
+	history, err := client.GetGameHistory(ctx, &pb.GameID{Id: seat.Id})
+	if err != nil {
+		t.Fatalf("Failed to get the history: %v", err)
+	}
+	if moves := history.GetBoards()[0].GetMoves(); len(moves) != 4 || moves[3].GetTeam() != pb.Team_yellow {
+		t.Fatalf("expected the 3 imported moves and the bot answer, got %v", moves)
+	}
+	col := int32(1)
+	err = stream.Send(&pb.Input{GameId: seat.Id, Action: &pb.Input_Column{Column: col}, InputTeam: pb.Team_red.Enum()})
+	if err != nil {
+		t.Fatalf("Failed to send move: %v", err)
+	}
+	s, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Failed to receive state: %v", err)
+	}
+	discs := 0
+	for _, row := range s.GetField().GetRows() {
+		for _, v := range row.GetValues() {
+			if v != pb.Team_empty {
+				discs++
+			}
+		}
+	}
+	if discs != 5 || s.GetTurn() != pb.Team_yellow {
+		t.Fatalf("expected to play on from the position, got %v", s)
+	}
+
+	for _, bad := range []string{"1 1 2 2 3 3 4", "4 x", "[Variant \"2x2 connect 2\"]\n\n1"} {
+		if _, err := client.ImportPosition(ctx, &pb.ImportPositionRequest{Notation: &bad}); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("expected InvalidArgument importing %q, got %v", bad, err)
+		}
+	}
+}
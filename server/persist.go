@@ -0,0 +1,152 @@
+package server
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"fortio.org/log"
+	"github.com/geofpwhite/connect4-grpc/bot"
+	"github.com/geofpwhite/connect4-grpc/engine"
+	"github.com/geofpwhite/connect4-grpc/pb"
+)
+
+// record snapshots g for the store. Called with g.mut held.
+func (g *game) record(id int32) *GameRecord {
+	rec := &GameRecord{
+		ID:          id,
+		Variant:     g.variant,
+		CreatedAt:   g.createdAt,
+		Host:        g.host,
+		Private:     g.private,
+		FirstTo:     g.firstTo,
+		Red:         SeatRecord{Taken: g.red, Name: g.redName, User: g.redUser, Token: g.redToken},
+		Yellow:      SeatRecord{Taken: g.yellow, Name: g.yellowName, User: g.yellowUser, Token: g.yellowToken},
+		Boards:      make([][]MoveRecord, len(g.boards)),
+		Starts:      slices.Clone(g.starts),
+		Next:        g.next,
+		FirstPlayer: g.firstPlayer,
+		Rated:       g.rated,
+		RatedBoards: g.ratedBoards,
+		RedWins:     g.redWins,
+		YellowWins:  g.yellowWins,
+		Draws:       g.draws,
+	}
+	for i, moves := range g.boards {
+		rec.Boards[i] = slices.Clone(moves)
+	}
+	if g.bot != nil {
+		rec.BotLevel = g.bot.player.Level()
+	}
+	if g.clock != nil {
+		now := time.Now()
+		rec.Clock = &ClockRecord{Control: g.clock.control, Red: g.timeLeft(engine.Red, now), Yellow: g.timeLeft(engine.Yellow, now)}
+	}
+	return rec
+}
+
+// save writes g to the store. Failures are only logged, the game going on in memory.
+func (cs *connect4Server) save(id int32, g *game) {
+	cs.write(id, g, time.Time{})
+}
+
+// finish saves g one last time once it was removed from the server.
+func (cs *connect4Server) finish(id int32, g *game) {
+	cs.write(id, g, time.Now())
+}
+
+func (cs *connect4Server) write(id int32, g *game, finished time.Time) {
+	// saves of a game must not overtake each other
+	cs.saveMut.Lock()
+	defer cs.saveMut.Unlock()
+	g.mut.RLock()
+	rec := g.record(id)
+	g.mut.RUnlock()
+	rec.FinishedAt = finished
+	if err := cs.store.Save(rec); err != nil {
+		log.Errf("can't save game %d: %v", id, err)
+	}
+}
+
+// restore brings back the games left unfinished in the store. Human players have the grace period
+// to resume their seats, like after a disconnection.
+func (cs *connect4Server) restore() error {
+	records, err := cs.store.Active()
+	if err != nil {
+		return err
+	}
+	for _, rec := range records {
+		g, err := fromRecord(rec)
+		if err != nil {
+			return fmt.Errorf("restoring game %d: %w", rec.ID, err)
+		}
+		id := rec.ID
+		cs.holdSeats(id, g)
+		// the clock waits for a player to attach, so the time the server was down isn't charged
+		cs.armClock(id, g)
+		cs.games.put(id, g)
+		cs.startBot(id, g)
+		g.wakeBot() // the server may have stopped while the bot was thinking
+	}
+	return nil
+}
+
+func fromRecord(rec *GameRecord) (*game, error) {
+	boards := rec.Boards
+	if len(boards) == 0 {
+		boards = [][]MoveRecord{nil}
+	}
+	starts := make([]engine.Piece, len(boards))
+	for i := range starts {
+		starts[i] = rec.start(i)
+	}
+	board, err := engine.NewStarting(rec.Variant, starts[len(starts)-1])
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range boards[len(boards)-1] {
+		if m.End != "" {
+			continue
+		}
+		if _, err := board.Drop(m.Column); err != nil {
+			return nil, err
+		}
+	}
+	g := &game{
+		variant:     rec.Variant,
+		board:       board,
+		boards:      boards,
+		starts:      starts,
+		next:        rec.Next,
+		firstPlayer: rec.FirstPlayer,
+		rated:       rec.Rated,
+		ratedBoards: rec.RatedBoards,
+		red:         rec.Red.Taken,
+		yellow:      rec.Yellow.Taken,
+		redWins:     rec.RedWins,
+		yellowWins:  rec.YellowWins,
+		draws:       rec.Draws,
+		firstTo:     rec.FirstTo,
+		redName:     rec.Red.Name,
+		yellowName:  rec.Yellow.Name,
+		redToken:    rec.Red.Token,
+		yellowToken: rec.Yellow.Token,
+		redUser:     rec.Red.User,
+		yellowUser:  rec.Yellow.User,
+		host:        rec.Host,
+		createdAt:   rec.CreatedAt,
+		private:     rec.Private,
+	}
+	if rec.BotLevel > 0 {
+		player, err := bot.New(rec.BotLevel)
+		if err != nil {
+			return nil, err
+		}
+		g.bot = newBotSeat(pb.Team_yellow, player)
+	}
+	if rec.Clock != nil {
+		g.clock = &clock{control: rec.Clock.Control, red: rec.Clock.Red, yellow: rec.Clock.Yellow}
+	}
+	g.setup()
+	return g, nil
+}
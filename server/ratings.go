@@ -0,0 +1,212 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"fortio.org/log"
+	"github.com/geofpwhite/connect4-grpc/pb"
+	"github.com/geofpwhite/connect4-grpc/rating"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const guestPrefix = "guest~"
+
+var (
+	errGuestRated = status.Error(codes.FailedPrecondition, "rated games need an account")
+	errBotRated   = status.Error(codes.FailedPrecondition, "this server doesn't rate bot games")
+	errNoProfile  = status.Error(codes.NotFound, "no such account")
+)
+
+func isGuest(username string) bool {
+	return strings.HasPrefix(username, guestPrefix)
+}
+
+// botUser is the username the bot of that level is rated under, when bot games are rated.
+func botUser(level int) string {
+	return fmt.Sprintf("bot~%d", level)
+}
+
+// glicko returns the rating of u, the default one before its first rated game.
+func (u user) glicko() rating.Rating {
+	if u.Rating == (rating.Rating{}) {
+		return rating.Default()
+	}
+	return u.Rating
+}
+
+func (u user) games() int {
+	return u.Wins + u.Losses + u.Draws
+}
+
+func (s *userStore) rating(username string) rating.Rating {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return s.users[username].glicko()
+}
+
+// rate records the result of a rated game between red and yellow, creating the accounts of bots on
+// their first game, and returns how their ratings changed.
+func (s *userStore) rate(red, yellow string, result pb.Result) (*pb.RatingChanges, error) {
+	score := rating.Draw
+	switch result {
+	case pb.Result_red_won:
+		score = rating.Win
+	case pb.Result_yellow_won:
+		score = rating.Loss
+	case pb.Result_draw, pb.Result_in_progress:
+	}
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	r, y := s.users[red], s.users[yellow]
+	before := []user{r, y}
+	rr, yr := r.glicko(), y.glicko()
+	r.Rating = rr.Update(rating.Result{Opponent: yr, Score: score})
+	y.Rating = yr.Update(rating.Result{Opponent: rr, Score: 1 - score})
+	for i, u := range []*user{&r, &y} {
+		switch {
+		case score == rating.Draw:
+			u.Draws++
+		case (score == rating.Win) == (i == 0):
+			u.Wins++
+		default:
+			u.Losses++
+		}
+		if u.Created.IsZero() {
+			u.Created = time.Now()
+		}
+	}
+	s.users[red], s.users[yellow] = r, y
+	if err := s.save(); err != nil {
+		s.users[red], s.users[yellow] = before[0], before[1]
+		return nil, err
+	}
+	return &pb.RatingChanges{
+		Red:    &pb.RatingChange{Before: &rr.Rating, After: &r.Rating.Rating},
+		Yellow: &pb.RatingChange{Before: &yr.Rating, After: &y.Rating.Rating},
+	}, nil
+}
+
+// ranked returns the players who finished a rated game, best rated first.
+func (s *userStore) ranked() []*pb.PlayerProfile {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	var players []*pb.PlayerProfile
+	for name, u := range s.users {
+		if u.games() > 0 {
+			players = append(players, u.profile(name))
+		}
+	}
+	slices.SortFunc(players, func(a, b *pb.PlayerProfile) int {
+		return cmp.Or(cmp.Compare(b.GetRating(), a.GetRating()), cmp.Compare(a.GetUsername(), b.GetUsername()))
+	})
+	for i, p := range players {
+		rank := int32(i + 1) //nolint:gosec // can't have that many accounts
+		p.Rank = &rank
+	}
+	return players
+}
+
+//nolint:gosec // counters stay far below int32 limits
+func (u user) profile(username string) *pb.PlayerProfile {
+	r := u.glicko()
+	wins, losses, draws := int32(u.Wins), int32(u.Losses), int32(u.Draws)
+	return &pb.PlayerProfile{
+		Username:  &username,
+		Rating:    &r.Rating,
+		Deviation: &r.Deviation,
+		Wins:      &wins,
+		Losses:    &losses,
+		Draws:     &draws,
+		CreatedAt: timestamppb.New(u.Created),
+	}
+}
+
+// rate updates the ratings of the players once a board of a rated game is over. Runs on the actor
+// of g, without g.mut held.
+func (cs *connect4Server) rate(g *game) {
+	g.mut.Lock()
+	defer g.mut.Unlock()
+	if !g.rated || !g.over() || g.ratedBoards == len(g.boards) {
+		return
+	}
+	g.ratedBoards = len(g.boards)
+	red, yellow := g.redUser, g.yellowUser
+	if g.bot != nil {
+		yellow = botUser(g.bot.player.Level())
+	}
+	if red == "" || yellow == "" || red == yellow {
+		return // a seat was left, or taken by the same account
+	}
+	changes, err := cs.users.rate(red, yellow, g.result())
+	if err != nil {
+		log.Errf("can't save the ratings of %s and %s: %v", red, yellow, err)
+		return
+	}
+	g.ratingChanges = changes
+}
+
+func (cs *connect4Server) GetPlayerProfile(ctx context.Context, req *pb.PlayerProfileRequest) (*pb.PlayerProfile, error) {
+	name := req.GetUsername()
+	if name == "" {
+		name = caller(ctx)
+	}
+	cs.users.mut.Lock()
+	u, exists := cs.users.users[name]
+	cs.users.mut.Unlock()
+	if !exists {
+		return nil, errNoProfile
+	}
+	profile := u.profile(name)
+	if u.games() > 0 {
+		i := slices.IndexFunc(cs.users.ranked(), func(p *pb.PlayerProfile) bool { return p.GetUsername() == name })
+		rank := int32(i + 1) //nolint:gosec // can't have that many accounts
+		profile.Rank = &rank
+	}
+	return profile, nil
+}
+
+func (cs *connect4Server) GetLeaderboard(_ context.Context, req *pb.LeaderboardRequest) (*pb.Leaderboard, error) {
+	size := int(req.GetPageSize())
+	switch {
+	case size < 0:
+		return nil, status.Errorf(codes.InvalidArgument, "page_size must not be negative, got %d", size)
+	case size == 0:
+		size = defaultPageSize
+	case size > maxPageSize:
+		size = maxPageSize
+	}
+	players := cs.users.ranked()
+	if token := req.GetPageToken(); token != "" {
+		// the token is the rating and username of the last player of the previous page, ratings
+		// changing between pages
+		r, name, found := strings.Cut(token, ":")
+		after, err := strconv.ParseFloat(r, 64)
+		if !found || err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page_token %q", token)
+		}
+		start := slices.IndexFunc(players, func(p *pb.PlayerProfile) bool {
+			return cmp.Or(cmp.Compare(after, p.GetRating()), cmp.Compare(p.GetUsername(), name)) > 0
+		})
+		if start < 0 {
+			start = len(players)
+		}
+		players = players[start:]
+	}
+	resp := &pb.Leaderboard{}
+	if len(players) > size {
+		players = players[:size]
+		last := players[size-1]
+		next := strconv.FormatFloat(last.GetRating(), 'g', -1, 64) + ":" + last.GetUsername()
+		resp.NextPageToken = &next
+	}
+	resp.Players = players
+	return resp, nil
+}
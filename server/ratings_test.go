@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/geofpwhite/connect4-grpc/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// account returns a context carrying the session of a new account.
+func account(t *testing.T, client pb.Connect4Client, name string) context.Context {
+	t.Helper()
+	password := "correct horse"
+	session, err := client.Register(context.Background(), &pb.Credentials{Username: &name, Password: &password})
+	if err != nil {
+		t.Fatalf("Failed to register %s: %v", name, err)
+	}
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+session.GetToken())
+}
+
+func TestRatedGame(t *testing.T) {
+	client := startServer(t)
+	aliceCtx, bobCtx := account(t, client, "alice"), account(t, client, "bob")
+	rated := true
+	resp, err := client.NewGame(aliceCtx, &pb.NewGameRequest{Rated: &rated})
+	if err != nil {
+		t.Fatalf("Failed to start a rated game: %v", err)
+	}
+	if _, err := client.JoinGame(guest(t, client), &pb.JoinGameRequest{Id: resp.Id}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition for a guest joining a rated game, got %v", err)
+	}
+	join, err := client.JoinGame(bobCtx, &pb.JoinGameRequest{Id: resp.Id})
+	if err != nil {
+		t.Fatalf("Failed to join the game: %v", err)
+	}
+	red := register(aliceCtx, t, client, resp)
+	yellow := register(bobCtx, t, client, join)
+
+	state := playMoves(t, resp.Id, red, yellow, 1, 1, 2, 2, 3, 3, 4)
+	changes := state.GetRatingChanges()
+	if changes.GetRed().GetBefore() != 1500 || changes.GetRed().GetAfter() <= 1500 || changes.GetYellow().GetAfter() >= 1500 {
+		t.Fatalf("expected the winner to gain and the loser to lose points, got %v", changes)
+	}
+	state = rematch(t, resp.Id, red, yellow)
+	if state.GetRatingChanges() != nil {
+		t.Fatalf("expected the changes to go with the board, got %v", state.GetRatingChanges())
+	}
+
+	// profiles and the leaderboard need no session
+	ctx := context.Background()
+	name := "bob"
+	bob, err := client.GetPlayerProfile(ctx, &pb.PlayerProfileRequest{Username: &name})
+	if err != nil {
+		t.Fatalf("Failed to get a profile: %v", err)
+	}
+	if bob.GetLosses() != 1 || bob.GetWins() != 0 || bob.GetRank() != 2 || bob.GetRating() != changes.GetYellow().GetAfter() {
+		t.Fatalf("unexpected profile %v", bob)
+	}
+	alice, err := client.GetPlayerProfile(aliceCtx, &pb.PlayerProfileRequest{})
+	if err != nil || alice.GetUsername() != "alice" || alice.GetWins() != 1 || alice.GetRank() != 1 {
+		t.Fatalf("expected the profile of the caller, got %v, %v", alice, err)
+	}
+	unknown := "carol"
+	if _, err := client.GetPlayerProfile(ctx, &pb.PlayerProfileRequest{Username: &unknown}); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound for an unknown account, got %v", err)
+	}
+	pageSize := int32(1)
+	req := &pb.LeaderboardRequest{PageSize: &pageSize}
+	var ranked []string
+	for {
+		page, err := client.GetLeaderboard(ctx, req)
+		if err != nil {
+			t.Fatalf("Failed to get the leaderboard: %v", err)
+		}
+		for _, p := range page.GetPlayers() {
+			ranked = append(ranked, p.GetUsername())
+		}
+		if page.GetNextPageToken() == "" {
+			break
+		}
+		req.PageToken = page.NextPageToken
+	}
+	if len(ranked) != 2 || ranked[0] != "alice" || ranked[1] != "bob" {
+		t.Fatalf("expected alice then bob, got %v", ranked)
+	}
+}
+
+func TestRatedRefusals(t *testing.T) {
+	cs := newServer()
+	client := serve(t, cs)
+	rated := true
+	if _, err := client.NewGame(guest(t, client), &pb.NewGameRequest{Rated: &rated}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition for a rated game of a guest, got %v", err)
+	}
+	ctx := account(t, client, "alice")
+	level := int32(1)
+	vsBot := &pb.NewGameRequest{Rated: &rated, Opponent: pb.Opponent_bot.Enum(), BotLevel: &level}
+	if _, err := client.NewGame(ctx, vsBot); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition for a rated bot game, got %v", err)
+	}
+	cs.rateBots = true
+	if _, err := client.NewGame(ctx, vsBot); err != nil {
+		t.Fatalf("Failed to start a rated bot game: %v", err)
+	}
+	stream, err := client.FindMatch(guest(t, client), &pb.FindMatchRequest{Rated: &rated})
+	if err != nil {
+		t.Fatalf("Failed to look for a match: %v", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition for a guest looking for a rated game, got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package server
+
+import (
+	"math/rand/v2"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var errTooManyGames = status.Error(codes.ResourceExhausted, "too many games in play, try again later")
+
+// registry holds the live games, safe for concurrent use.
+type registry struct {
+	mut    sync.RWMutex
+	games  map[int32]*game
+	limit  int  // most games reserved at once, 0 for no limit
+	closed bool // no game can be reserved anymore, the server shutting down
+}
+
+func newRegistry(limit int) *registry {
+	return &registry{games: make(map[int32]*game), limit: limit}
+}
+
+func (r *registry) get(id int32) (*game, bool) {
+	r.mut.RLock()
+	defer r.mut.RUnlock()
+	g := r.games[id]
+	return g, g != nil
+}
+
+// reserve returns a new random id, skipping the ids of live games and the ones stored reports as
+// used by finished games. The id is kept for the game put under it once set up.
+func (r *registry) reserve(stored func(int32) bool) (int32, error) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	if r.closed {
+		return 0, errShuttingDown
+	}
+	if r.limit > 0 && len(r.games) >= r.limit {
+		return 0, errTooManyGames
+	}
+	id := rand.Int32() //nolint: gosec // it's just the game id
+	for r.has(id) || stored(id) {
+		id = rand.Int32() //nolint: gosec // it's just the game id
+	}
+	r.games[id] = nil
+	return id, nil
+}
+
+// close makes reserve fail from now on.
+func (r *registry) close() {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	r.closed = true
+}
+
+func (r *registry) has(id int32) bool {
+	_, exists := r.games[id]
+	return exists
+}
+
+// put registers g under id, reserved or restored from the store.
+func (r *registry) put(id int32, g *game) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	r.games[id] = g
+}
+
+// remove deletes the game registered as id if it still is g.
+func (r *registry) remove(id int32, g *game) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	if r.games[id] == g {
+		delete(r.games, id)
+	}
+}
+
+// all returns a copy of the games by id.
+func (r *registry) all() map[int32]*game {
+	r.mut.RLock()
+	defer r.mut.RUnlock()
+	games := make(map[int32]*game, len(r.games))
+	for id, g := range r.games {
+		if g != nil {
+			games[id] = g
+		}
+	}
+	return games
+}
+
+func (r *registry) len() int {
+	return len(r.all())
+}
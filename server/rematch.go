@@ -0,0 +1,50 @@
+package server
+
+import (
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/geofpwhite/connect4-grpc/engine"
+	"github.com/geofpwhite/connect4-grpc/pb"
+)
+
+var errBoardInPlay = errors.New("board is still in play")
+
+// The following methods are called with g.mut held.
+
+// startBoard begins the next board of the series, g.next moving first.
+func (g *game) startBoard(now time.Time) {
+	g.boards = append(g.boards, nil)
+	g.starts = append(g.starts, g.next)
+	g.board, _ = engine.NewStarting(g.variant, g.next) // variant was validated in NewGame
+	g.next = engine.Empty
+	g.ratingChanges = nil
+	g.resetClock()
+	g.startClock(now)
+}
+
+// nextFirst picks who moves first on the board after the one that just ended.
+func (g *game) nextFirst() engine.Piece {
+	switch g.firstPlayer {
+	case pb.FirstPlayer_loser_starts:
+		switch g.result() {
+		case pb.Result_red_won:
+			return engine.Yellow
+		case pb.Result_yellow_won:
+			return engine.Red
+		case pb.Result_draw, pb.Result_in_progress:
+		}
+	case pb.FirstPlayer_random_first:
+		return engine.Piece(1 + rand.IntN(2)) //nolint:gosec // no need for crypto randomness
+	case pb.FirstPlayer_alternate:
+	}
+	return g.starts[len(g.starts)-1].Opponent()
+}
+
+func (g *game) nextFirstTeam() *pb.Team {
+	if g.next == engine.Empty {
+		return nil
+	}
+	return pb.Team(g.next).Enum()
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/geofpwhite/connect4-grpc/engine"
+	"github.com/geofpwhite/connect4-grpc/pb"
+	"google.golang.org/grpc"
+)
+
+// rematch has red offer the next board and yellow agree, returning the state of the new board.
+func rematch(t *testing.T, id *int32, red, yellow grpc.BidiStreamingClient[pb.Input, pb.State]) *pb.State {
+	t.Helper()
+	act(t, id, pb.Team_red, red, &pb.Input{Action: &pb.Input_Rematch{Rematch: &pb.Empty{}}})
+	recv(t, yellow)
+	state := act(t, id, pb.Team_yellow, yellow, &pb.Input{Action: &pb.Input_Rematch{Rematch: &pb.Empty{}}})
+	recv(t, red)
+	return state
+}
+
+func TestRematch(t *testing.T) {
+	client := startServer(t)
+	redCtx, yellowCtx := guest(t, client), guest(t, client)
+	resp, err := client.NewGame(redCtx, &pb.NewGameRequest{})
+	if err != nil {
+		t.Fatalf("Failed to start a new game: %v", err)
+	}
+	join, err := client.JoinGame(yellowCtx, &pb.JoinGameRequest{Id: resp.Id})
+	if err != nil {
+		t.Fatalf("Failed to join the game: %v", err)
+	}
+	red := register(redCtx, t, client, resp)
+	yellow := register(yellowCtx, t, client, join)
+	id := resp.Id
+	offer := &pb.Input{Action: &pb.Input_Rematch{Rematch: &pb.Empty{}}}
+
+	if state := act(t, id, pb.Team_red, red, offer); state.GetRejection().GetReason() != pb.RejectionReason_board_in_play {
+		t.Fatalf("expected a rematch during the board to be refused, got %v", state.GetRejection())
+	}
+	state := playMoves(t, id, red, yellow, 1, 2, 1, 2, 1, 2, 1)
+	if state.GetNextFirst() != pb.Team_yellow {
+		t.Fatalf("expected yellow to start the next board, got %v", state.GetNextFirst())
+	}
+	state = act(t, id, pb.Team_yellow, yellow, &pb.Input{Action: &pb.Input_Column{Column: 3}})
+	if state.GetRejection().GetReason() != pb.RejectionReason_board_over {
+		t.Fatalf("expected a move on the finished board to be refused, got %v", state.GetRejection())
+	}
+	state = act(t, id, pb.Team_red, red, offer)
+	recv(t, yellow)
+	if state.GetOffer().GetKind() != pb.OfferKind_rematch || state.GetResult() != pb.Result_red_won {
+		t.Fatalf("expected the finished board to wait for the answer to the rematch, got %v", state)
+	}
+	state = act(t, id, pb.Team_yellow, yellow, &pb.Input{Action: &pb.Input_Accept{Accept: &pb.Empty{}}})
+	recv(t, red)
+	if state.GetResult() != pb.Result_in_progress || state.GetTurn() != pb.Team_yellow || state.NextFirst != nil ||
+		state.GetScore().GetRedWins() != 1 {
+		t.Fatalf("expected a new board opened by yellow, got %v", state)
+	}
+}
+
+func TestFirstPlayer(t *testing.T) {
+	redWins, yellowWins := []int{0, 1, 0, 1, 0, 1, 0}, []int{0, 1, 0, 1, 0, 1, 2, 1}
+	for _, test := range []struct {
+		name   string
+		policy pb.FirstPlayer
+		start  engine.Piece
+		moves  []int
+		next   engine.Piece
+	}{
+		{"alternate", pb.FirstPlayer_alternate, engine.Red, redWins, engine.Yellow},
+		{"alternate back", pb.FirstPlayer_alternate, engine.Yellow, []int{1, 0, 1, 0, 1, 0, 1}, engine.Red},
+		{"loser starts", pb.FirstPlayer_loser_starts, engine.Red, redWins, engine.Yellow},
+		{"loser starts again", pb.FirstPlayer_loser_starts, engine.Red, yellowWins, engine.Red},
+	} {
+		board, _ := engine.NewStarting(engine.Classic, test.start)
+		g := &game{variant: engine.Classic, board: board, boards: [][]MoveRecord{nil},
+			starts: []engine.Piece{test.start}, firstPlayer: test.policy}
+		g.setup()
+		for _, col := range test.moves {
+			if err := g.play(col, time.Now()); err != nil {
+				t.Fatalf("%s: failed to play: %v", test.name, err)
+			}
+		}
+		if g.next != test.next {
+			t.Errorf("%s: expected %v to start the next board, got %v", test.name, test.next, g.next)
+		}
+		g.startBoard(time.Now())
+		if g.board.Turn() != test.next || g.starts[1] != test.next || g.next != engine.Empty {
+			t.Errorf("%s: expected the new board to be opened by %v, got %v", test.name, test.next, g.board.Turn())
+		}
+	}
+
+	rec := &GameRecord{Variant: engine.Classic, Boards: [][]MoveRecord{{{Mover: engine.Red, End: endResign}}, nil},
+		Starts: []engine.Piece{engine.Red, engine.Yellow}}
+	g, err := fromRecord(rec)
+	if err != nil || g.board.Turn() != engine.Yellow {
+		t.Fatalf("expected the restored board to be opened by yellow, got %v", err)
+	}
+}
+
+func TestBotRematch(t *testing.T) {
+	client := startServer(t)
+	ctx := guest(t, client)
+	resp, err := client.NewGame(ctx, &pb.NewGameRequest{Opponent: pb.Opponent_bot.Enum()})
+	if err != nil {
+		t.Fatalf("Failed to start a bot game: %v", err)
+	}
+	red := register(ctx, t, client, resp)
+	act(t, resp.Id, pb.Team_red, red, &pb.Input{Action: &pb.Input_Resign{Resign: &pb.Empty{}}})
+	state := act(t, resp.Id, pb.Team_red, red, &pb.Input{Action: &pb.Input_Rematch{Rematch: &pb.Empty{}}})
+	if state.GetResult() != pb.Result_in_progress || state.GetTurn() != pb.Team_yellow {
+		t.Fatalf("expected the bot to accept the rematch, got %v", state)
+	}
+	if state = recv(t, red); state.GetTurn() != pb.Team_red {
+		t.Fatalf("expected the bot to open the new board, got %v", state)
+	}
+}
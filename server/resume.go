@@ -0,0 +1,138 @@
+package server
+
+import (
+	"crypto/rand"
+	"time"
+
+	"github.com/geofpwhite/connect4-grpc/pb"
+)
+
+func newToken() string {
+	return rand.Text()
+}
+
+func stopGrace(t *time.Timer) {
+	if t != nil {
+		t.Stop()
+	}
+}
+
+// attach makes stream the connection of the seat of team, ending the one of a previous connection
+// that didn't notice yet it was dropped. Runs on the actor of g.
+func (g *game) attach(team pb.Team, user, token string, stream *subscriber[*pb.State]) error {
+	g.mut.Lock()
+	defer g.mut.Unlock()
+	previous := g.seatStream(team)
+	if team == pb.Team_yellow {
+		if !g.yellow || token != g.yellowToken {
+			return errBadToken
+		}
+		if user != g.yellowUser {
+			return errNotSeatOwner
+		}
+		stopGrace(g.yellowGrace)
+		g.yellowStream = stream
+	} else {
+		if !g.red || token != g.redToken {
+			return errBadToken
+		}
+		if user != g.redUser {
+			return errNotSeatOwner
+		}
+		stopGrace(g.redGrace)
+		g.redStream = stream
+	}
+	if previous != nil {
+		go previous.end(errSeatResumed) // its handler stops applying inputs once it ended
+	}
+	g.startClock(time.Now())
+	return nil
+}
+
+// holdSeats gives the players seated without a connection the grace period to attach, their seat
+// being released otherwise. Runs on the actor of g, or before g is registered.
+func (cs *connect4Server) holdSeats(id int32, g *game) {
+	g.mut.Lock()
+	defer g.mut.Unlock()
+	cs.holdSeat(id, g, pb.Team_red)
+	cs.holdSeat(id, g, pb.Team_yellow)
+}
+
+// holdSeat is holdSeats for the seat of team only. Called with g.mut held.
+func (cs *connect4Server) holdSeat(id int32, g *game, team pb.Team) {
+	if g.bot != nil && g.bot.team == team {
+		return
+	}
+	if team == pb.Team_yellow {
+		if !g.yellow || g.yellowStream != nil {
+			return
+		}
+		stopGrace(g.yellowGrace)
+		token := g.yellowToken
+		g.yellowGrace = time.AfterFunc(cs.grace, func() { g.exec(func() { cs.releaseSeat(id, g, team, token) }) })
+	} else {
+		if !g.red || g.redStream != nil {
+			return
+		}
+		stopGrace(g.redGrace)
+		token := g.redToken
+		g.redGrace = time.AfterFunc(cs.grace, func() { g.exec(func() { cs.releaseSeat(id, g, team, token) }) })
+	}
+}
+
+// seatStream is the connection of the seat of team, nil while it has none. Called with g.mut held.
+func (g *game) seatStream(team pb.Team) *subscriber[*pb.State] {
+	if team == pb.Team_yellow {
+		return g.yellowStream
+	}
+	return g.redStream
+}
+
+// detach is called once stream ended. The seat stays taken for the grace period so the player can
+// resume it, unless another stream of theirs took over already. Runs on the actor of g.
+func (cs *connect4Server) detach(id int32, g *game, team pb.Team, stream *subscriber[*pb.State]) {
+	g.mut.Lock()
+	defer g.mut.Unlock()
+	if g.seatStream(team) != stream {
+		return
+	}
+	if team == pb.Team_yellow {
+		g.yellowStream = nil
+	} else {
+		g.redStream = nil
+	}
+	cs.holdSeat(id, g, team)
+}
+
+// releaseSeat frees the seat of a player who didn't come back in time. Runs on the actor of g.
+func (cs *connect4Server) releaseSeat(id int32, g *game, team pb.Team, token string) {
+	g.mut.Lock()
+	if team == pb.Team_yellow {
+		if g.yellowStream != nil || g.yellowToken != token {
+			g.mut.Unlock()
+			return // resumed, left or taken by someone else since
+		}
+		g.yellow = false
+		g.yellowName, g.yellowToken, g.yellowUser, g.yellowMuted = "", "", "", false
+	} else {
+		if g.redStream != nil || g.redToken != token {
+			g.mut.Unlock()
+			return
+		}
+		g.red = false
+		g.redName, g.redToken, g.redUser, g.redMuted = "", "", "", false
+	}
+	g.stopClock(time.Now())
+	abandoned := g.abandoned()
+	if abandoned {
+		close(g.done)
+	}
+	g.mut.Unlock()
+	if abandoned {
+		cs.games.remove(id, g)
+		cs.finish(id, g)
+	} else {
+		cs.save(id, g)
+	}
+	cs.lobbyChanged(id)
+}
@@ -0,0 +1,165 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/geofpwhite/connect4-grpc/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// connect opens a stream on seat and returns it with the first state received.
+func connect(
+	ctx context.Context, client pb.Connect4Client, seat *pb.GameIDAndTeam,
+) (grpc.BidiStreamingClient[pb.Input, pb.State], *pb.State, error) {
+	stream, err := client.CommunicateState(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	register := int32(-1)
+	if err := stream.Send(&pb.Input{GameId: seat.Id, Action: &pb.Input_Column{Column: register}, InputTeam: seat.Team,
+		ResumeToken: seat.ResumeToken}); err != nil {
+		return nil, nil, err
+	}
+	state, err := stream.Recv()
+	return stream, state, err
+}
+
+func TestResume(t *testing.T) {
+	cs := newServer()
+	cs.grace = 100 * time.Millisecond
+	client := serve(t, cs)
+	redCtx, yellowCtx, ctx := guest(t, client), guest(t, client), guest(t, client)
+	red, err := client.NewGame(redCtx, &pb.NewGameRequest{})
+	if err != nil {
+		t.Fatalf("Failed to start a new game: %v", err)
+	}
+	yellow, err := client.JoinGame(yellowCtx, &pb.JoinGameRequest{Id: red.Id})
+	if err != nil {
+		t.Fatalf("Failed to join the game: %v", err)
+	}
+	if red.GetResumeToken() == "" || yellow.GetResumeToken() == "" || red.GetResumeToken() == yellow.GetResumeToken() {
+		t.Fatalf("expected distinct resume tokens, got %q and %q", red.GetResumeToken(), yellow.GetResumeToken())
+	}
+	stolen := &pb.GameIDAndTeam{Id: red.Id, Team: red.Team, ResumeToken: yellow.ResumeToken}
+	if _, _, err := connect(redCtx, client, stolen); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied playing with another token, got %v", err)
+	}
+	if _, err := client.LeaveGame(redCtx, stolen); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied leaving with another token, got %v", err)
+	}
+
+	dropped, cancel := context.WithCancel(redCtx)
+	redStream, _, err := connect(dropped, client, red)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	yellowStream := register(yellowCtx, t, client, yellow)
+	playMoves(t, red.Id, redStream, yellowStream, 4, 4)
+	cancel()
+
+	// the seat is held while red reconnects
+	if _, err := client.JoinGame(ctx, &pb.JoinGameRequest{Id: red.Id}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected the seat to be held, got %v", err)
+	}
+	redStream, state, err := connect(redCtx, client, red)
+	if err != nil {
+		t.Fatalf("Failed to resume: %v", err)
+	}
+	if state.GetField().GetRows()[1].GetValues()[3] != pb.Team_yellow || state.GetTurn() != pb.Team_red {
+		t.Fatalf("expected the board to be resumed, got %v", state)
+	}
+	time.Sleep(2 * cs.grace)
+	state = playMoves(t, red.Id, redStream, yellowStream, 4, 4)
+	if state.GetField().GetRows()[3].GetValues()[3] != pb.Team_yellow {
+		t.Fatalf("expected the game to go on after resuming, got %v", state.GetField())
+	}
+
+	// without coming back the seat is freed for someone else
+	if err := yellowStream.CloseSend(); err != nil {
+		t.Fatalf("Failed to close: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		seat, err := client.JoinGame(ctx, &pb.JoinGameRequest{Id: red.Id})
+		if err == nil {
+			if seat.GetTeam() != pb.Team_yellow {
+				t.Fatalf("expected the yellow seat to be free, got %v", seat.GetTeam())
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the seat to be released, got %v", err)
+		}
+		time.Sleep(cs.grace / 4)
+	}
+	if _, _, err := connect(yellowCtx, client, yellow); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected the old token to be rejected, got %v", err)
+	}
+}
+
+func TestResumeEndsReplacedStream(t *testing.T) {
+	client := startServer(t)
+	redCtx, yellowCtx := guest(t, client), guest(t, client)
+	red, yellow := seat(t, client, redCtx, yellowCtx)
+	stale := register(redCtx, t, client, red)
+	yellowStream := register(yellowCtx, t, client, yellow)
+	resumed, _, err := connect(redCtx, client, red)
+	if err != nil {
+		t.Fatalf("Failed to resume: %v", err)
+	}
+	// the replaced stream may still be open when its move comes in, which must not be played
+	_ = stale.Send(&pb.Input{GameId: red.Id, Action: &pb.Input_Column{Column: 1}, InputTeam: pb.Team_red.Enum()})
+	for {
+		_, err := stale.Recv()
+		if err == nil {
+			continue
+		}
+		if status.Code(err) != codes.Aborted {
+			t.Fatalf("expected the replaced stream to end with Aborted, got %v", err)
+		}
+		break
+	}
+	state := act(t, red.Id, pb.Team_red, resumed, &pb.Input{Action: &pb.Input_Column{Column: 4}})
+	if got := state.GetField().GetRows()[0].GetValues(); got[0] != pb.Team_empty || got[3] != pb.Team_red {
+		t.Fatalf("expected only the move of the resumed stream to be played, got the first row %v", got)
+	}
+	recv(t, yellowStream)
+}
+
+func TestUnattachedSeats(t *testing.T) {
+	cs := newServer()
+	cs.grace = 100 * time.Millisecond
+	client := serve(t, cs)
+	redCtx, yellowCtx, ctx := guest(t, client), guest(t, client), guest(t, client)
+	// the creator of a game who never attaches leaves it
+	if _, err := client.NewGame(ctx, &pb.NewGameRequest{}); err != nil {
+		t.Fatalf("Failed to start a new game: %v", err)
+	}
+	red, err := client.NewGame(redCtx, &pb.NewGameRequest{})
+	if err != nil {
+		t.Fatalf("Failed to start a new game: %v", err)
+	}
+	register(redCtx, t, client, red)
+	if _, err := client.JoinGame(yellowCtx, &pb.JoinGameRequest{Id: red.Id}); err != nil {
+		t.Fatalf("Failed to join the game: %v", err)
+	}
+	// so does a player joining without attaching
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		g, _ := cs.games.get(red.GetId())
+		g.mut.RLock()
+		yellow := g.yellow
+		g.mut.RUnlock()
+		if !yellow && cs.games.len() == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the unattached seats to be released, %d games left", cs.games.len())
+		}
+		time.Sleep(cs.grace / 4)
+	}
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"fortio.org/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// drainPoll is how often a shutting down server checks whether the boards in play are over.
+	drainPoll = 100 * time.Millisecond
+	// stopTimeout bounds the wait for the calls still running once the games are saved.
+	stopTimeout = 5 * time.Second
+)
+
+var (
+	errShuttingDown    = status.Error(codes.Unavailable, "server is shutting down")
+	errRematchShutdown = errors.New("no rematch while the server shuts down")
+)
+
+// shutdown drains the server: new games and rematches are refused, and the players are told they
+// have up to drain to finish the boards in play. The games are then saved for the next start to
+// restore them, and their streams and the ones of the lobby end with errShuttingDown.
+func (cs *connect4Server) shutdown(drain time.Duration) {
+	cs.games.close()
+	close(cs.draining)
+	deadline := time.Now().Add(drain)
+	for _, g := range cs.games.all() {
+		g.exec(func() {
+			g.mut.Lock()
+			g.shutdownAt = deadline
+			g.mut.Unlock()
+			g.update(g.pbState())
+		})
+	}
+	tick := time.NewTicker(drainPoll)
+	defer tick.Stop()
+	for cs.playing() && time.Now().Before(deadline) {
+		<-tick.C
+	}
+	var wg sync.WaitGroup
+	for id, g := range cs.games.all() {
+		wg.Go(func() { g.exec(func() { cs.suspend(id, g) }) })
+	}
+	cs.lobbyMut.Lock()
+	for w := range cs.watchers {
+		wg.Go(func() { w.sub.end(errShuttingDown) })
+	}
+	cs.lobbyMut.Unlock()
+	wg.Wait()
+}
+
+// playing reports whether a board is in play between two seated players.
+func (cs *connect4Server) playing() bool {
+	for _, g := range cs.games.all() {
+		g.mut.RLock()
+		playing := g.red && g.yellow && !g.over()
+		g.mut.RUnlock()
+		if playing {
+			return true
+		}
+	}
+	return false
+}
+
+// suspend stops g and saves it as it is, then ends its streams once they sent what they have
+// queued. Runs on the actor of g, which returns after it.
+func (cs *connect4Server) suspend(id int32, g *game) {
+	g.mut.Lock()
+	stopGrace(g.redGrace)
+	stopGrace(g.yellowGrace)
+	g.stopClock(time.Now())
+	subscribers := g.receivers()
+	g.mut.Unlock()
+	cs.save(id, g)
+	var wg sync.WaitGroup
+	for _, sub := range subscribers {
+		wg.Go(func() { sub.end(errShuttingDown) })
+	}
+	wg.Wait()
+	g.mut.Lock()
+	close(g.done)
+	g.mut.Unlock()
+	cs.games.remove(id, g)
+}
+
+// shutdownTimestamp is when the server stops, nil unless it is shutting down. Called with g.mut held.
+func (g *game) shutdownTimestamp() *timestamppb.Timestamp {
+	if g.shutdownAt.IsZero() {
+		return nil
+	}
+	return timestamppb.New(g.shutdownAt)
+}
+
+// stop waits up to stopTimeout for the calls of s to end, then cuts the ones left.
+func stop(s *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(stopTimeout):
+		log.Warnf("calls still running after %v, closing their connections", stopTimeout)
+		s.Stop()
+	}
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/geofpwhite/connect4-grpc/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestShutdown(t *testing.T) {
+	key := filepath.Join(t.TempDir(), "session.key")
+	cs := keyed(t, key)
+	client := serve(t, cs)
+	redCtx, yellowCtx := guest(t, client), guest(t, client)
+	red, yellow := seat(t, client, redCtx, yellowCtx)
+	redStream, yellowStream := register(redCtx, t, client, red), register(yellowCtx, t, client, yellow)
+	playMoves(t, red.Id, redStream, yellowStream, 4, 3)
+	// an open game for the lobby to list
+	if _, err := client.NewGame(guest(t, client), &pb.NewGameRequest{}); err != nil {
+		t.Fatalf("Failed to start a new game: %v", err)
+	}
+	lobby, err := client.WatchLobby(context.Background(), &pb.ListGamesRequest{})
+	if err != nil {
+		t.Fatalf("Failed to watch the lobby: %v", err)
+	}
+	if _, err := lobby.Recv(); err != nil {
+		t.Fatalf("Failed to receive the lobby: %v", err)
+	}
+
+	start := time.Now()
+	stopped := make(chan struct{})
+	go func() {
+		cs.shutdown(300 * time.Millisecond)
+		close(stopped)
+	}()
+	if at := recv(t, redStream).GetShutdownAt(); at == nil || at.AsTime().Before(start) {
+		t.Fatalf("expected the players to be told when the server stops, got %v", at)
+	}
+	recv(t, yellowStream)
+	if _, err := client.NewGame(redCtx, &pb.NewGameRequest{}); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected new games to be refused while shutting down, got %v", err)
+	}
+	// the board stays in play, so the server waits until the deadline
+	state := act(t, red.Id, pb.Team_red, redStream, &pb.Input{Action: &pb.Input_Column{Column: 4}})
+	if state.GetShutdownAt() == nil {
+		t.Fatal("expected the states sent while shutting down to keep the deadline")
+	}
+	recv(t, yellowStream)
+	<-stopped
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond {
+		t.Fatalf("expected the server to wait for the board in play, stopped after %v", elapsed)
+	}
+	for name, stream := range map[string]interface{ Recv() (*pb.State, error) }{"red": redStream, "yellow": yellowStream} {
+		if _, err := stream.Recv(); status.Code(err) != codes.Unavailable {
+			t.Errorf("expected the %s stream to end with Unavailable, got %v", name, err)
+		}
+	}
+	if _, err := lobby.Recv(); status.Code(err) != codes.Unavailable {
+		t.Errorf("expected the lobby to end with Unavailable, got %v", err)
+	}
+	if cs.games.len() != 0 {
+		t.Fatalf("expected the games to be stopped, %d left", cs.games.len())
+	}
+
+	restarted := keyed(t, key)
+	restarted.store = cs.store
+	if err := restarted.restore(); err != nil {
+		t.Fatalf("Failed to restore the games: %v", err)
+	}
+	client = serve(t, restarted)
+	redStream = register(redCtx, t, client, red)
+	state = act(t, red.Id, pb.Team_yellow, register(yellowCtx, t, client, yellow), &pb.Input{Action: &pb.Input_Column{Column: 3}})
+	if got := state.GetField().GetRows()[1].GetValues(); got[3] != pb.Team_red || got[2] != pb.Team_yellow {
+		t.Fatalf("expected the game to go on after the restart, got the second row %v", got)
+	}
+	recv(t, redStream)
+}
+
+func TestShutdownAfterBoards(t *testing.T) {
+	cs := newServer()
+	client := serve(t, cs)
+	var seats [2][2]*pb.GameIDAndTeam
+	var streams [2][2]grpc.BidiStreamingClient[pb.Input, pb.State]
+	for i := range seats {
+		redCtx, yellowCtx := guest(t, client), guest(t, client)
+		seats[i][0], seats[i][1] = seat(t, client, redCtx, yellowCtx)
+		streams[i][0], streams[i][1] = register(redCtx, t, client, seats[i][0]), register(yellowCtx, t, client, seats[i][1])
+	}
+	stopped := make(chan struct{})
+	go func() {
+		cs.shutdown(time.Minute)
+		close(stopped)
+	}()
+	for _, game := range streams {
+		recv(t, game[0])
+		recv(t, game[1])
+	}
+	// the second game keeps the server waiting while the first one asks for a rematch
+	first, second := streams[0], streams[1]
+	act(t, seats[0][0].Id, pb.Team_red, first[0], &pb.Input{Action: &pb.Input_Resign{Resign: &pb.Empty{}}})
+	recv(t, first[1])
+	state := act(t, seats[0][1].Id, pb.Team_yellow, first[1], &pb.Input{Action: &pb.Input_Rematch{Rematch: &pb.Empty{}}})
+	if state.GetRejection().GetReason() != pb.RejectionReason_shutting_down {
+		t.Fatalf("expected rematches to be refused while shutting down, got %v", state.GetRejection())
+	}
+	act(t, seats[1][0].Id, pb.Team_red, second[0], &pb.Input{Action: &pb.Input_Resign{Resign: &pb.Empty{}}})
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the server to stop once no board is in play")
+	}
+}
+
+// seat starts a game for the red player of redCtx and has the one of yellowCtx join it.
+func seat(t *testing.T, client pb.Connect4Client, redCtx, yellowCtx context.Context) (*pb.GameIDAndTeam, *pb.GameIDAndTeam) {
+	t.Helper()
+	red, err := client.NewGame(redCtx, &pb.NewGameRequest{})
+	if err != nil {
+		t.Fatalf("Failed to start a new game: %v", err)
+	}
+	yellow, err := client.JoinGame(yellowCtx, &pb.JoinGameRequest{Id: red.Id})
+	if err != nil {
+		t.Fatalf("Failed to join the game: %v", err)
+	}
+	return red, yellow
+}
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"github.com/geofpwhite/connect4-grpc/pb"
+	"google.golang.org/grpc"
+)
+
+// Spectate streams the state of a game to an observer until it disconnects or the game is deleted.
+func (cs *connect4Server) Spectate(id *pb.GameID, stream grpc.ServerStreamingServer[pb.State]) error {
+	game, exists := cs.games.get(id.GetId())
+	if !exists {
+		return errGameNotFound
+	}
+	sub := subscribe(&cs.stats, "spectator", stateQueue, stream.Send, supersededState)
+	defer sub.close()
+	defer game.exec(func() {
+		game.mut.Lock()
+		delete(game.spectators, sub)
+		game.mut.Unlock()
+		cs.lobbyChanged(id.GetId())
+	})
+	err := game.do(func() error {
+		game.mut.Lock()
+		game.spectators[sub] = struct{}{}
+		game.mut.Unlock()
+		cs.lobbyChanged(id.GetId())
+		sub.push(game.pbState())
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	select {
+	case <-stream.Context().Done():
+	case <-game.done:
+	case <-sub.done():
+		return sub.Err()
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/geofpwhite/connect4-grpc/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSpectate(t *testing.T) {
+	client := startServer(t)
+	alice, bob := "alice", "bob"
+	aliceCtx, bobCtx := guest(t, client), guest(t, client)
+	resp, err := client.NewGame(aliceCtx, &pb.NewGameRequest{PlayerName: &alice})
+	if err != nil {
+		t.Fatalf("Failed to start a new game: %v", err)
+	}
+	join, err := client.JoinGame(bobCtx, &pb.JoinGameRequest{Id: resp.Id, PlayerName: &bob})
+	if err != nil {
+		t.Fatalf("Failed to join the game: %v", err)
+	}
+	red := register(aliceCtx, t, client, resp)
+	yellow := register(bobCtx, t, client, join)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	watchers := make([]interface{ Recv() (*pb.State, error) }, 2)
+	for i := range watchers {
+		watch, err := client.Spectate(ctx, &pb.GameID{Id: resp.Id})
+		if err != nil {
+			t.Fatalf("Failed to spectate: %v", err)
+		}
+		state, err := watch.Recv()
+		if err != nil {
+			t.Fatalf("Failed to receive the initial state: %v", err)
+		}
+		if p := state.GetPlayers(); p.GetRed() != alice || p.GetYellow() != bob {
+			t.Fatalf("expected alice against bob, got %v", p)
+		}
+		watchers[i] = watch
+	}
+
+	playMoves(t, resp.Id, red, yellow, 3)
+	for _, watch := range watchers {
+		state, err := watch.Recv()
+		if err != nil {
+			t.Fatalf("Failed to receive the move: %v", err)
+		}
+		if state.GetField().GetRows()[0].GetValues()[2] != pb.Team_red {
+			t.Fatalf("expected spectators to see red in column 3, got %v", state.GetField())
+		}
+		if state.GetPlayers().GetSpectators() != 2 {
+			t.Fatalf("expected 2 spectators, got %d", state.GetPlayers().GetSpectators())
+		}
+		if state.GetScore() == nil {
+			t.Fatal("expected spectators to get the score")
+		}
+	}
+
+	// the spectator streams end once both players left
+	for ctx, seat := range map[context.Context]*pb.GameIDAndTeam{aliceCtx: resp, bobCtx: join} {
+		if _, err := client.LeaveGame(ctx, seat); err != nil {
+			t.Fatalf("Failed to leave: %v", err)
+		}
+	}
+	for _, watch := range watchers {
+		if _, err := watch.Recv(); err != io.EOF { //nolint:errorlint // grpc returns io.EOF as is
+			t.Fatalf("expected the stream to end, got %v", err)
+		}
+	}
+
+	watch, err := client.Spectate(ctx, &pb.GameID{Id: resp.Id})
+	if err == nil {
+		_, err = watch.Recv()
+	}
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound watching a deleted game, got %v", err)
+	}
+}
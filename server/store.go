@@ -0,0 +1,214 @@
+package server
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/geofpwhite/connect4-grpc/engine"
+	"github.com/geofpwhite/connect4-grpc/pb"
+	bolt "go.etcd.io/bbolt"
+)
+
+var ErrNotStored = errors.New("game not in the store")
+
+// GameStore keeps games across restarts, including finished ones for history and stats.
+// Implementations must be safe for concurrent use.
+type GameStore interface {
+	// Save creates or replaces the record of rec.ID.
+	Save(rec *GameRecord) error
+	Load(id int32) (*GameRecord, error)
+	// Active returns the games that weren't finished.
+	Active() ([]*GameRecord, error)
+	Close() error
+}
+
+// GameRecord is everything needed to rebuild a game.
+type GameRecord struct {
+	ID         int32          `json:"id"`
+	Variant    engine.Variant `json:"variant"`
+	CreatedAt  time.Time      `json:"created_at"`
+	FinishedAt time.Time      `json:"finished_at,omitzero"` // zero while the game is on
+	Host       string         `json:"host"`
+	Private    bool           `json:"private,omitempty"`
+	FirstTo    int            `json:"first_to,omitempty"`
+	BotLevel   int            `json:"bot_level,omitempty"` // the bot plays yellow when set
+	Red        SeatRecord     `json:"red"`
+	Yellow     SeatRecord     `json:"yellow"`
+	// Boards are the moves of each board of the series, the last one being the board in play.
+	Boards [][]MoveRecord `json:"boards"`
+	// Starts is who moved first on each board, red for the boards missing.
+	Starts      []engine.Piece `json:"starts,omitempty"`
+	Next        engine.Piece   `json:"next,omitempty"` // who moves first on the next board once the last one is over
+	FirstPlayer pb.FirstPlayer `json:"first_player,omitempty"`
+	Rated       bool           `json:"rated,omitempty"`
+	RatedBoards int            `json:"rated_boards,omitempty"` // boards whose result was counted in the ratings
+	RedWins     int            `json:"red_wins"`
+	YellowWins  int            `json:"yellow_wins"`
+	Draws       int            `json:"draws"`
+	Clock       *ClockRecord   `json:"clock,omitempty"`
+}
+
+// start returns who moved first on board i.
+func (rec *GameRecord) start(i int) engine.Piece {
+	if i < len(rec.Starts) {
+		return rec.Starts[i]
+	}
+	return engine.Red
+}
+
+// ClockRecord is the time left to each player, the clock being stopped while the game is stored.
+type ClockRecord struct {
+	Control TimeControl   `json:"control"`
+	Red     time.Duration `json:"red"`
+	Yellow  time.Duration `json:"yellow"`
+}
+
+type MoveRecord struct {
+	Column int          `json:"column"` // 0 based
+	Mover  engine.Piece `json:"mover"`
+	At     time.Time    `json:"at"`
+	// End is set on the record ending a board early instead of a move, Mover being the player
+	// who lost or accepted the draw: endTimeout, endResign or endDraw.
+	End string `json:"end,omitempty"`
+}
+
+type SeatRecord struct {
+	Taken bool   `json:"taken,omitempty"`
+	Name  string `json:"name,omitempty"`
+	User  string `json:"user,omitempty"`
+	Token string `json:"token,omitempty"`
+}
+
+// memoryStore is a GameStore that forgets everything on restart.
+type memoryStore struct {
+	mut   sync.Mutex
+	games map[int32][]byte // records are copied in and out as JSON
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{games: make(map[int32][]byte)}
+}
+
+func (s *memoryStore) Save(rec *GameRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	s.games[rec.ID] = data
+	return nil
+}
+
+func (s *memoryStore) Load(id int32) (*GameRecord, error) {
+	s.mut.Lock()
+	data, exists := s.games[id]
+	s.mut.Unlock()
+	if !exists {
+		return nil, ErrNotStored
+	}
+	return decodeRecord(data)
+}
+
+func (s *memoryStore) Active() ([]*GameRecord, error) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	var active []*GameRecord
+	for _, id := range slices.Sorted(maps.Keys(s.games)) {
+		rec, err := decodeRecord(s.games[id])
+		if err != nil {
+			return nil, err
+		}
+		if rec.FinishedAt.IsZero() {
+			active = append(active, rec)
+		}
+	}
+	return active, nil
+}
+
+func (s *memoryStore) Close() error { return nil }
+
+var gamesBucket = []byte("games")
+
+// boltStore is a GameStore in a BoltDB file.
+type boltStore struct {
+	db *bolt.DB
+}
+
+// OpenBoltStore opens or creates the store in the file at path.
+func OpenBoltStore(path string) (GameStore, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(gamesBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &boltStore{db: db}, nil
+}
+
+func boltKey(id int32) []byte {
+	return binary.BigEndian.AppendUint32(nil, uint32(id)) //nolint:gosec // just a key
+}
+
+func (s *boltStore) Save(rec *GameRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(gamesBucket).Put(boltKey(rec.ID), data)
+	})
+}
+
+func (s *boltStore) Load(id int32) (*GameRecord, error) {
+	var data []byte
+	_ = s.db.View(func(tx *bolt.Tx) error {
+		// the value is only valid during the transaction
+		data = slices.Clone(tx.Bucket(gamesBucket).Get(boltKey(id)))
+		return nil
+	})
+	if data == nil {
+		return nil, ErrNotStored
+	}
+	return decodeRecord(data)
+}
+
+func (s *boltStore) Active() ([]*GameRecord, error) {
+	var active []*GameRecord
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(gamesBucket).ForEach(func(_, data []byte) error {
+			rec, err := decodeRecord(data)
+			if err != nil {
+				return err
+			}
+			if rec.FinishedAt.IsZero() {
+				active = append(active, rec)
+			}
+			return nil
+		})
+	})
+	return active, err
+}
+
+func (s *boltStore) Close() error {
+	return s.db.Close()
+}
+
+func decodeRecord(data []byte) (*GameRecord, error) {
+	rec := &GameRecord{}
+	if err := json.Unmarshal(data, rec); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
@@ -0,0 +1,176 @@
+package server
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/geofpwhite/connect4-grpc/engine"
+	"github.com/geofpwhite/connect4-grpc/pb"
+)
+
+func TestGameStores(t *testing.T) {
+	bolt, err := OpenBoltStore(filepath.Join(t.TempDir(), "games.db"))
+	if err != nil {
+		t.Fatalf("Failed to open the bolt store: %v", err)
+	}
+	for name, store := range map[string]GameStore{"memory": newMemoryStore(), "bolt": bolt} {
+		t.Run(name, func(t *testing.T) {
+			defer store.Close()
+			if _, err := store.Load(1); !errors.Is(err, ErrNotStored) {
+				t.Fatalf("expected ErrNotStored, got %v", err)
+			}
+			on := &GameRecord{ID: 1, Variant: engine.Classic, Host: "alice", RedWins: 1,
+				Boards: [][]MoveRecord{{{Column: 3, Mover: engine.Red}, {Column: 3, Mover: engine.Yellow}}, {{Column: 2, Mover: engine.Red}}},
+				Red:    SeatRecord{Taken: true, Name: "alice", User: "alice", Token: "t"}}
+			over := &GameRecord{ID: -2, Variant: engine.Classic, FinishedAt: time.Now()}
+			for _, rec := range []*GameRecord{on, over} {
+				if err := store.Save(rec); err != nil {
+					t.Fatalf("Failed to save: %v", err)
+				}
+			}
+			rec, err := store.Load(1)
+			if err != nil {
+				t.Fatalf("Failed to load: %v", err)
+			}
+			if rec.Red != on.Red || rec.RedWins != 1 || len(rec.Boards) != 2 || !slices.Equal(rec.Boards[0], on.Boards[0]) {
+				t.Fatalf("expected %+v, got %+v", on, rec)
+			}
+			if _, err := store.Load(-2); err != nil {
+				t.Fatalf("expected finished games to be kept, got %v", err)
+			}
+			active, err := store.Active()
+			if err != nil {
+				t.Fatalf("Failed to list active games: %v", err)
+			}
+			if len(active) != 1 || active[0].ID != 1 {
+				t.Fatalf("expected only game 1 to be active, got %v", active)
+			}
+		})
+	}
+}
+
+func TestRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "games.db")
+	store, err := OpenBoltStore(path)
+	if err != nil {
+		t.Fatalf("Failed to open the store: %v", err)
+	}
+	key := filepath.Join(t.TempDir(), "session.key")
+	cs := keyed(t, key)
+	cs.store = store
+	client := serve(t, cs)
+	redCtx, yellowCtx := guest(t, client), guest(t, client)
+	red, err := client.NewGame(redCtx, &pb.NewGameRequest{})
+	if err != nil {
+		t.Fatalf("Failed to start a new game: %v", err)
+	}
+	yellow, err := client.JoinGame(yellowCtx, &pb.JoinGameRequest{Id: red.Id})
+	if err != nil {
+		t.Fatalf("Failed to join the game: %v", err)
+	}
+	playMoves(t, red.Id, register(redCtx, t, client, red), register(yellowCtx, t, client, yellow), 4, 4)
+	if err := store.Close(); err != nil {
+		t.Fatalf("Failed to close the store: %v", err)
+	}
+
+	if store, err = OpenBoltStore(path); err != nil {
+		t.Fatalf("Failed to reopen the store: %v", err)
+	}
+	defer store.Close()
+	restarted := keyed(t, key)
+	restarted.store = store
+	if err := restarted.restore(); err != nil {
+		t.Fatalf("Failed to restore: %v", err)
+	}
+	client = serve(t, restarted)
+	redStream, state, err := connect(redCtx, client, red)
+	if err != nil {
+		t.Fatalf("Failed to resume after the restart: %v", err)
+	}
+	if state.GetField().GetRows()[1].GetValues()[3] != pb.Team_yellow || state.GetTurn() != pb.Team_red {
+		t.Fatalf("expected the board to survive the restart, got %v", state)
+	}
+	yellowStream, _, err := connect(yellowCtx, client, yellow)
+	if err != nil {
+		t.Fatalf("Failed to resume after the restart: %v", err)
+	}
+	state = playMoves(t, red.Id, redStream, yellowStream, 4, 4)
+	if state.GetField().GetRows()[3].GetValues()[3] != pb.Team_yellow {
+		t.Fatalf("expected the game to go on after the restart, got %v", state.GetField())
+	}
+
+	if _, err := client.LeaveGame(redCtx, red); err != nil {
+		t.Fatalf("Failed to leave: %v", err)
+	}
+	if _, err := client.LeaveGame(yellowCtx, yellow); err != nil {
+		t.Fatalf("Failed to leave: %v", err)
+	}
+	rec, err := store.Load(red.GetId())
+	if err != nil || rec.FinishedAt.IsZero() || len(rec.Boards[0]) != 4 {
+		t.Fatalf("expected the abandoned game to be kept as finished, got %+v, %v", rec, err)
+	}
+}
+
+func TestRestoreBotToMove(t *testing.T) {
+	cs := newServer()
+	rec := &GameRecord{ID: 1, Variant: engine.Classic, BotLevel: 1,
+		Boards: [][]MoveRecord{{{Column: 3, Mover: engine.Red}}},
+		Red:    SeatRecord{Taken: true, Name: "ann", User: "ann", Token: "t"},
+		Yellow: SeatRecord{Taken: true, Name: "bot level 1"}}
+	if err := cs.store.Save(rec); err != nil {
+		t.Fatalf("Failed to save: %v", err)
+	}
+	if err := cs.restore(); err != nil {
+		t.Fatalf("Failed to restore: %v", err)
+	}
+	g, _ := cs.games.get(1)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		g.mut.RLock()
+		moves := len(g.board.Moves())
+		g.mut.RUnlock()
+		if moves == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the restored bot to move, the board has %d moves", moves)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRestoreClockWaits(t *testing.T) {
+	cs := newServer()
+	rec := &GameRecord{ID: 1, Variant: engine.Classic, Boards: [][]MoveRecord{nil},
+		Red:    SeatRecord{Taken: true, Name: "ann", User: "ann", Token: "r"},
+		Yellow: SeatRecord{Taken: true, Name: "bob", User: "bob", Token: "y"},
+		Clock:  &ClockRecord{Control: TimeControl{Initial: time.Minute}, Red: time.Minute, Yellow: time.Minute}}
+	if err := cs.store.Save(rec); err != nil {
+		t.Fatalf("Failed to save: %v", err)
+	}
+	if err := cs.restore(); err != nil {
+		t.Fatalf("Failed to restore: %v", err)
+	}
+	g, _ := cs.games.get(1)
+	g.mut.RLock()
+	running := !g.clock.since.IsZero()
+	g.mut.RUnlock()
+	if running {
+		t.Fatal("expected the clock to wait for a player to resume")
+	}
+}
+
+// keyed returns a server signing the sessions with the key of path, created if missing, as Serve
+// does.
+func keyed(t *testing.T, path string) *connect4Server {
+	t.Helper()
+	cs := newServer()
+	var err error
+	if cs.sessions, err = loadSessions(path); err != nil {
+		t.Fatalf("Failed to load the session key: %v", err)
+	}
+	return cs
+}
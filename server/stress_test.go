@@ -0,0 +1,191 @@
+package server
+
+// These tests hammer the server from many goroutines and are meant to be run with -race.
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/geofpwhite/connect4-grpc/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	stressGames   = 20
+	stressPlayers = 8
+)
+
+// guests returns the contexts of n new guests.
+func guests(t *testing.T, client pb.Connect4Client, n int) []context.Context {
+	t.Helper()
+	ctxs := make([]context.Context, n)
+	for i := range ctxs {
+		ctxs[i] = guest(t, client)
+	}
+	return ctxs
+}
+
+// drain reads stream until it ends.
+func drain(stream interface{ Recv() (*pb.State, error) }) {
+	for {
+		if _, err := stream.Recv(); err != nil {
+			return
+		}
+	}
+}
+
+func TestConcurrentJoins(t *testing.T) {
+	client := startServer(t)
+	var wg sync.WaitGroup
+	for range stressGames {
+		hostCtx, players := guest(t, client), guests(t, client, stressPlayers)
+		wg.Go(func() {
+			resp, err := client.NewGame(hostCtx, &pb.NewGameRequest{})
+			if err != nil {
+				t.Errorf("Failed to start a new game: %v", err)
+				return
+			}
+			var seated atomic.Int32
+			var joins sync.WaitGroup
+			for _, ctx := range players {
+				joins.Go(func() {
+					_, err := client.JoinGame(ctx, &pb.JoinGameRequest{Id: resp.Id})
+					switch {
+					case err == nil:
+						seated.Add(1)
+					case status.Code(err) != codes.FailedPrecondition:
+						t.Errorf("expected FailedPrecondition for a full game, got %v", err)
+					}
+				})
+			}
+			joins.Wait()
+			if n := seated.Load(); n != 1 {
+				t.Errorf("expected one player to get the free seat of game %d, got %d", resp.GetId(), n)
+			}
+		})
+	}
+	wg.Wait()
+}
+
+func TestConcurrentMoves(t *testing.T) {
+	client := startServer(t)
+	var wg sync.WaitGroup
+	for range stressGames {
+		redCtx, yellowCtx := guest(t, client), guest(t, client)
+		resp, err := client.NewGame(redCtx, &pb.NewGameRequest{})
+		if err != nil {
+			t.Fatalf("Failed to start a new game: %v", err)
+		}
+		join, err := client.JoinGame(yellowCtx, &pb.JoinGameRequest{Id: resp.Id})
+		if err != nil {
+			t.Fatalf("Failed to join the game: %v", err)
+		}
+		seats := map[*pb.GameIDAndTeam]context.Context{resp: redCtx, join: yellowCtx}
+		var players sync.WaitGroup
+		for seat, ctx := range seats {
+			stream := register(ctx, t, client, seat)
+			players.Go(func() {
+				// both players send moves without waiting for their turn
+				for range 40 {
+					col := int32(1 + rand.IntN(7)) //nolint:gosec // no need for crypto randomness
+					if err := stream.Send(&pb.Input{GameId: seat.Id, InputTeam: seat.Team, Action: &pb.Input_Column{Column: col}}); err != nil {
+						t.Errorf("Failed to send a move: %v", err)
+						return
+					}
+				}
+				_ = stream.CloseSend()
+				drain(stream)
+			})
+		}
+		wg.Go(func() {
+			players.Wait()
+			history, err := client.GetGameHistory(redCtx, &pb.GameID{Id: resp.Id})
+			if err != nil {
+				t.Errorf("Failed to get the history: %v", err)
+				return
+			}
+			for i, m := range history.GetBoards()[0].GetMoves() {
+				if want := []pb.Team{pb.Team_red, pb.Team_yellow}[i%2]; m.GetTeam() != want {
+					t.Errorf("game %d: expected move %d to be played by %v, got %v", resp.GetId(), i, want, m.GetTeam())
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+}
+
+func TestConcurrentLeaves(t *testing.T) {
+	cs := newServer()
+	client := serve(t, cs)
+	var wg sync.WaitGroup
+	for range stressGames {
+		redCtx, yellowCtx := guest(t, client), guest(t, client)
+		wg.Go(func() {
+			resp, err := client.NewGame(redCtx, &pb.NewGameRequest{})
+			if err != nil {
+				t.Errorf("Failed to start a new game: %v", err)
+				return
+			}
+			join, err := client.JoinGame(yellowCtx, &pb.JoinGameRequest{Id: resp.Id})
+			if err != nil {
+				t.Errorf("Failed to join the game: %v", err)
+				return
+			}
+			var seats sync.WaitGroup
+			for seat, ctx := range map[*pb.GameIDAndTeam]context.Context{resp: redCtx, join: yellowCtx} {
+				seats.Go(func() { play(t, client, ctx, seat) })
+			}
+			spectate, err := client.Spectate(context.Background(), &pb.GameID{Id: resp.Id})
+			if err == nil {
+				go drain(spectate)
+			}
+			_, _ = client.ListGames(context.Background(), &pb.ListGamesRequest{})
+			seats.Wait()
+		})
+	}
+	wg.Wait()
+	// the games are deleted once both players left
+	deadline := time.Now().Add(5 * time.Second)
+	for cs.games.len() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected every game to be deleted, %d left", cs.games.len())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// play makes a few moves, chats and leaves the seat while the opponent does the same.
+func play(t *testing.T, client pb.Connect4Client, ctx context.Context, seat *pb.GameIDAndTeam) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := client.CommunicateState(ctx)
+	if err != nil {
+		t.Errorf("Failed to communicate state: %v", err)
+		return
+	}
+	send := func(in *pb.Input) error {
+		in.GameId, in.InputTeam, in.ResumeToken = seat.Id, seat.Team, seat.ResumeToken
+		return stream.Send(in)
+	}
+	if err := send(&pb.Input{Action: &pb.Input_Column{Column: -1}}); err != nil {
+		t.Errorf("Failed to register: %v", err)
+		return
+	}
+	go drain(stream)
+	text := "hi"
+	for range 5 {
+		col := int32(1 + rand.IntN(7)) //nolint:gosec // no need for crypto randomness
+		_ = send(&pb.Input{Action: &pb.Input_Column{Column: col}})
+		_ = send(&pb.Input{Action: &pb.Input_Chat{Chat: &pb.ChatMessage{Text: &text}}})
+	}
+	_, err = client.LeaveGame(ctx, seat)
+	if err != nil && status.Code(err) != codes.NotFound {
+		t.Errorf("Failed to leave the game: %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/geofpwhite/connect4-grpc/rating"
+)
+
+const (
+	minPasswordLength = 8
+	hashIterations    = 100_000
+)
+
+var (
+	errUserExists   = errors.New("username is taken")
+	errBadLogin     = errors.New("wrong username or password")
+	errBadUsername  = fmt.Errorf("username must be 3 to %d letters, digits, '-' or '_'", maxNameLength)
+	errWeakPassword = fmt.Errorf("password must be at least %d characters", minPasswordLength)
+	validUsername   = regexp.MustCompile(fmt.Sprintf(`^[A-Za-z0-9_-]{3,%d}$`, maxNameLength))
+)
+
+// user is an account, or the rating of a bot which has no password.
+type user struct {
+	Salt    []byte        `json:"salt"`
+	Hash    []byte        `json:"hash"`
+	Created time.Time     `json:"created"`
+	Rating  rating.Rating `json:"rating,omitzero"` // zero before the first rated game
+	Wins    int           `json:"wins,omitempty"`
+	Losses  int           `json:"losses,omitempty"`
+	Draws   int           `json:"draws,omitempty"`
+}
+
+// userStore keeps the accounts in a JSON file, rewritten on every change.
+type userStore struct {
+	mut   sync.Mutex
+	path  string // empty keeps the accounts in memory only
+	users map[string]user
+}
+
+// loadUsers reads the accounts saved at path, a missing file standing for no account yet.
+func loadUsers(path string) (*userStore, error) {
+	s := &userStore{path: path, users: make(map[string]user)}
+	if path == "" {
+		return s, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &s.users); err != nil {
+		return nil, fmt.Errorf("reading users from %s: %w", path, err)
+	}
+	return s, nil
+}
+
+func (s *userStore) add(name, password string) error {
+	if !validUsername.MatchString(name) {
+		return errBadUsername
+	}
+	if len(password) < minPasswordLength {
+		return errWeakPassword
+	}
+	salt := make([]byte, 16)
+	_, _ = rand.Read(salt) // never fails
+	hash, err := pbkdf2.Key(sha256.New, password, salt, hashIterations, sha256.Size)
+	if err != nil {
+		return err
+	}
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	if _, exists := s.users[name]; exists {
+		return errUserExists
+	}
+	s.users[name] = user{Salt: salt, Hash: hash, Created: time.Now()}
+	if err := s.save(); err != nil {
+		delete(s.users, name)
+		return err
+	}
+	return nil
+}
+
+func (s *userStore) check(name, password string) error {
+	s.mut.Lock()
+	u, exists := s.users[name]
+	s.mut.Unlock()
+	if !exists {
+		return errBadLogin
+	}
+	hash, err := pbkdf2.Key(sha256.New, password, u.Salt, hashIterations, sha256.Size)
+	if err != nil || !hmac.Equal(hash, u.Hash) {
+		return errBadLogin
+	}
+	return nil
+}
+
+// save writes the accounts to a temporary file renamed over the store so a crash never leaves it
+// half written. Called with mut held.
+func (s *userStore) save() error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(s.users, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
@@ -0,0 +1,174 @@
+package solver
+
+import (
+	"math/bits"
+
+	"github.com/geofpwhite/connect4-grpc/engine"
+)
+
+// words is enough for the largest board: engine.MaxSize columns of engine.MaxSize+1 bits.
+const words = ((engine.MaxSize+1)*engine.MaxSize + 63) / 64
+
+// bitboard is a set of cells, bit col*(rows+1)+row standing for a cell. The extra bit on top of each
+// column stays empty so that lines can't wrap from one column to the next when shifting.
+type bitboard [words]uint64
+
+func (b bitboard) and(o bitboard) bitboard {
+	for i := range b {
+		b[i] &= o[i]
+	}
+	return b
+}
+
+func (b bitboard) or(o bitboard) bitboard {
+	for i := range b {
+		b[i] |= o[i]
+	}
+	return b
+}
+
+func (b bitboard) xor(o bitboard) bitboard {
+	for i := range b {
+		b[i] ^= o[i]
+	}
+	return b
+}
+
+func (b bitboard) add(o bitboard) bitboard {
+	var carry uint64
+	for i := range b {
+		b[i], carry = bits.Add64(b[i], o[i], carry)
+	}
+	return b
+}
+
+func (b bitboard) shiftRight(n int) bitboard {
+	var r bitboard
+	w, s := n/64, uint(n%64) //nolint:gosec // n is positive
+	for i := range words - w {
+		r[i] = b[i+w] >> s
+		if s > 0 && i+w+1 < words {
+			r[i] |= b[i+w+1] << (64 - s)
+		}
+	}
+	return r
+}
+
+func (b bitboard) isZero() bool {
+	for _, w := range b {
+		if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (b bitboard) hash() uint64 {
+	h := uint64(14695981039346656037)
+	for _, w := range b {
+		h = (h ^ w) * 1099511628211
+	}
+	return h
+}
+
+func cell(row, col, height int) bitboard {
+	var b bitboard
+	i := col*(height+1) + row
+	b[i/64] = 1 << (i % 64)
+	return b
+}
+
+// position is the bitboard version of an engine.Board used during the search.
+type position struct {
+	rows, cols, connect int
+	current             bitboard // discs of the side to move
+	mask                bitboard // all discs
+	moves               int
+	bottom, top, column []bitboard
+}
+
+func newPosition(b *engine.Board) *position {
+	p := &position{
+		rows:    b.Rows(),
+		cols:    b.Cols(),
+		connect: b.ConnectN(),
+		bottom:  make([]bitboard, b.Cols()),
+		top:     make([]bitboard, b.Cols()),
+		column:  make([]bitboard, b.Cols()),
+	}
+	for c := range p.cols {
+		p.bottom[c] = cell(0, c, p.rows)
+		p.top[c] = cell(p.rows-1, c, p.rows)
+		for r := range p.rows {
+			bit := cell(r, c, p.rows)
+			p.column[c] = p.column[c].or(bit)
+			disc := b.At(r, c)
+			if disc == engine.Empty {
+				continue
+			}
+			if disc == b.Turn() {
+				p.current = p.current.or(bit)
+			}
+			p.mask = p.mask.or(bit)
+			p.moves++
+		}
+	}
+	return p
+}
+
+func (p *position) empty() int {
+	return p.rows*p.cols - p.moves
+}
+
+func (p *position) canPlay(col int) bool {
+	return p.mask.and(p.top[col]).isZero()
+}
+
+// landing returns the cell a disc dropped in col would occupy.
+func (p *position) landing(col int) bitboard {
+	return p.mask.add(p.bottom[col]).and(p.column[col])
+}
+
+func (p *position) play(col int) {
+	p.current = p.current.xor(p.mask)
+	p.mask = p.mask.or(p.landing(col))
+	p.moves++
+}
+
+// undo takes back the disc last played in col.
+func (p *position) undo(col int) {
+	last := p.top[col]
+	if landing := p.landing(col); !landing.isZero() {
+		last = landing.shiftRight(1)
+	}
+	p.mask = p.mask.xor(last)
+	p.current = p.current.xor(p.mask)
+	p.moves--
+}
+
+func (p *position) isWinningMove(col int) bool {
+	return p.aligned(p.current.or(p.landing(col)))
+}
+
+// opponentWins reports whether the opponent would win by playing in col.
+func (p *position) opponentWins(col int) bool {
+	return p.aligned(p.current.xor(p.mask).or(p.landing(col)))
+}
+
+func (p *position) aligned(discs bitboard) bool {
+	h := p.rows + 1
+	for _, d := range [...]int{1, h, h - 1, h + 1} {
+		m := discs
+		for i := 1; i < p.connect && !m.isZero(); i++ {
+			m = m.and(discs.shiftRight(i * d))
+		}
+		if !m.isZero() {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *position) key() bitboard {
+	return p.current.add(p.mask)
+}
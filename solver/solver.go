@@ -0,0 +1,257 @@
+// Package solver computes the game theoretic value of every move of a connect 4 position, using a
+// bitboard negamax with alpha-beta pruning, move ordering and a transposition table.
+package solver
+
+import (
+	"slices"
+	"time"
+
+	"github.com/geofpwhite/connect4-grpc/engine"
+)
+
+type Outcome int8
+
+const (
+	Unknown Outcome = iota // the budget ran out before the value was proven
+	Win
+	Loss
+	Draw
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case Win:
+		return "win"
+	case Loss:
+		return "loss"
+	case Draw:
+		return "draw"
+	case Unknown:
+	}
+	return "unknown"
+}
+
+// Options bound the search, zero values meaning no limit.
+type Options struct {
+	MaxDepth  int // plies searched from the position
+	TimeLimit time.Duration
+	MaxNodes  int64
+}
+
+// ColumnValue is the value of playing in Column for the side to move.
+type ColumnValue struct {
+	Column  int // 0 based
+	Legal   bool
+	Outcome Outcome
+	// Plies until the game ends with perfect play from both sides, counting the move in Column.
+	// Only set for wins and losses.
+	Plies int
+	// Score is positive for wins and negative for losses, quicker results having larger magnitudes.
+	Score int
+}
+
+type Analysis struct {
+	Columns  []ColumnValue
+	Depth    int  // depth of the last completed iteration
+	Complete bool // every legal column has a proven value
+	Nodes    int64
+}
+
+const tableSize = 1 << 18
+
+type entry struct {
+	key     bitboard
+	upper   int16 // upper bound of the score
+	depth   int16
+	limited bool // the search was cut by the depth limit below this node
+	used    bool
+}
+
+type search struct {
+	pos      *position
+	order    []int
+	table    []entry
+	deadline time.Time
+	maxNodes int64
+	nodes    int64
+	aborted  bool
+	horizon  bool // the depth limit was hit since the flag was last cleared
+}
+
+// Analyze solves each column of b with iterative deepening, stopping at the first depth where every
+// legal column is proven or when the budget of opts runs out. Values of the last completed depth are
+// returned, columns that could not be proven in time being Unknown.
+func Analyze(b *engine.Board, opts Options) Analysis {
+	pos := newPosition(b)
+	s := &search{
+		pos:      pos,
+		order:    centerFirst(pos.cols),
+		table:    make([]entry, tableSize),
+		maxNodes: opts.MaxNodes,
+	}
+	if opts.TimeLimit > 0 {
+		s.deadline = time.Now().Add(opts.TimeLimit)
+	}
+	maxDepth := pos.empty()
+	if opts.MaxDepth > 0 {
+		maxDepth = min(maxDepth, opts.MaxDepth)
+	}
+	result := Analysis{Columns: make([]ColumnValue, pos.cols)}
+	for c := range result.Columns {
+		result.Columns[c] = ColumnValue{Column: c, Legal: b.CanDrop(c)}
+	}
+	if b.IsOver() {
+		for c := range result.Columns {
+			result.Columns[c].Legal = false
+		}
+		result.Complete = true
+		return result
+	}
+	for depth := 1; depth <= maxDepth; depth++ {
+		columns, complete := s.root(depth)
+		if s.aborted {
+			break
+		}
+		result.Columns, result.Depth, result.Complete = columns, depth, complete
+		if complete {
+			break
+		}
+	}
+	result.Nodes = s.nodes
+	return result
+}
+
+// centerFirst orders the columns from the center outwards, central columns being part of more lines.
+func centerFirst(cols int) []int {
+	order := make([]int, cols)
+	for i := range order {
+		order[i] = i
+	}
+	distance := func(c int) int { return abs(2*c - (cols - 1)) }
+	slices.SortStableFunc(order, func(a, b int) int { return distance(a) - distance(b) })
+	return order
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func (s *search) root(depth int) ([]ColumnValue, bool) {
+	p := s.pos
+	empty := p.empty()
+	columns := make([]ColumnValue, p.cols)
+	complete := true
+	for c := range columns {
+		columns[c] = ColumnValue{Column: c, Legal: p.canPlay(c)}
+		if !columns[c].Legal {
+			continue
+		}
+		score := empty
+		s.horizon = false
+		if !p.isWinningMove(c) {
+			p.play(c)
+			score = -s.negamax(-empty, empty, depth-1)
+			p.undo(c)
+			if s.aborted {
+				return nil, false
+			}
+		}
+		switch {
+		case score > 0:
+			columns[c].Outcome = Win
+			columns[c].Plies = empty - score + 1
+		case score < 0:
+			columns[c].Outcome = Loss
+			columns[c].Plies = empty + score + 1
+		case s.horizon:
+			columns[c].Outcome = Unknown
+			complete = false
+		default:
+			columns[c].Outcome = Draw
+		}
+		columns[c].Score = score
+	}
+	return columns, complete
+}
+
+// negamax returns the score of the position for the side to move: the number of empty cells left
+// after the winning move plus one for a win, its opposite for a loss and 0 for a draw.
+func (s *search) negamax(alpha, beta, depth int) int {
+	s.nodes++
+	if (s.maxNodes > 0 && s.nodes > s.maxNodes) ||
+		(!s.deadline.IsZero() && s.nodes%4096 == 0 && time.Now().After(s.deadline)) {
+		s.aborted = true
+		return 0
+	}
+	p := s.pos
+	empty := p.empty()
+	if empty == 0 {
+		return 0
+	}
+	for c := range p.cols {
+		if p.canPlay(c) && p.isWinningMove(c) {
+			return empty
+		}
+	}
+	if depth <= 0 {
+		s.horizon = true
+		return 0
+	}
+	threats, forced := 0, -1
+	for c := range p.cols {
+		if p.canPlay(c) && p.opponentWins(c) {
+			threats++
+			forced = c
+		}
+	}
+	if threats > 1 {
+		return -(empty - 1) // can only block one of them
+	}
+	// we can't win right away, at best we win with our next disc
+	if upper := max(empty-2, 0); beta > upper {
+		beta = upper
+		if alpha >= beta {
+			return beta
+		}
+	}
+	key := p.key()
+	e := &s.table[key.hash()%tableSize]
+	if e.used && e.key == key && (!e.limited || int(e.depth) == depth) {
+		if e.limited {
+			s.horizon = true
+		}
+		if upper := int(e.upper); beta > upper {
+			beta = upper
+			if alpha >= beta {
+				return beta
+			}
+		}
+	}
+	horizon := s.horizon
+	s.horizon = false
+	defer func() { s.horizon = s.horizon || horizon }()
+	moves := s.order
+	if forced >= 0 {
+		moves = []int{forced}
+	}
+	for _, c := range moves {
+		if !p.canPlay(c) {
+			continue
+		}
+		p.play(c)
+		score := -s.negamax(-beta, -alpha, depth-1)
+		p.undo(c)
+		if s.aborted {
+			return 0
+		}
+		if score >= beta {
+			return score
+		}
+		alpha = max(alpha, score)
+	}
+	*e = entry{key: key, upper: int16(alpha), depth: int16(depth), limited: s.horizon, used: true} //nolint:gosec // small
+	return alpha
+}
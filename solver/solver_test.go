@@ -0,0 +1,127 @@
+package solver
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/geofpwhite/connect4-grpc/engine"
+)
+
+// bruteForce scores b the way negamax does, exploring the whole tree with the engine.
+func bruteForce(b *engine.Board) int {
+	empty := b.Rows()*b.Cols() - len(b.Moves())
+	best := -empty
+	if len(b.LegalMoves()) == 0 {
+		return 0
+	}
+	for _, c := range b.LegalMoves() {
+		_, _ = b.Drop(c)
+		score := empty
+		if b.Winner() == engine.Empty {
+			score = -bruteForce(b)
+		}
+		_ = b.Undo()
+		best = max(best, score)
+	}
+	return best
+}
+
+func TestMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	variants := []engine.Variant{
+		{Rows: 4, Cols: 4, Connect: 3},
+		{Rows: 4, Cols: 5, Connect: 4},
+		{Rows: 5, Cols: 4, Connect: 3},
+	}
+	for _, v := range variants {
+		for range 20 {
+			b, err := engine.New(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for range v.Rows*v.Cols - 10 {
+				moves := b.LegalMoves()
+				if len(moves) == 0 {
+					break
+				}
+				_, _ = b.Drop(moves[rng.IntN(len(moves))])
+			}
+			analysis := Analyze(b, Options{})
+			if !analysis.Complete {
+				t.Fatalf("%v %v: expected a complete analysis", v, b.Moves())
+			}
+			for _, col := range analysis.Columns {
+				if col.Legal != b.CanDrop(col.Column) {
+					t.Fatalf("%v %v: column %d legal %v", v, b.Moves(), col.Column, col.Legal)
+				}
+				if !col.Legal {
+					continue
+				}
+				_, _ = b.Drop(col.Column)
+				want := b.Rows()*b.Cols() - len(b.Moves()) + 1
+				if b.Winner() == engine.Empty {
+					want = -bruteForce(b)
+				}
+				_ = b.Undo()
+				if col.Score != want {
+					t.Fatalf("%v %v: column %d scored %d, brute force says %d", v, b.Moves(), col.Column, col.Score, want)
+				}
+			}
+		}
+	}
+}
+
+func TestImmediateWin(t *testing.T) {
+	b, err := engine.New(engine.Classic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []int{0, 0, 1, 1, 2, 2} {
+		_, _ = b.Drop(c)
+	}
+	analysis := Analyze(b, Options{MaxDepth: 4})
+	if col := analysis.Columns[3]; col.Outcome != Win || col.Plies != 1 {
+		t.Fatalf("expected column 3 to win right away, got %v in %d", col.Outcome, col.Plies)
+	}
+	// anything else lets yellow block and keeps the game going, not losing within 4 plies
+	if col := analysis.Columns[6]; col.Outcome == Win {
+		t.Fatalf("column 6 should not be a proven win, got %v in %d", col.Outcome, col.Plies)
+	}
+}
+
+func TestBudget(t *testing.T) {
+	b, err := engine.New(engine.Variant{Rows: engine.MaxSize, Cols: engine.MaxSize, Connect: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	analysis := Analyze(b, Options{TimeLimit: 200 * time.Millisecond})
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("analysis took %v", elapsed)
+	}
+	if analysis.Complete {
+		t.Fatal("an empty 16x16 board can't be solved in 200ms")
+	}
+	for _, col := range analysis.Columns {
+		if !col.Legal || col.Outcome != Unknown {
+			t.Fatalf("expected legal unknown columns, got %+v", col)
+		}
+	}
+}
+
+func TestGameOver(t *testing.T) {
+	b := engine.NewBoard()
+	for _, c := range []int{0, 0, 1, 1, 2, 2, 3} {
+		_, _ = b.Drop(c)
+	}
+	analysis := Analyze(b, Options{})
+	if !analysis.Complete {
+		t.Fatal("a finished game has nothing left to analyze")
+	}
+	for _, col := range analysis.Columns {
+		if col.Legal {
+			t.Fatalf("column %d should not be playable", col.Column)
+		}
+	}
+}